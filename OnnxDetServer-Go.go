@@ -2,9 +2,13 @@ package main
 
 import (
 	adhoc "OnnxDetServer/Adhoc"
+	"OnnxDetServer/engine"
 	backend "OnnxDetServer/gRPC"
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/isolation"
 	"OnnxDetServer/logger"
 	"OnnxDetServer/monitor"
+	"OnnxDetServer/webhook"
 	"context"
 	"fmt"
 	"net"
@@ -12,6 +16,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -24,6 +29,20 @@ type configStruct struct {
 	UseRegServer  bool   `yaml:"UseRegServer"`
 	RegServerPort int    `yaml:"RegServerPort"`
 	RegServerHost string `yaml:"RegServerHost"`
+	// Isolation 为 true 时每个引擎（或同一 isolation_group 的引擎）运行在独立子进程中
+	Isolation bool `yaml:"isolation"`
+	// MemoryBudgetMB 大于 0 时，进程 RSS 超出该值后按 LRU 回收空闲引擎
+	MemoryBudgetMB int `yaml:"memoryBudgetMB"`
+	// StateFile 非空时持久化引擎注册表，重启后以原 ID 恢复引擎
+	StateFile string `yaml:"stateFile"`
+	// Engines 列出启动时以固定 ID 预加载的引擎
+	Engines []backend.PreloadEngine `yaml:"engines"`
+	// PreloadPolicy 为 "degrade" 时预加载失败的引擎被跳过，默认（"fail"）直接退出
+	PreloadPolicy string `yaml:"preloadPolicy"`
+	// TrackingSessionTTLSeconds 是 Inference 跟踪会话的空闲过期时间，不大于 0 时为 600 秒
+	TrackingSessionTTLSeconds int `yaml:"trackingSessionTTLSeconds"`
+	// Webhooks 列出推送检测事件的 webhook 接收端
+	Webhooks []webhook.Config `yaml:"webhooks"`
 }
 
 func GetOutboundIP() (string, error) {
@@ -40,7 +59,34 @@ func GetOutboundIP() (string, error) {
 	return localAddr.IP.String(), nil
 }
 
+// runEngineHost 以子进程模式运行：通过专用连接与父进程通信，日志只写标准错误
+func runEngineHost() {
+	if err := logger.InitProduction(); err != nil {
+		os.Exit(1)
+	}
+	conn, err := isolation.DialParent()
+	if err != nil {
+		logger.Log().Error(fmt.Sprintf("engine host failed to connect: %v", err))
+		os.Exit(1)
+	}
+	err = isolation.ServeChild(conn, conn, func() (iface.Backend, error) {
+		detector := &engine.Detector{}
+		if !detector.New() {
+			return nil, fmt.Errorf("failed to create native detector")
+		}
+		return detector, nil
+	})
+	if err != nil {
+		logger.Log().Error(fmt.Sprintf("engine host exited: %v", err))
+		os.Exit(1)
+	}
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == isolation.ChildFlag {
+		runEngineHost()
+		return
+	}
 	ip, err := GetOutboundIP()
 	if err != nil {
 		fmt.Println("Failed to get outbound IP:", err)
@@ -108,9 +154,47 @@ func main() {
 	adhoc.RegServerCfg.SetAddress(config.RegServerHost, config.RegServerPort)
 	backend.JobQueue = make(chan backend.JobPackage, config.WorkersNum)
 	backend.StartWorker(config.WorkersNum)
-	backend.DSequences = make(map[string]backend.WorkerID)
+	backend.DSequences = make(map[string]*backend.WorkerID)
+	if config.Isolation {
+		exePath, err := os.Executable()
+		if err != nil {
+			fmt.Println("Failed to locate executable for engine isolation:", err)
+			return
+		}
+		backend.Isolation = isolation.NewSupervisor(isolation.ExecSpawner(exePath))
+		fmt.Println("Engine isolation enabled, engines run in child processes")
+	}
+	if len(config.Engines) > 0 {
+		fmt.Printf("Preloading %d engines\n", len(config.Engines))
+		err := backend.PreloadEngines(config.Engines, config.PreloadPolicy != "degrade")
+		if err != nil && config.PreloadPolicy != "degrade" {
+			fmt.Println(strings.Repeat("!", 64))
+			fmt.Println("Failed to preload engines:", err)
+			fmt.Println("Set preloadPolicy to \"degrade\" to start without failed engines")
+			fmt.Println(strings.Repeat("!", 64))
+			logger.Log().Error(fmt.Sprintf("Failed to preload engines: %v", err))
+			if backend.Isolation != nil {
+				backend.Isolation.Close()
+			}
+			os.Exit(1)
+		} else if err != nil {
+			fmt.Println(strings.Repeat("!", 64))
+			fmt.Println("Some engines failed to preload, continuing without them:")
+			fmt.Println(err)
+			fmt.Println(strings.Repeat("!", 64))
+		}
+	}
+	backend.EnablePersistence(config.StateFile)
+	if err := backend.RestoreEngines(); err != nil {
+		fmt.Println("Failed to restore engines:", err)
+	}
 	//Adhoc server setup
 	ctx, cancel := context.WithCancel(context.Background())
+	if err := webhook.Start(ctx, config.Webhooks); err != nil {
+		fmt.Println("Failed to start webhooks:", err)
+		cancel()
+		return
+	}
 	wg.Add(1)
 	if config.UseRegServer {
 		go adhoc.SendAliveMessage(ip, config.RPCPort, InstanceClass, ctx, &wg)
@@ -122,9 +206,14 @@ func main() {
 	fmt.Println("Starting gRPC Server")
 	server := backend.StartGRPCServer(config.RPCPort)
 	go monitor.StartMon(config.AdhocPort, ctx)
+	go backend.StartEvictor(ctx, config.MemoryBudgetMB)
+	go backend.StartTrackingSweeper(ctx, time.Duration(config.TrackingSessionTTLSeconds)*time.Second)
 	<-backend.CloseChannel
 	cancel()
 	server.GracefulStop()
+	if backend.Isolation != nil {
+		backend.Isolation.Close()
+	}
 	fmt.Println("Done")
 	wg.Wait()
 	fmt.Println("Safely exited")
@@ -4,9 +4,13 @@ import (
 	iface "OnnxDetServer/interface"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"unsafe"
+
+	"gopkg.in/yaml.v3"
 )
 
 const UNREGISTERED = 0x0001
@@ -15,25 +19,93 @@ const IDLE = 0x0003
 const BUSY = 0x0004
 const SingleThread = 0x1001
 const MultiThread = 0x1002
+const Ensemble = 0x1003
 
 func ReadLinesReadFile(path string) ([]string, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	// 支持 Windows CRLF，去掉尾部的 '\r'
+	// 支持 Windows CRLF，去掉尾部的 '\r'，并跳过空行
 	raw := strings.Split(string(b), "\n")
-	for i := range raw {
-		raw[i] = strings.TrimRight(raw[i], "\r")
-	}
-	//TIP 如果文件末尾有空行，可以按需过滤：
 	var lines []string
 	for _, l := range raw {
+		l = strings.TrimSpace(l)
 		if l != "" {
 			lines = append(lines, l)
 		}
 	}
-	return raw, nil
+	return lines, nil
+}
+
+// ReadNamesFile 读取类别名文件：.yaml / .yml / .json 可以是类别名列表、{id: name} 映射，
+// 或带 names 键的数据集配置；其他扩展名按每行一个类别名读取
+func ReadNamesFile(path string) ([]string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return ReadLinesReadFile(path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// JSON 是 YAML 的子集，统一按 YAML 解析
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse names file %s: %w", path, err)
+	}
+	if m, ok := doc.(map[string]any); ok {
+		if inner, ok := m["names"]; ok {
+			doc = inner
+		}
+	}
+	names, err := namesFrom(doc)
+	if err != nil {
+		return nil, fmt.Errorf("names file %s: %w", path, err)
+	}
+	return names, nil
+}
+
+// namesFrom 把列表或 {id: name} 映射转换为按类别序号排列的类别名，序号必须从 0 开始连续
+func namesFrom(doc any) ([]string, error) {
+	entries := make(map[any]any)
+	switch v := doc.(type) {
+	case []any:
+		for i, n := range v {
+			entries[i] = n
+		}
+	case map[string]any:
+		for k, n := range v {
+			entries[k] = n
+		}
+	case map[any]any:
+		entries = v
+	default:
+		return nil, fmt.Errorf("expected a list of names or an {id: name} map")
+	}
+	names := make([]string, len(entries))
+	for k, v := range entries {
+		idx, ok := k.(int)
+		if s, isStr := k.(string); isStr {
+			n, err := strconv.Atoi(s)
+			idx, ok = n, err == nil
+		}
+		if !ok || idx < 0 || idx >= len(names) {
+			return nil, fmt.Errorf("class ids must be 0..%d, got %v", len(names)-1, k)
+		}
+		name, ok := v.(string)
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("class %d has an invalid name %v", idx, v)
+		}
+		names[idx] = name
+	}
+	return names, nil
+}
+
+// BackendName 返回 backend.yaml 中配置的推理后端（onnx / ncnn）
+func BackendName() string {
+	return backendCfg.UseBackend
 }
 
 type Detector struct {
@@ -68,7 +140,11 @@ func (d *Detector) CheckConfig() iface.EngineConfig {
 
 func (d *Detector) LoadModel(modelPath string, names iface.NamesConf, conf float32, iou float32, useGPU bool) (bool, error) {
 	if names.IsFile {
-		d.Names, _ = ReadLinesReadFile(names.Data.(string))
+		fileNames, err := ReadNamesFile(names.Data.(string))
+		if err != nil {
+			return false, fmt.Errorf("failed to read names file: %w", err)
+		}
+		d.Names = fileNames
 	} else {
 		rv := reflect.ValueOf(names.Data)
 		if rv.Kind() != reflect.Slice {
@@ -154,13 +230,21 @@ func (d *Detector) Detect(img iface.ImageData) iface.RetData {
 			Box:    box,
 			Center: center,
 		}
-		className := d.Names[classIdx]
+		className := d.className(classIdx)
 		resultDict[className] = append(resultDict[className], res)
 	}
 	d.State = IDLE
 	return iface.RetData{Success: true, Data: resultDict}
 }
 
+// className 返回类别序号对应的类别名，超出类别名列表的序号映射为 class_<n>
+func (d *Detector) className(idx int) string {
+	if idx < 0 || idx >= len(d.Names) {
+		return fmt.Sprintf("class_%d", idx)
+	}
+	return d.Names[idx]
+}
+
 func (d *Detector) SetInputSize(size int) {
 	SetInputSize(d.Instance, size)
 }
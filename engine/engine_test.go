@@ -2,6 +2,8 @@ package engine
 
 import (
 	iface "OnnxDetServer/interface"
+	"os"
+	"path/filepath"
 	"testing"
 	"unsafe"
 
@@ -68,3 +70,51 @@ func TestDetector_All(t *testing.T) {
 		assert.Equal(t, d.State, UNREGISTERED)
 	})
 }
+
+func TestReadNamesFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	names, err := ReadNamesFile(write("coco.names", "person\r\n\r\ncar\n  \nbicycle\n\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"person", "car", "bicycle"}, names)
+
+	names, err = ReadNamesFile(write("map.yaml", "1: car\n0: person\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"person", "car"}, names)
+
+	names, err = ReadNamesFile(write("map.json", `{"0": "person", "1": "car"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"person", "car"}, names)
+
+	names, err = ReadNamesFile(write("dataset.yaml", "path: ../datasets/coco\nnames:\n  0: person\n  1: car\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"person", "car"}, names)
+
+	names, err = ReadNamesFile(write("list.yml", "[person, car]"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"person", "car"}, names)
+
+	for content, msg := range map[string]string{
+		"0: person\n2: car\n": "class ids must be",
+		"0: person\n1: ''\n":  "invalid name",
+		"person":              "expected a list",
+		`{"0": "a", 0: "b"}`:  "already defined",
+	} {
+		_, err := ReadNamesFile(write("bad.yaml", content))
+		assert.ErrorContains(t, err, msg, content)
+	}
+	_, err = ReadNamesFile(filepath.Join(dir, "missing.names"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestClassNameOutOfRange(t *testing.T) {
+	d := &Detector{Names: []string{"person", "car"}}
+	assert.Equal(t, "car", d.className(1))
+	assert.Equal(t, "class_2", d.className(2))
+	assert.Equal(t, "class_-1", d.className(-1))
+}
@@ -0,0 +1,145 @@
+// Package fusion 合并多个检测模型对同一张图片的结果，支持 Weighted Boxes Fusion 与 NMS
+package fusion
+
+import (
+	iface "OnnxDetServer/interface"
+	"cmp"
+	"slices"
+)
+
+const (
+	MethodWBF = "wbf"
+	MethodNMS = "nms"
+)
+
+type scored struct {
+	res    iface.Result
+	score  float32
+	weight float32
+}
+
+// NewResult 由左上、右下坐标构造检测结果
+func NewResult(x1, y1, x2, y2, conf float32) iface.Result {
+	return iface.Result{
+		Conf: conf,
+		Box: iface.Box{
+			LT: iface.Position{X: x1, Y: y1},
+			RT: iface.Position{X: x2, Y: y1},
+			RB: iface.Position{X: x2, Y: y2},
+			LB: iface.Position{X: x1, Y: y2},
+		},
+		Center: iface.Position{X: (x1 + x2) / 2, Y: (y1 + y2) / 2},
+	}
+}
+
+// collect 按类别收集所有模型的结果，置信度乘以模型权重，丢弃低于 skipThr 的结果，按分数降序排列
+func collect(results []map[string][]iface.Result, weights []float32, skipThr float32) map[string][]scored {
+	byClass := make(map[string][]scored)
+	for i, set := range results {
+		w := float32(1)
+		if i < len(weights) {
+			w = weights[i]
+		}
+		for class, list := range set {
+			for _, r := range list {
+				if r.Conf < skipThr {
+					continue
+				}
+				byClass[class] = append(byClass[class], scored{res: r, score: r.Conf * w, weight: w})
+			}
+		}
+	}
+	for _, list := range byClass {
+		slices.SortStableFunc(list, func(a, b scored) int {
+			return cmp.Compare(b.score, a.score)
+		})
+	}
+	return byClass
+}
+
+// WeightedBoxesFusion 对每个类别把 IoU 超过 iouThr 的检测框聚成一簇，以置信度为权重平均坐标。
+// 融合后的置信度为簇内置信度按模型权重的加权平均，再乘以检出该框的模型数占模型总数的比例，
+// 只被少数模型检出的框置信度更低
+func WeightedBoxesFusion(results []map[string][]iface.Result, weights []float32, iouThr, skipThr float32) map[string][]iface.Result {
+	models := float32(len(results))
+	fused := make(map[string][]iface.Result)
+	for class, list := range collect(results, weights, skipThr) {
+		var clusters [][]scored
+		var boxes []iface.Result
+		for _, s := range list {
+			best, bestIoU := -1, iouThr
+			for i, b := range boxes {
+				if iou := b.Box.IoU(s.res.Box); iou > bestIoU {
+					best, bestIoU = i, iou
+				}
+			}
+			if best < 0 {
+				clusters = append(clusters, []scored{s})
+				boxes = append(boxes, s.res)
+				continue
+			}
+			clusters[best] = append(clusters[best], s)
+			boxes[best] = average(clusters[best])
+		}
+		for i, c := range clusters {
+			b := boxes[i]
+			var sum, weightSum float32
+			for _, s := range c {
+				sum += s.score
+				weightSum += s.weight
+			}
+			if weightSum > 0 {
+				b.Conf = sum / weightSum * min(float32(len(c)), models) / models
+			}
+			fused[class] = append(fused[class], b)
+		}
+	}
+	return fused
+}
+
+// average 以分数为权重平均簇内检测框的坐标
+func average(c []scored) iface.Result {
+	var x1, y1, x2, y2, total float32
+	for _, s := range c {
+		x1 += s.res.Box.LT.X * s.score
+		y1 += s.res.Box.LT.Y * s.score
+		x2 += s.res.Box.RB.X * s.score
+		y2 += s.res.Box.RB.Y * s.score
+		total += s.score
+	}
+	if total == 0 {
+		return c[0].res
+	}
+	return NewResult(x1/total, y1/total, x2/total, y2/total, 0)
+}
+
+// NMS 合并所有模型的结果后按类别做非极大值抑制，保留的结果置信度为加权分数除以最大的模型权重，
+// 权重大于 1 时置信度也不会超过 1
+func NMS(results []map[string][]iface.Result, weights []float32, iouThr, skipThr float32) map[string][]iface.Result {
+	norm := float32(0)
+	for i := range results {
+		w := float32(1)
+		if i < len(weights) {
+			w = weights[i]
+		}
+		norm = max(norm, w)
+	}
+	kept := make(map[string][]iface.Result)
+	for class, list := range collect(results, weights, skipThr) {
+		for _, s := range list {
+			suppressed := false
+			for _, k := range kept[class] {
+				if k.Box.IoU(s.res.Box) > iouThr {
+					suppressed = true
+					break
+				}
+			}
+			if !suppressed {
+				r := s.res
+				r.Conf = min(s.score/norm, 1)
+				kept[class] = append(kept[class], r)
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,76 @@
+package fusion
+
+import (
+	iface "OnnxDetServer/interface"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedBoxesFusion(t *testing.T) {
+	a := map[string][]iface.Result{
+		"person": {NewResult(0, 0, 10, 10, 0.9)},
+		"car":    {NewResult(50, 50, 60, 60, 0.6)},
+	}
+	b := map[string][]iface.Result{
+		"person": {NewResult(1, 1, 11, 11, 0.9), NewResult(100, 100, 110, 110, 0.4)},
+	}
+	fused := WeightedBoxesFusion([]map[string][]iface.Result{a, b}, []float32{1, 1}, 0.5, 0.1)
+	if assert.Len(t, fused["person"], 2) {
+		p := fused["person"][0]
+		assert.InDelta(t, 0.5, p.Box.LT.X, 1e-5)
+		assert.InDelta(t, 10.5, p.Box.RB.Y, 1e-5)
+		assert.InDelta(t, 0.9, p.Conf, 1e-5)
+		assert.InDelta(t, 5.5, p.Center.X, 1e-5)
+		// 只有一个模型检出的框置信度减半
+		assert.InDelta(t, 0.2, fused["person"][1].Conf, 1e-5)
+	}
+	if assert.Len(t, fused["car"], 1) {
+		assert.InDelta(t, 0.3, fused["car"][0].Conf, 1e-5)
+	}
+
+	skipped := WeightedBoxesFusion([]map[string][]iface.Result{a, b}, nil, 0.5, 0.5)
+	assert.Len(t, skipped["person"], 1)
+}
+
+func TestWeightedBoxesFusionWeights(t *testing.T) {
+	a := map[string][]iface.Result{
+		"person": {NewResult(0, 0, 10, 10, 0.9)},
+		"car":    {NewResult(50, 50, 60, 60, 0.6)},
+	}
+	b := map[string][]iface.Result{"person": {NewResult(0, 0, 10, 10, 0.3)}}
+	fused := WeightedBoxesFusion([]map[string][]iface.Result{a, b}, []float32{2, 1}, 0.5, 0)
+	if assert.Len(t, fused["person"], 1) {
+		// 两个模型都检出时为按权重的加权平均
+		assert.InDelta(t, 0.7, fused["person"][0].Conf, 1e-5)
+	}
+	if assert.Len(t, fused["car"], 1) {
+		// 只有一个模型检出时按模型数缩放，与权重大小无关
+		assert.InDelta(t, 0.3, fused["car"][0].Conf, 1e-5)
+	}
+	small := WeightedBoxesFusion([]map[string][]iface.Result{a, b}, []float32{0.25, 0.25}, 0.5, 0)
+	assert.InDelta(t, 0.6, small["person"][0].Conf, 1e-5)
+	assert.InDelta(t, 0.3, small["car"][0].Conf, 1e-5)
+}
+
+func TestNMS(t *testing.T) {
+	a := map[string][]iface.Result{"person": {NewResult(0, 0, 10, 10, 0.8)}}
+	b := map[string][]iface.Result{"person": {NewResult(1, 1, 10, 10, 0.9), NewResult(20, 20, 30, 30, 0.5)}}
+	kept := NMS([]map[string][]iface.Result{a, b}, []float32{1, 1}, 0.5, 0)
+	if assert.Len(t, kept["person"], 2) {
+		assert.InDelta(t, 0.9, kept["person"][0].Conf, 1e-5)
+		assert.InDelta(t, 1, kept["person"][0].Box.LT.X, 1e-5)
+	}
+	weighted := NMS([]map[string][]iface.Result{a, b}, []float32{1, 0.5}, 0.5, 0)
+	assert.InDelta(t, 0.8, weighted["person"][0].Conf, 1e-5)
+
+	// 权重大于 1 时按最大权重归一化，置信度不超过 1
+	heavy := NMS([]map[string][]iface.Result{a, b}, []float32{3, 2}, 0.5, 0)
+	if assert.Len(t, heavy["person"], 2) {
+		assert.InDelta(t, 0.8, heavy["person"][0].Conf, 1e-5)
+		assert.InDelta(t, 0.5*2.0/3.0, heavy["person"][1].Conf, 1e-5)
+		for _, r := range heavy["person"] {
+			assert.LessOrEqual(t, r.Conf, float32(1))
+		}
+	}
+}
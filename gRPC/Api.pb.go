@@ -22,16 +22,82 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type EngineState int32
+
+const (
+	EngineState_ENGINE_STATE_ACTIVE    EngineState = 0
+	EngineState_ENGINE_STATE_DRAINING  EngineState = 1
+	EngineState_ENGINE_STATE_DESTROYED EngineState = 2
+)
+
+// Enum value maps for EngineState.
+var (
+	EngineState_name = map[int32]string{
+		0: "ENGINE_STATE_ACTIVE",
+		1: "ENGINE_STATE_DRAINING",
+		2: "ENGINE_STATE_DESTROYED",
+	}
+	EngineState_value = map[string]int32{
+		"ENGINE_STATE_ACTIVE":    0,
+		"ENGINE_STATE_DRAINING":  1,
+		"ENGINE_STATE_DESTROYED": 2,
+	}
+)
+
+func (x EngineState) Enum() *EngineState {
+	p := new(EngineState)
+	*p = x
+	return p
+}
+
+func (x EngineState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EngineState) Descriptor() protoreflect.EnumDescriptor {
+	return file_Api_proto_enumTypes[0].Descriptor()
+}
+
+func (EngineState) Type() protoreflect.EnumType {
+	return &file_Api_proto_enumTypes[0]
+}
+
+func (x EngineState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EngineState.Descriptor instead.
+func (EngineState) EnumDescriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{0}
+}
+
 type EngineInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	EngineType    int32                  `protobuf:"varint,3,opt,name=engine_type,json=engineType,proto3" json:"engine_type,omitempty"`
-	ModelPath     string                 `protobuf:"bytes,4,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
-	Names         []string               `protobuf:"bytes,5,rep,name=names,proto3" json:"names,omitempty"`
-	Confidence    float32                `protobuf:"fixed32,6,opt,name=confidence,proto3" json:"confidence,omitempty"`
-	Iou           float32                `protobuf:"fixed32,7,opt,name=iou,proto3" json:"iou,omitempty"`
-	UseGpu        bool                   `protobuf:"varint,8,opt,name=use_gpu,json=useGpu,proto3" json:"use_gpu,omitempty"`
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Description        string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	EngineType         int32                  `protobuf:"varint,3,opt,name=engine_type,json=engineType,proto3" json:"engine_type,omitempty"`
+	ModelPath          string                 `protobuf:"bytes,4,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
+	Names              []string               `protobuf:"bytes,5,rep,name=names,proto3" json:"names,omitempty"`
+	Confidence         float32                `protobuf:"fixed32,6,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Iou                float32                `protobuf:"fixed32,7,opt,name=iou,proto3" json:"iou,omitempty"`
+	UseGpu             bool                   `protobuf:"varint,8,opt,name=use_gpu,json=useGpu,proto3" json:"use_gpu,omitempty"`
+	State              EngineState            `protobuf:"varint,9,opt,name=state,proto3,enum=proto.EngineState" json:"state,omitempty"`
+	InFlight           int32                  `protobuf:"varint,10,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`
+	IdleTtlSeconds     int32                  `protobuf:"varint,11,opt,name=idle_ttl_seconds,json=idleTtlSeconds,proto3" json:"idle_ttl_seconds,omitempty"`
+	MemoryEstimateMb   int64                  `protobuf:"varint,12,opt,name=memory_estimate_mb,json=memoryEstimateMb,proto3" json:"memory_estimate_mb,omitempty"`
+	LastUsedUnixMs     int64                  `protobuf:"varint,13,opt,name=last_used_unix_ms,json=lastUsedUnixMs,proto3" json:"last_used_unix_ms,omitempty"`
+	LeaseSeconds       int32                  `protobuf:"varint,14,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`
+	LeaseExpiresUnixMs int64                  `protobuf:"varint,15,opt,name=lease_expires_unix_ms,json=leaseExpiresUnixMs,proto3" json:"lease_expires_unix_ms,omitempty"`
+	Shared             bool                   `protobuf:"varint,16,opt,name=shared,proto3" json:"shared,omitempty"`
+	Fingerprint        string                 `protobuf:"bytes,17,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	ShareCount         int32                  `protobuf:"varint,18,opt,name=share_count,json=shareCount,proto3" json:"share_count,omitempty"`
+	// 由 config.yaml 的 engines 列表在启动时加载
+	Preloaded bool `protobuf:"varint,19,opt,name=preloaded,proto3" json:"preloaded,omitempty"`
+	// 配置版本号，创建时为 1，每次 UpdateEngine / ReloadEngine 成功后加 1
+	ConfigRevision int64 `protobuf:"varint,20,opt,name=config_revision,json=configRevision,proto3" json:"config_revision,omitempty"`
+	// 集成引擎的成员引擎 ID 与融合方法
+	Members       []string `protobuf:"bytes,21,rep,name=members,proto3" json:"members,omitempty"`
+	FusionMethod  string   `protobuf:"bytes,22,opt,name=fusion_method,json=fusionMethod,proto3" json:"fusion_method,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -122,6 +188,104 @@ func (x *EngineInfo) GetUseGpu() bool {
 	return false
 }
 
+func (x *EngineInfo) GetState() EngineState {
+	if x != nil {
+		return x.State
+	}
+	return EngineState_ENGINE_STATE_ACTIVE
+}
+
+func (x *EngineInfo) GetInFlight() int32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetIdleTtlSeconds() int32 {
+	if x != nil {
+		return x.IdleTtlSeconds
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetMemoryEstimateMb() int64 {
+	if x != nil {
+		return x.MemoryEstimateMb
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetLastUsedUnixMs() int64 {
+	if x != nil {
+		return x.LastUsedUnixMs
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetLeaseSeconds() int32 {
+	if x != nil {
+		return x.LeaseSeconds
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetLeaseExpiresUnixMs() int64 {
+	if x != nil {
+		return x.LeaseExpiresUnixMs
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+func (x *EngineInfo) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *EngineInfo) GetShareCount() int32 {
+	if x != nil {
+		return x.ShareCount
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetPreloaded() bool {
+	if x != nil {
+		return x.Preloaded
+	}
+	return false
+}
+
+func (x *EngineInfo) GetConfigRevision() int64 {
+	if x != nil {
+		return x.ConfigRevision
+	}
+	return 0
+}
+
+func (x *EngineInfo) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *EngineInfo) GetFusionMethod() string {
+	if x != nil {
+		return x.FusionMethod
+	}
+	return ""
+}
+
 type Position struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
@@ -175,11 +339,14 @@ func (x *Position) GetY() int32 {
 }
 
 type SingleResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Confidence    float32                `protobuf:"fixed32,2,opt,name=confidence,proto3" json:"confidence,omitempty"`
-	Box           []*Position            `protobuf:"bytes,3,rep,name=box,proto3" json:"box,omitempty"`
-	Center        *Position              `protobuf:"bytes,4,opt,name=center,proto3" json:"center,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Name       string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Confidence float32                `protobuf:"fixed32,2,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Box        []*Position            `protobuf:"bytes,3,rep,name=box,proto3" json:"box,omitempty"`
+	Center     *Position              `protobuf:"bytes,4,opt,name=center,proto3" json:"center,omitempty"`
+	// 请求携带跟踪会话时为检测所属的轨迹 ID（从 1 开始）与轨迹已存在的帧数；未关联到轨迹的检测为 0
+	TrackId       int64 `protobuf:"varint,5,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	TrackAge      int32 `protobuf:"varint,6,opt,name=track_age,json=trackAge,proto3" json:"track_age,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -242,16 +409,44 @@ func (x *SingleResult) GetCenter() *Position {
 	return nil
 }
 
+func (x *SingleResult) GetTrackId() int64 {
+	if x != nil {
+		return x.TrackId
+	}
+	return 0
+}
+
+func (x *SingleResult) GetTrackAge() int32 {
+	if x != nil {
+		return x.TrackAge
+	}
+	return 0
+}
+
 type InitEngineRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	EngineType    int32                  `protobuf:"varint,1,opt,name=engine_type,json=engineType,proto3" json:"engine_type,omitempty"`
-	ModelPath     string                 `protobuf:"bytes,2,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
-	Names         []string               `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty"`
-	InputSize     int32                  `protobuf:"varint,4,opt,name=input_size,json=inputSize,proto3" json:"input_size,omitempty"`
-	Confidence    float32                `protobuf:"fixed32,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
-	Iou           float32                `protobuf:"fixed32,6,opt,name=iou,proto3" json:"iou,omitempty"`
-	UseGpu        bool                   `protobuf:"varint,7,opt,name=use_gpu,json=useGpu,proto3" json:"use_gpu,omitempty"`
-	Description   string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EngineType     int32                  `protobuf:"varint,1,opt,name=engine_type,json=engineType,proto3" json:"engine_type,omitempty"`
+	ModelPath      string                 `protobuf:"bytes,2,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
+	Names          []string               `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty"`
+	InputSize      int32                  `protobuf:"varint,4,opt,name=input_size,json=inputSize,proto3" json:"input_size,omitempty"`
+	Confidence     float32                `protobuf:"fixed32,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Iou            float32                `protobuf:"fixed32,6,opt,name=iou,proto3" json:"iou,omitempty"`
+	UseGpu         bool                   `protobuf:"varint,7,opt,name=use_gpu,json=useGpu,proto3" json:"use_gpu,omitempty"`
+	Description    string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	IsolationGroup string                 `protobuf:"bytes,9,opt,name=isolation_group,json=isolationGroup,proto3" json:"isolation_group,omitempty"`
+	// 空闲超过该秒数的引擎会被自动回收，0 表示不回收
+	IdleTtlSeconds int32 `protobuf:"varint,10,opt,name=idle_ttl_seconds,json=idleTtlSeconds,proto3" json:"idle_ttl_seconds,omitempty"`
+	// 大于 0 时引擎持有租约，客户端需在到期前调用 RenewLease 续约，否则引擎被销毁
+	LeaseSeconds int32 `protobuf:"varint,11,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`
+	// 为 true 时与配置指纹相同的其他共享引擎复用同一个原生实例，每个客户端仍获得独立的引擎 ID
+	Share bool `protobuf:"varint,12,opt,name=share,proto3" json:"share,omitempty"`
+	// 非空时，相同键的重试请求直接返回已创建的引擎 ID
+	IdempotencyKey string `protobuf:"bytes,13,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// 非空时覆盖模型默认的输入/输出 blob 名称
+	InputBlob  string `protobuf:"bytes,14,opt,name=input_blob,json=inputBlob,proto3" json:"input_blob,omitempty"`
+	OutputBlob string `protobuf:"bytes,15,opt,name=output_blob,json=outputBlob,proto3" json:"output_blob,omitempty"`
+	// 类别名文件（仓库中的文件名或路径），每行一个类别名，或 YAML / JSON 的列表、{id: name} 映射；不能与 names 同时设置
+	NamesFile     string `protobuf:"bytes,16,opt,name=names_file,json=namesFile,proto3" json:"names_file,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -342,6 +537,62 @@ func (x *InitEngineRequest) GetDescription() string {
 	return ""
 }
 
+func (x *InitEngineRequest) GetIsolationGroup() string {
+	if x != nil {
+		return x.IsolationGroup
+	}
+	return ""
+}
+
+func (x *InitEngineRequest) GetIdleTtlSeconds() int32 {
+	if x != nil {
+		return x.IdleTtlSeconds
+	}
+	return 0
+}
+
+func (x *InitEngineRequest) GetLeaseSeconds() int32 {
+	if x != nil {
+		return x.LeaseSeconds
+	}
+	return 0
+}
+
+func (x *InitEngineRequest) GetShare() bool {
+	if x != nil {
+		return x.Share
+	}
+	return false
+}
+
+func (x *InitEngineRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *InitEngineRequest) GetInputBlob() string {
+	if x != nil {
+		return x.InputBlob
+	}
+	return ""
+}
+
+func (x *InitEngineRequest) GetOutputBlob() string {
+	if x != nil {
+		return x.OutputBlob
+	}
+	return ""
+}
+
+func (x *InitEngineRequest) GetNamesFile() string {
+	if x != nil {
+		return x.NamesFile
+	}
+	return ""
+}
+
 type InitEngineResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -471,9 +722,16 @@ func (x *ImageData) GetChannels() int32 {
 }
 
 type InferenceRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ImgData       *ImageData             `protobuf:"bytes,2,opt,name=img_data,json=imgData,proto3" json:"img_data,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 引擎 UUID 或别名
+	Id      string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ImgData *ImageData `protobuf:"bytes,2,opt,name=img_data,json=imgData,proto3" json:"img_data,omitempty"`
+	// 非空时在该跟踪会话中为检测分配轨迹 ID，同一会话的请求需按帧顺序串行发送
+	SessionId string `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// 跟踪参数，只在会话创建时生效
+	Tracker *TrackerConfig `protobuf:"bytes,4,opt,name=tracker,proto3" json:"tracker,omitempty"`
+	// 会话的区域规则，只在会话创建时生效，优先于引擎的区域规则；需要同时设置 session_id
+	Zones         *ZoneConfig `protobuf:"bytes,5,opt,name=zones,proto3" json:"zones,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -522,28 +780,59 @@ func (x *InferenceRequest) GetImgData() *ImageData {
 	return nil
 }
 
-type InferenceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Results       []*SingleResult        `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+func (x *InferenceRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *InferenceRequest) GetTracker() *TrackerConfig {
+	if x != nil {
+		return x.Tracker
+	}
+	return nil
+}
+
+func (x *InferenceRequest) GetZones() *ZoneConfig {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+type TrackerConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// bytetrack（默认）或 sort
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// 高置信度阈值，只有高置信度检测能创建轨迹，默认 0.5
+	HighThresh float32 `protobuf:"fixed32,2,opt,name=high_thresh,json=highThresh,proto3" json:"high_thresh,omitempty"`
+	// 低于该阈值的检测不参与跟踪，默认 0.1；ByteTrack 用两阈值之间的检测延续已有轨迹
+	LowThresh float32 `protobuf:"fixed32,3,opt,name=low_thresh,json=lowThresh,proto3" json:"low_thresh,omitempty"`
+	// 检测框与轨迹预测框关联所需的最小 IoU，默认 0.3
+	MatchIou float32 `protobuf:"fixed32,4,opt,name=match_iou,json=matchIou,proto3" json:"match_iou,omitempty"`
+	// 轨迹连续未匹配多少帧后判定丢失，默认 30
+	MaxLost int32 `protobuf:"varint,5,opt,name=max_lost,json=maxLost,proto3" json:"max_lost,omitempty"`
+	// 为 true 时允许不同类别的检测延续同一轨迹
+	CrossClass    bool `protobuf:"varint,6,opt,name=cross_class,json=crossClass,proto3" json:"cross_class,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InferenceResponse) Reset() {
-	*x = InferenceResponse{}
+func (x *TrackerConfig) Reset() {
+	*x = TrackerConfig{}
 	mi := &file_Api_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InferenceResponse) String() string {
+func (x *TrackerConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InferenceResponse) ProtoMessage() {}
+func (*TrackerConfig) ProtoMessage() {}
 
-func (x *InferenceResponse) ProtoReflect() protoreflect.Message {
+func (x *TrackerConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_Api_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -555,46 +844,78 @@ func (x *InferenceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InferenceResponse.ProtoReflect.Descriptor instead.
-func (*InferenceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TrackerConfig.ProtoReflect.Descriptor instead.
+func (*TrackerConfig) Descriptor() ([]byte, []int) {
 	return file_Api_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *InferenceResponse) GetSuccess() bool {
+func (x *TrackerConfig) GetMethod() string {
 	if x != nil {
-		return x.Success
+		return x.Method
 	}
-	return false
+	return ""
 }
 
-func (x *InferenceResponse) GetResults() []*SingleResult {
+func (x *TrackerConfig) GetHighThresh() float32 {
 	if x != nil {
-		return x.Results
+		return x.HighThresh
 	}
-	return nil
+	return 0
 }
 
-type DestroyEngineRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+func (x *TrackerConfig) GetLowThresh() float32 {
+	if x != nil {
+		return x.LowThresh
+	}
+	return 0
+}
+
+func (x *TrackerConfig) GetMatchIou() float32 {
+	if x != nil {
+		return x.MatchIou
+	}
+	return 0
+}
+
+func (x *TrackerConfig) GetMaxLost() int32 {
+	if x != nil {
+		return x.MaxLost
+	}
+	return 0
+}
+
+func (x *TrackerConfig) GetCrossClass() bool {
+	if x != nil {
+		return x.CrossClass
+	}
+	return false
+}
+
+type TrackEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// new 或 lost
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	TrackId       int64  `protobuf:"varint,2,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Age           int32  `protobuf:"varint,4,opt,name=age,proto3" json:"age,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DestroyEngineRequest) Reset() {
-	*x = DestroyEngineRequest{}
+func (x *TrackEvent) Reset() {
+	*x = TrackEvent{}
 	mi := &file_Api_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DestroyEngineRequest) String() string {
+func (x *TrackEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DestroyEngineRequest) ProtoMessage() {}
+func (*TrackEvent) ProtoMessage() {}
 
-func (x *DestroyEngineRequest) ProtoReflect() protoreflect.Message {
+func (x *TrackEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_Api_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -606,40 +927,66 @@ func (x *DestroyEngineRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DestroyEngineRequest.ProtoReflect.Descriptor instead.
-func (*DestroyEngineRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TrackEvent.ProtoReflect.Descriptor instead.
+func (*TrackEvent) Descriptor() ([]byte, []int) {
 	return file_Api_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *DestroyEngineRequest) GetId() string {
+func (x *TrackEvent) GetType() string {
 	if x != nil {
-		return x.Id
+		return x.Type
 	}
 	return ""
 }
 
-type DestroyEngineResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+func (x *TrackEvent) GetTrackId() int64 {
+	if x != nil {
+		return x.TrackId
+	}
+	return 0
+}
+
+func (x *TrackEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TrackEvent) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+type ZoneRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// 多边形顶点（像素坐标），至少 3 个
+	Polygon []*Position `protobuf:"bytes,2,rep,name=polygon,proto3" json:"polygon,omitempty"`
+	// 只统计这些类别，为空时统计所有类别
+	Classes []string `protobuf:"bytes,3,rep,name=classes,proto3" json:"classes,omitempty"`
+	// 大于 0 时，轨迹在区域内停留超过该毫秒数时产生一次 dwell 事件
+	DwellAlertMs  int64 `protobuf:"varint,4,opt,name=dwell_alert_ms,json=dwellAlertMs,proto3" json:"dwell_alert_ms,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DestroyEngineResponse) Reset() {
-	*x = DestroyEngineResponse{}
+func (x *ZoneRule) Reset() {
+	*x = ZoneRule{}
 	mi := &file_Api_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DestroyEngineResponse) String() string {
+func (x *ZoneRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DestroyEngineResponse) ProtoMessage() {}
+func (*ZoneRule) ProtoMessage() {}
 
-func (x *DestroyEngineResponse) ProtoReflect() protoreflect.Message {
+func (x *ZoneRule) ProtoReflect() protoreflect.Message {
 	mi := &file_Api_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -651,93 +998,4208 @@ func (x *DestroyEngineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DestroyEngineResponse.ProtoReflect.Descriptor instead.
-func (*DestroyEngineResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ZoneRule.ProtoReflect.Descriptor instead.
+func (*ZoneRule) Descriptor() ([]byte, []int) {
 	return file_Api_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *DestroyEngineResponse) GetSuccess() bool {
+func (x *ZoneRule) GetName() string {
 	if x != nil {
-		return x.Success
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-func (x *DestroyEngineResponse) GetMessage() string {
+func (x *ZoneRule) GetPolygon() []*Position {
 	if x != nil {
-		return x.Message
+		return x.Polygon
 	}
-	return ""
+	return nil
+}
+
+func (x *ZoneRule) GetClasses() []string {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+func (x *ZoneRule) GetDwellAlertMs() int64 {
+	if x != nil {
+		return x.DwellAlertMs
+	}
+	return 0
+}
+
+type LineRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// 有向线段 from→to；图像坐标系中从左侧穿到右侧为 forward，反之为 backward
+	From          *Position `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *Position `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Classes       []string  `protobuf:"bytes,4,rep,name=classes,proto3" json:"classes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineRule) Reset() {
+	*x = LineRule{}
+	mi := &file_Api_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineRule) ProtoMessage() {}
+
+func (x *LineRule) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineRule.ProtoReflect.Descriptor instead.
+func (*LineRule) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LineRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LineRule) GetFrom() *Position {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *LineRule) GetTo() *Position {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *LineRule) GetClasses() []string {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+type ZoneConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zones         []*ZoneRule            `protobuf:"bytes,1,rep,name=zones,proto3" json:"zones,omitempty"`
+	Lines         []*LineRule            `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZoneConfig) Reset() {
+	*x = ZoneConfig{}
+	mi := &file_Api_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZoneConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZoneConfig) ProtoMessage() {}
+
+func (x *ZoneConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZoneConfig.ProtoReflect.Descriptor instead.
+func (*ZoneConfig) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ZoneConfig) GetZones() []*ZoneRule {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *ZoneConfig) GetLines() []*LineRule {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type ZoneCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Zone  string                 `protobuf:"bytes,1,opt,name=zone,proto3" json:"zone,omitempty"`
+	Count int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// 按类别的目标数
+	Classes       map[string]int32 `protobuf:"bytes,3,rep,name=classes,proto3" json:"classes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZoneCount) Reset() {
+	*x = ZoneCount{}
+	mi := &file_Api_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZoneCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZoneCount) ProtoMessage() {}
+
+func (x *ZoneCount) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZoneCount.ProtoReflect.Descriptor instead.
+func (*ZoneCount) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ZoneCount) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *ZoneCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ZoneCount) GetClasses() map[string]int32 {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+type ZoneEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enter、exit、dwell 或 cross
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// 区域或线的名称
+	Zone    string `protobuf:"bytes,2,opt,name=zone,proto3" json:"zone,omitempty"`
+	TrackId int64  `protobuf:"varint,3,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	Name    string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// cross 事件的方向：forward 或 backward
+	Direction string `protobuf:"bytes,5,opt,name=direction,proto3" json:"direction,omitempty"`
+	// exit 与 dwell 事件中轨迹在区域内已停留的毫秒数
+	DwellMs       int64 `protobuf:"varint,6,opt,name=dwell_ms,json=dwellMs,proto3" json:"dwell_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZoneEvent) Reset() {
+	*x = ZoneEvent{}
+	mi := &file_Api_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZoneEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZoneEvent) ProtoMessage() {}
+
+func (x *ZoneEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZoneEvent.ProtoReflect.Descriptor instead.
+func (*ZoneEvent) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ZoneEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetTrackId() int64 {
+	if x != nil {
+		return x.TrackId
+	}
+	return 0
+}
+
+func (x *ZoneEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetDwellMs() int64 {
+	if x != nil {
+		return x.DwellMs
+	}
+	return 0
+}
+
+type SetZonesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 引擎 UUID
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 为空时清除引擎的区域规则
+	Zones         *ZoneConfig `protobuf:"bytes,2,opt,name=zones,proto3" json:"zones,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetZonesRequest) Reset() {
+	*x = SetZonesRequest{}
+	mi := &file_Api_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetZonesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetZonesRequest) ProtoMessage() {}
+
+func (x *SetZonesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetZonesRequest.ProtoReflect.Descriptor instead.
+func (*SetZonesRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SetZonesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetZonesRequest) GetZones() *ZoneConfig {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+type SetZonesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetZonesResponse) Reset() {
+	*x = SetZonesResponse{}
+	mi := &file_Api_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetZonesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetZonesResponse) ProtoMessage() {}
+
+func (x *SetZonesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetZonesResponse.ProtoReflect.Descriptor instead.
+func (*SetZonesResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetZonesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetZonesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type InferenceResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Results []*SingleResult        `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	// 实际处理该请求的引擎；通过别名请求时同时返回别名与版本
+	EngineId string `protobuf:"bytes,3,opt,name=engine_id,json=engineId,proto3" json:"engine_id,omitempty"`
+	Alias    string `protobuf:"bytes,4,opt,name=alias,proto3" json:"alias,omitempty"`
+	Version  string `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	// 本帧新建与丢失的轨迹
+	TrackEvents []*TrackEvent `protobuf:"bytes,6,rep,name=track_events,json=trackEvents,proto3" json:"track_events,omitempty"`
+	// 每个区域内的目标数，顺序与区域规则一致
+	ZoneCounts []*ZoneCount `protobuf:"bytes,7,rep,name=zone_counts,json=zoneCounts,proto3" json:"zone_counts,omitempty"`
+	// 区域进出、停留超时与穿越线事件，只在带轨迹 ID 时产生
+	ZoneEvents    []*ZoneEvent `protobuf:"bytes,8,rep,name=zone_events,json=zoneEvents,proto3" json:"zone_events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InferenceResponse) Reset() {
+	*x = InferenceResponse{}
+	mi := &file_Api_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InferenceResponse) ProtoMessage() {}
+
+func (x *InferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InferenceResponse.ProtoReflect.Descriptor instead.
+func (*InferenceResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *InferenceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InferenceResponse) GetResults() []*SingleResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *InferenceResponse) GetEngineId() string {
+	if x != nil {
+		return x.EngineId
+	}
+	return ""
+}
+
+func (x *InferenceResponse) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *InferenceResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *InferenceResponse) GetTrackEvents() []*TrackEvent {
+	if x != nil {
+		return x.TrackEvents
+	}
+	return nil
+}
+
+func (x *InferenceResponse) GetZoneCounts() []*ZoneCount {
+	if x != nil {
+		return x.ZoneCounts
+	}
+	return nil
+}
+
+func (x *InferenceResponse) GetZoneEvents() []*ZoneEvent {
+	if x != nil {
+		return x.ZoneEvents
+	}
+	return nil
+}
+
+type StreamInferenceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 引擎 UUID 或别名，以及以下窗口设置只在流的第一条消息中生效
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 同时推理的最大帧数，默认 1
+	MaxInFlight int32 `protobuf:"varint,2,opt,name=max_in_flight,json=maxInFlight,proto3" json:"max_in_flight,omitempty"`
+	// 为 true 时窗口已满的新帧替换尚未推理的旧帧（旧帧以 dropped 返回），只保留最新一帧；
+	// 为 false 时接收端阻塞等待，由 gRPC 流控对客户端施加背压
+	KeepLatest bool `protobuf:"varint,3,opt,name=keep_latest,json=keepLatest,proto3" json:"keep_latest,omitempty"`
+	// 大于 0 时，开始推理前距采集时间超过该毫秒数的帧被丢弃
+	MaxFrameAgeMs int32 `protobuf:"varint,4,opt,name=max_frame_age_ms,json=maxFrameAgeMs,proto3" json:"max_frame_age_ms,omitempty"`
+	Seq           int64 `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+	// 帧的采集时间（Unix 毫秒），原样返回并用于计算延迟
+	CaptureUnixMs int64      `protobuf:"varint,6,opt,name=capture_unix_ms,json=captureUnixMs,proto3" json:"capture_unix_ms,omitempty"`
+	ImgData       *ImageData `protobuf:"bytes,7,opt,name=img_data,json=imgData,proto3" json:"img_data,omitempty"`
+	// 只在第一条消息中生效；设置后该流的检测带轨迹 ID，此时 max_in_flight 不能大于 1
+	Tracker *TrackerConfig `protobuf:"bytes,8,opt,name=tracker,proto3" json:"tracker,omitempty"`
+	// 只在第一条消息中生效；设置后该流按这些规则统计区域，未设置 tracker 时使用默认跟踪参数
+	Zones         *ZoneConfig `protobuf:"bytes,9,opt,name=zones,proto3" json:"zones,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamInferenceRequest) Reset() {
+	*x = StreamInferenceRequest{}
+	mi := &file_Api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamInferenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamInferenceRequest) ProtoMessage() {}
+
+func (x *StreamInferenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamInferenceRequest.ProtoReflect.Descriptor instead.
+func (*StreamInferenceRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StreamInferenceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StreamInferenceRequest) GetMaxInFlight() int32 {
+	if x != nil {
+		return x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *StreamInferenceRequest) GetKeepLatest() bool {
+	if x != nil {
+		return x.KeepLatest
+	}
+	return false
+}
+
+func (x *StreamInferenceRequest) GetMaxFrameAgeMs() int32 {
+	if x != nil {
+		return x.MaxFrameAgeMs
+	}
+	return 0
+}
+
+func (x *StreamInferenceRequest) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *StreamInferenceRequest) GetCaptureUnixMs() int64 {
+	if x != nil {
+		return x.CaptureUnixMs
+	}
+	return 0
+}
+
+func (x *StreamInferenceRequest) GetImgData() *ImageData {
+	if x != nil {
+		return x.ImgData
+	}
+	return nil
+}
+
+func (x *StreamInferenceRequest) GetTracker() *TrackerConfig {
+	if x != nil {
+		return x.Tracker
+	}
+	return nil
+}
+
+func (x *StreamInferenceRequest) GetZones() *ZoneConfig {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+type StreamInferenceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           int64                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	CaptureUnixMs int64                  `protobuf:"varint,2,opt,name=capture_unix_ms,json=captureUnixMs,proto3" json:"capture_unix_ms,omitempty"`
+	// 帧因窗口已满或过期被丢弃时为 true，此时 result 为空
+	Dropped bool               `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Message string             `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Result  *InferenceResponse `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	// 从采集时间到结果发送的毫秒数，帧没有采集时间时为 0
+	LatencyMs     int64 `protobuf:"varint,6,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamInferenceResponse) Reset() {
+	*x = StreamInferenceResponse{}
+	mi := &file_Api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamInferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamInferenceResponse) ProtoMessage() {}
+
+func (x *StreamInferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamInferenceResponse.ProtoReflect.Descriptor instead.
+func (*StreamInferenceResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StreamInferenceResponse) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *StreamInferenceResponse) GetCaptureUnixMs() int64 {
+	if x != nil {
+		return x.CaptureUnixMs
+	}
+	return 0
+}
+
+func (x *StreamInferenceResponse) GetDropped() bool {
+	if x != nil {
+		return x.Dropped
+	}
+	return false
+}
+
+func (x *StreamInferenceResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *StreamInferenceResponse) GetResult() *InferenceResponse {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *StreamInferenceResponse) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+type DeleteTrackingSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTrackingSessionRequest) Reset() {
+	*x = DeleteTrackingSessionRequest{}
+	mi := &file_Api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTrackingSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTrackingSessionRequest) ProtoMessage() {}
+
+func (x *DeleteTrackingSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTrackingSessionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTrackingSessionRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteTrackingSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type DeleteTrackingSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTrackingSessionResponse) Reset() {
+	*x = DeleteTrackingSessionResponse{}
+	mi := &file_Api_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTrackingSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTrackingSessionResponse) ProtoMessage() {}
+
+func (x *DeleteTrackingSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTrackingSessionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTrackingSessionResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteTrackingSessionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteTrackingSessionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DestroyEngineRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// force 为 true 时立即从注册表移除，原生实例在最后一个在途任务结束后释放
+	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	// 等待在途任务结束的超时时间，0 表示使用默认值
+	TimeoutMs     int32 `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestroyEngineRequest) Reset() {
+	*x = DestroyEngineRequest{}
+	mi := &file_Api_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestroyEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestroyEngineRequest) ProtoMessage() {}
+
+func (x *DestroyEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestroyEngineRequest.ProtoReflect.Descriptor instead.
+func (*DestroyEngineRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DestroyEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DestroyEngineRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *DestroyEngineRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type DestroyEngineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestroyEngineResponse) Reset() {
+	*x = DestroyEngineResponse{}
+	mi := &file_Api_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestroyEngineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestroyEngineResponse) ProtoMessage() {}
+
+func (x *DestroyEngineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestroyEngineResponse.ProtoReflect.Descriptor instead.
+func (*DestroyEngineResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DestroyEngineResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DestroyEngineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CheckEngineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckEngineRequest) Reset() {
+	*x = CheckEngineRequest{}
+	mi := &file_Api_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckEngineRequest) ProtoMessage() {}
+
+func (x *CheckEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckEngineRequest.ProtoReflect.Descriptor instead.
+func (*CheckEngineRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CheckEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CheckEngineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	EngineInfo    *EngineInfo            `protobuf:"bytes,2,opt,name=engine_info,json=engineInfo,proto3" json:"engine_info,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckEngineResponse) Reset() {
+	*x = CheckEngineResponse{}
+	mi := &file_Api_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckEngineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckEngineResponse) ProtoMessage() {}
+
+func (x *CheckEngineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckEngineResponse.ProtoReflect.Descriptor instead.
+func (*CheckEngineResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CheckEngineResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CheckEngineResponse) GetEngineInfo() *EngineInfo {
+	if x != nil {
+		return x.EngineInfo
+	}
+	return nil
+}
+
+func (x *CheckEngineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RestoreFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	ModelPath     string                 `protobuf:"bytes,3,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFailure) Reset() {
+	*x = RestoreFailure{}
+	mi := &file_Api_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFailure) ProtoMessage() {}
+
+func (x *RestoreFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFailure.ProtoReflect.Descriptor instead.
+func (*RestoreFailure) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RestoreFailure) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RestoreFailure) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RestoreFailure) GetModelPath() string {
+	if x != nil {
+		return x.ModelPath
+	}
+	return ""
+}
+
+func (x *RestoreFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CheckAllEngineResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Engines []*EngineInfo          `protobuf:"bytes,2,rep,name=engines,proto3" json:"engines,omitempty"`
+	Message string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// 启动时从状态文件恢复失败的引擎，可通过 DestroyEngine 丢弃
+	RestoreFailures []*RestoreFailure `protobuf:"bytes,4,rep,name=restore_failures,json=restoreFailures,proto3" json:"restore_failures,omitempty"`
+	// 降级启动时加载失败的预加载引擎
+	PreloadFailures []*RestoreFailure `protobuf:"bytes,5,rep,name=preload_failures,json=preloadFailures,proto3" json:"preload_failures,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CheckAllEngineResponse) Reset() {
+	*x = CheckAllEngineResponse{}
+	mi := &file_Api_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckAllEngineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckAllEngineResponse) ProtoMessage() {}
+
+func (x *CheckAllEngineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckAllEngineResponse.ProtoReflect.Descriptor instead.
+func (*CheckAllEngineResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CheckAllEngineResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CheckAllEngineResponse) GetEngines() []*EngineInfo {
+	if x != nil {
+		return x.Engines
+	}
+	return nil
+}
+
+func (x *CheckAllEngineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CheckAllEngineResponse) GetRestoreFailures() []*RestoreFailure {
+	if x != nil {
+		return x.RestoreFailures
+	}
+	return nil
+}
+
+func (x *CheckAllEngineResponse) GetPreloadFailures() []*RestoreFailure {
+	if x != nil {
+		return x.PreloadFailures
+	}
+	return nil
+}
+
+type RenewLeaseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// 大于 0 时同时修改租约时长，否则沿用创建时的租约时长
+	LeaseSeconds  int32 `protobuf:"varint,2,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenewLeaseRequest) Reset() {
+	*x = RenewLeaseRequest{}
+	mi := &file_Api_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenewLeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewLeaseRequest) ProtoMessage() {}
+
+func (x *RenewLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewLeaseRequest.ProtoReflect.Descriptor instead.
+func (*RenewLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RenewLeaseRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *RenewLeaseRequest) GetLeaseSeconds() int32 {
+	if x != nil {
+		return x.LeaseSeconds
+	}
+	return 0
+}
+
+type LeaseStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Renewed       bool                   `protobuf:"varint,2,opt,name=renewed,proto3" json:"renewed,omitempty"`
+	ExpiresUnixMs int64                  `protobuf:"varint,3,opt,name=expires_unix_ms,json=expiresUnixMs,proto3" json:"expires_unix_ms,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseStatus) Reset() {
+	*x = LeaseStatus{}
+	mi := &file_Api_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseStatus) ProtoMessage() {}
+
+func (x *LeaseStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseStatus.ProtoReflect.Descriptor instead.
+func (*LeaseStatus) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LeaseStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LeaseStatus) GetRenewed() bool {
+	if x != nil {
+		return x.Renewed
+	}
+	return false
+}
+
+func (x *LeaseStatus) GetExpiresUnixMs() int64 {
+	if x != nil {
+		return x.ExpiresUnixMs
+	}
+	return 0
+}
+
+func (x *LeaseStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RenewLeaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Leases        []*LeaseStatus         `protobuf:"bytes,2,rep,name=leases,proto3" json:"leases,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenewLeaseResponse) Reset() {
+	*x = RenewLeaseResponse{}
+	mi := &file_Api_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenewLeaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewLeaseResponse) ProtoMessage() {}
+
+func (x *RenewLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewLeaseResponse.ProtoReflect.Descriptor instead.
+func (*RenewLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RenewLeaseResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RenewLeaseResponse) GetLeases() []*LeaseStatus {
+	if x != nil {
+		return x.Leases
+	}
+	return nil
+}
+
+func (x *RenewLeaseResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ReloadEngineRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 新模型路径，为空时重新加载当前模型文件
+	ModelPath string `protobuf:"bytes,2,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
+	// 可选的预热图片，新实例对它推理成功后才会替换旧实例
+	WarmupImage *ImageData `protobuf:"bytes,3,opt,name=warmup_image,json=warmupImage,proto3" json:"warmup_image,omitempty"`
+	// 等待旧实例在途任务结束的超时时间，0 表示使用默认值
+	TimeoutMs     int32 `protobuf:"varint,4,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadEngineRequest) Reset() {
+	*x = ReloadEngineRequest{}
+	mi := &file_Api_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadEngineRequest) ProtoMessage() {}
+
+func (x *ReloadEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadEngineRequest.ProtoReflect.Descriptor instead.
+func (*ReloadEngineRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReloadEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReloadEngineRequest) GetModelPath() string {
+	if x != nil {
+		return x.ModelPath
+	}
+	return ""
+}
+
+func (x *ReloadEngineRequest) GetWarmupImage() *ImageData {
+	if x != nil {
+		return x.WarmupImage
+	}
+	return nil
+}
+
+func (x *ReloadEngineRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type ReloadEngineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadEngineResponse) Reset() {
+	*x = ReloadEngineResponse{}
+	mi := &file_Api_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadEngineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadEngineResponse) ProtoMessage() {}
+
+func (x *ReloadEngineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadEngineResponse.ProtoReflect.Descriptor instead.
+func (*ReloadEngineResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReloadEngineResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ReloadEngineResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReloadEngineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UpdateEngineRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 未设置的字段保持不变
+	Confidence *float32 `protobuf:"fixed32,2,opt,name=confidence,proto3,oneof" json:"confidence,omitempty"`
+	Iou        *float32 `protobuf:"fixed32,3,opt,name=iou,proto3,oneof" json:"iou,omitempty"`
+	InputSize  *int32   `protobuf:"varint,4,opt,name=input_size,json=inputSize,proto3,oneof" json:"input_size,omitempty"`
+	// 非空时替换类别名
+	Names         []string `protobuf:"bytes,5,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEngineRequest) Reset() {
+	*x = UpdateEngineRequest{}
+	mi := &file_Api_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEngineRequest) ProtoMessage() {}
+
+func (x *UpdateEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEngineRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEngineRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateEngineRequest) GetConfidence() float32 {
+	if x != nil && x.Confidence != nil {
+		return *x.Confidence
+	}
+	return 0
+}
+
+func (x *UpdateEngineRequest) GetIou() float32 {
+	if x != nil && x.Iou != nil {
+		return *x.Iou
+	}
+	return 0
+}
+
+func (x *UpdateEngineRequest) GetInputSize() int32 {
+	if x != nil && x.InputSize != nil {
+		return *x.InputSize
+	}
+	return 0
+}
+
+func (x *UpdateEngineRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type UpdateEngineResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// 为 true 时新配置通过重新加载原生实例生效，否则在 Go 侧就地生效
+	Reinitialized bool        `protobuf:"varint,3,opt,name=reinitialized,proto3" json:"reinitialized,omitempty"`
+	EngineInfo    *EngineInfo `protobuf:"bytes,4,opt,name=engine_info,json=engineInfo,proto3" json:"engine_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEngineResponse) Reset() {
+	*x = UpdateEngineResponse{}
+	mi := &file_Api_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEngineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEngineResponse) ProtoMessage() {}
+
+func (x *UpdateEngineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEngineResponse.ProtoReflect.Descriptor instead.
+func (*UpdateEngineResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *UpdateEngineResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateEngineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateEngineResponse) GetReinitialized() bool {
+	if x != nil {
+		return x.Reinitialized
+	}
+	return false
+}
+
+func (x *UpdateEngineResponse) GetEngineInfo() *EngineInfo {
+	if x != nil {
+		return x.EngineInfo
+	}
+	return nil
+}
+
+type AliasTarget struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	EngineId string                 `protobuf:"bytes,1,opt,name=engine_id,json=engineId,proto3" json:"engine_id,omitempty"`
+	Version  string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// 流量权重，按各目标权重占比分配请求
+	Weight        uint32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AliasTarget) Reset() {
+	*x = AliasTarget{}
+	mi := &file_Api_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AliasTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AliasTarget) ProtoMessage() {}
+
+func (x *AliasTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AliasTarget.ProtoReflect.Descriptor instead.
+func (*AliasTarget) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AliasTarget) GetEngineId() string {
+	if x != nil {
+		return x.EngineId
+	}
+	return ""
+}
+
+func (x *AliasTarget) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AliasTarget) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type Alias struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Targets       []*AliasTarget         `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"`
+	UpdatedUnixMs int64                  `protobuf:"varint,3,opt,name=updated_unix_ms,json=updatedUnixMs,proto3" json:"updated_unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alias) Reset() {
+	*x = Alias{}
+	mi := &file_Api_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alias) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alias) ProtoMessage() {}
+
+func (x *Alias) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alias.ProtoReflect.Descriptor instead.
+func (*Alias) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Alias) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Alias) GetTargets() []*AliasTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *Alias) GetUpdatedUnixMs() int64 {
+	if x != nil {
+		return x.UpdatedUnixMs
+	}
+	return 0
+}
+
+type SetAliasRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Targets       []*AliasTarget         `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAliasRequest) Reset() {
+	*x = SetAliasRequest{}
+	mi := &file_Api_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAliasRequest) ProtoMessage() {}
+
+func (x *SetAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAliasRequest.ProtoReflect.Descriptor instead.
+func (*SetAliasRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SetAliasRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetAliasRequest) GetTargets() []*AliasTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type DeleteAliasRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAliasRequest) Reset() {
+	*x = DeleteAliasRequest{}
+	mi := &file_Api_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAliasRequest) ProtoMessage() {}
+
+func (x *DeleteAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAliasRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAliasRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DeleteAliasRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type AliasResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Alias         *Alias                 `protobuf:"bytes,3,opt,name=alias,proto3" json:"alias,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AliasResponse) Reset() {
+	*x = AliasResponse{}
+	mi := &file_Api_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AliasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AliasResponse) ProtoMessage() {}
+
+func (x *AliasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AliasResponse.ProtoReflect.Descriptor instead.
+func (*AliasResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *AliasResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AliasResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AliasResponse) GetAlias() *Alias {
+	if x != nil {
+		return x.Alias
+	}
+	return nil
+}
+
+type ListAliasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Aliases       []*Alias               `protobuf:"bytes,1,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAliasesResponse) Reset() {
+	*x = ListAliasesResponse{}
+	mi := &file_Api_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAliasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAliasesResponse) ProtoMessage() {}
+
+func (x *ListAliasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAliasesResponse.ProtoReflect.Descriptor instead.
+func (*ListAliasesResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListAliasesResponse) GetAliases() []*Alias {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type SetShadowRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 影子引擎 ID，为空时取消影子推理
+	ShadowId string `protobuf:"bytes,2,opt,name=shadow_id,json=shadowId,proto3" json:"shadow_id,omitempty"`
+	// 采样比例 (0, 1]
+	SampleRate float32 `protobuf:"fixed32,3,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	// 判定两个检测框匹配的 IoU 阈值，0 表示使用 0.5
+	IouThreshold  float32 `protobuf:"fixed32,4,opt,name=iou_threshold,json=iouThreshold,proto3" json:"iou_threshold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetShadowRequest) Reset() {
+	*x = SetShadowRequest{}
+	mi := &file_Api_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetShadowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetShadowRequest) ProtoMessage() {}
+
+func (x *SetShadowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetShadowRequest.ProtoReflect.Descriptor instead.
+func (*SetShadowRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SetShadowRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetShadowRequest) GetShadowId() string {
+	if x != nil {
+		return x.ShadowId
+	}
+	return ""
+}
+
+func (x *SetShadowRequest) GetSampleRate() float32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *SetShadowRequest) GetIouThreshold() float32 {
+	if x != nil {
+		return x.IouThreshold
+	}
+	return 0
+}
+
+type SetShadowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetShadowResponse) Reset() {
+	*x = SetShadowResponse{}
+	mi := &file_Api_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetShadowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetShadowResponse) ProtoMessage() {}
+
+func (x *SetShadowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetShadowResponse.ProtoReflect.Descriptor instead.
+func (*SetShadowResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *SetShadowResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetShadowResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ShadowStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShadowStatsRequest) Reset() {
+	*x = ShadowStatsRequest{}
+	mi := &file_Api_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShadowStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShadowStatsRequest) ProtoMessage() {}
+
+func (x *ShadowStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShadowStatsRequest.ProtoReflect.Descriptor instead.
+func (*ShadowStatsRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ShadowStatsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ClassAgreement struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// 两个引擎在 IoU 阈值下匹配上的检测框数
+	Matched int64 `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
+	// 影子引擎多检出的检测框数
+	Extra int64 `protobuf:"varint,3,opt,name=extra,proto3" json:"extra,omitempty"`
+	// 影子引擎漏检的检测框数
+	Missing int64 `protobuf:"varint,4,opt,name=missing,proto3" json:"missing,omitempty"`
+	// 匹配框的置信度差（影子 - 生产）均值与绝对值均值
+	MeanConfDelta    float32 `protobuf:"fixed32,5,opt,name=mean_conf_delta,json=meanConfDelta,proto3" json:"mean_conf_delta,omitempty"`
+	MeanAbsConfDelta float32 `protobuf:"fixed32,6,opt,name=mean_abs_conf_delta,json=meanAbsConfDelta,proto3" json:"mean_abs_conf_delta,omitempty"`
+	MeanIou          float32 `protobuf:"fixed32,7,opt,name=mean_iou,json=meanIou,proto3" json:"mean_iou,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ClassAgreement) Reset() {
+	*x = ClassAgreement{}
+	mi := &file_Api_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassAgreement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassAgreement) ProtoMessage() {}
+
+func (x *ClassAgreement) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassAgreement.ProtoReflect.Descriptor instead.
+func (*ClassAgreement) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ClassAgreement) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClassAgreement) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *ClassAgreement) GetExtra() int64 {
+	if x != nil {
+		return x.Extra
+	}
+	return 0
+}
+
+func (x *ClassAgreement) GetMissing() int64 {
+	if x != nil {
+		return x.Missing
+	}
+	return 0
+}
+
+func (x *ClassAgreement) GetMeanConfDelta() float32 {
+	if x != nil {
+		return x.MeanConfDelta
+	}
+	return 0
+}
+
+func (x *ClassAgreement) GetMeanAbsConfDelta() float32 {
+	if x != nil {
+		return x.MeanAbsConfDelta
+	}
+	return 0
+}
+
+func (x *ClassAgreement) GetMeanIou() float32 {
+	if x != nil {
+		return x.MeanIou
+	}
+	return 0
+}
+
+type ShadowStatsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShadowId     string                 `protobuf:"bytes,2,opt,name=shadow_id,json=shadowId,proto3" json:"shadow_id,omitempty"`
+	SampleRate   float32                `protobuf:"fixed32,3,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	IouThreshold float32                `protobuf:"fixed32,4,opt,name=iou_threshold,json=iouThreshold,proto3" json:"iou_threshold,omitempty"`
+	// 已比较的请求数、影子推理失败数、因并发已满被丢弃的采样数
+	Compared      int64             `protobuf:"varint,5,opt,name=compared,proto3" json:"compared,omitempty"`
+	Failed        int64             `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	Dropped       int64             `protobuf:"varint,7,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Classes       []*ClassAgreement `protobuf:"bytes,8,rep,name=classes,proto3" json:"classes,omitempty"`
+	SinceUnixMs   int64             `protobuf:"varint,9,opt,name=since_unix_ms,json=sinceUnixMs,proto3" json:"since_unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShadowStatsResponse) Reset() {
+	*x = ShadowStatsResponse{}
+	mi := &file_Api_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShadowStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShadowStatsResponse) ProtoMessage() {}
+
+func (x *ShadowStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShadowStatsResponse.ProtoReflect.Descriptor instead.
+func (*ShadowStatsResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ShadowStatsResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShadowStatsResponse) GetShadowId() string {
+	if x != nil {
+		return x.ShadowId
+	}
+	return ""
+}
+
+func (x *ShadowStatsResponse) GetSampleRate() float32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *ShadowStatsResponse) GetIouThreshold() float32 {
+	if x != nil {
+		return x.IouThreshold
+	}
+	return 0
+}
+
+func (x *ShadowStatsResponse) GetCompared() int64 {
+	if x != nil {
+		return x.Compared
+	}
+	return 0
+}
+
+func (x *ShadowStatsResponse) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ShadowStatsResponse) GetDropped() int64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *ShadowStatsResponse) GetClasses() []*ClassAgreement {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+func (x *ShadowStatsResponse) GetSinceUnixMs() int64 {
+	if x != nil {
+		return x.SinceUnixMs
+	}
+	return 0
+}
+
+type EnsembleMember struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	EngineId string                 `protobuf:"bytes,1,opt,name=engine_id,json=engineId,proto3" json:"engine_id,omitempty"`
+	// 融合时该成员的权重，0 表示 1
+	Weight float32 `protobuf:"fixed32,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	// 成员类别名到集成类别名的映射，未列出的类别保持原名，映射为空字符串的类别被丢弃
+	ClassMap      map[string]string `protobuf:"bytes,3,rep,name=class_map,json=classMap,proto3" json:"class_map,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnsembleMember) Reset() {
+	*x = EnsembleMember{}
+	mi := &file_Api_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnsembleMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnsembleMember) ProtoMessage() {}
+
+func (x *EnsembleMember) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnsembleMember.ProtoReflect.Descriptor instead.
+func (*EnsembleMember) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *EnsembleMember) GetEngineId() string {
+	if x != nil {
+		return x.EngineId
+	}
+	return ""
+}
+
+func (x *EnsembleMember) GetWeight() float32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *EnsembleMember) GetClassMap() map[string]string {
+	if x != nil {
+		return x.ClassMap
+	}
+	return nil
+}
+
+type CreateEnsembleRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Members []*EnsembleMember      `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	// wbf（默认）或 nms
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// 聚类 / 抑制的 IoU 阈值，0 表示使用 0.55
+	IouThreshold float32 `protobuf:"fixed32,3,opt,name=iou_threshold,json=iouThreshold,proto3" json:"iou_threshold,omitempty"`
+	// 低于该置信度的成员结果不参与融合
+	SkipThreshold float32 `protobuf:"fixed32,4,opt,name=skip_threshold,json=skipThreshold,proto3" json:"skip_threshold,omitempty"`
+	Description   string  `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEnsembleRequest) Reset() {
+	*x = CreateEnsembleRequest{}
+	mi := &file_Api_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnsembleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnsembleRequest) ProtoMessage() {}
+
+func (x *CreateEnsembleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnsembleRequest.ProtoReflect.Descriptor instead.
+func (*CreateEnsembleRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreateEnsembleRequest) GetMembers() []*EnsembleMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *CreateEnsembleRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *CreateEnsembleRequest) GetIouThreshold() float32 {
+	if x != nil {
+		return x.IouThreshold
+	}
+	return 0
+}
+
+func (x *CreateEnsembleRequest) GetSkipThreshold() float32 {
+	if x != nil {
+		return x.SkipThreshold
+	}
+	return 0
+}
+
+func (x *CreateEnsembleRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type MultiInferenceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 引擎 UUID 或别名
+	Ids           []string   `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	ImgData       *ImageData `protobuf:"bytes,2,opt,name=img_data,json=imgData,proto3" json:"img_data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiInferenceRequest) Reset() {
+	*x = MultiInferenceRequest{}
+	mi := &file_Api_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiInferenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiInferenceRequest) ProtoMessage() {}
+
+func (x *MultiInferenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiInferenceRequest.ProtoReflect.Descriptor instead.
+func (*MultiInferenceRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *MultiInferenceRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *MultiInferenceRequest) GetImgData() *ImageData {
+	if x != nil {
+		return x.ImgData
+	}
+	return nil
+}
+
+type EngineResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 请求中的引擎 UUID 或别名
+	Id            string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Success       bool            `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string          `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Results       []*SingleResult `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	EngineId      string          `protobuf:"bytes,5,opt,name=engine_id,json=engineId,proto3" json:"engine_id,omitempty"`
+	Alias         string          `protobuf:"bytes,6,opt,name=alias,proto3" json:"alias,omitempty"`
+	Version       string          `protobuf:"bytes,7,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EngineResult) Reset() {
+	*x = EngineResult{}
+	mi := &file_Api_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EngineResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EngineResult) ProtoMessage() {}
+
+func (x *EngineResult) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EngineResult.ProtoReflect.Descriptor instead.
+func (*EngineResult) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *EngineResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EngineResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *EngineResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *EngineResult) GetResults() []*SingleResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *EngineResult) GetEngineId() string {
+	if x != nil {
+		return x.EngineId
+	}
+	return ""
+}
+
+func (x *EngineResult) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *EngineResult) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type MultiInferenceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 全部引擎都成功时为 true
+	Success       bool            `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Results       []*EngineResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiInferenceResponse) Reset() {
+	*x = MultiInferenceResponse{}
+	mi := &file_Api_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiInferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiInferenceResponse) ProtoMessage() {}
+
+func (x *MultiInferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiInferenceResponse.ProtoReflect.Descriptor instead.
+func (*MultiInferenceResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *MultiInferenceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MultiInferenceResponse) GetResults() []*EngineResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type RegisterPipelineRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// YAML 格式的流水线定义
+	Yaml string `protobuf:"bytes,1,opt,name=yaml,proto3" json:"yaml,omitempty"`
+	// 为 true 时替换同名流水线
+	Replace       bool `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterPipelineRequest) Reset() {
+	*x = RegisterPipelineRequest{}
+	mi := &file_Api_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterPipelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterPipelineRequest) ProtoMessage() {}
+
+func (x *RegisterPipelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterPipelineRequest.ProtoReflect.Descriptor instead.
+func (*RegisterPipelineRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RegisterPipelineRequest) GetYaml() string {
+	if x != nil {
+		return x.Yaml
+	}
+	return ""
+}
+
+func (x *RegisterPipelineRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+type PipelineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PipelineResponse) Reset() {
+	*x = PipelineResponse{}
+	mi := &file_Api_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelineResponse) ProtoMessage() {}
+
+func (x *PipelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelineResponse.ProtoReflect.Descriptor instead.
+func (*PipelineResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *PipelineResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PipelineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PipelineResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeletePipelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePipelineRequest) Reset() {
+	*x = DeletePipelineRequest{}
+	mi := &file_Api_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePipelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePipelineRequest) ProtoMessage() {}
+
+func (x *DeletePipelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePipelineRequest.ProtoReflect.Descriptor instead.
+func (*DeletePipelineRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *DeletePipelineRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PipelineInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Stages        []string               `protobuf:"bytes,2,rep,name=stages,proto3" json:"stages,omitempty"`
+	Engines       []string               `protobuf:"bytes,3,rep,name=engines,proto3" json:"engines,omitempty"`
+	Yaml          string                 `protobuf:"bytes,4,opt,name=yaml,proto3" json:"yaml,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PipelineInfo) Reset() {
+	*x = PipelineInfo{}
+	mi := &file_Api_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelineInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelineInfo) ProtoMessage() {}
+
+func (x *PipelineInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelineInfo.ProtoReflect.Descriptor instead.
+func (*PipelineInfo) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *PipelineInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PipelineInfo) GetStages() []string {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *PipelineInfo) GetEngines() []string {
+	if x != nil {
+		return x.Engines
+	}
+	return nil
+}
+
+func (x *PipelineInfo) GetYaml() string {
+	if x != nil {
+		return x.Yaml
+	}
+	return ""
+}
+
+type ListPipelinesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pipelines     []*PipelineInfo        `protobuf:"bytes,1,rep,name=pipelines,proto3" json:"pipelines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPipelinesResponse) Reset() {
+	*x = ListPipelinesResponse{}
+	mi := &file_Api_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPipelinesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPipelinesResponse) ProtoMessage() {}
+
+func (x *ListPipelinesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPipelinesResponse.ProtoReflect.Descriptor instead.
+func (*ListPipelinesResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListPipelinesResponse) GetPipelines() []*PipelineInfo {
+	if x != nil {
+		return x.Pipelines
+	}
+	return nil
+}
+
+type RunPipelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ImgData       *ImageData             `protobuf:"bytes,2,opt,name=img_data,json=imgData,proto3" json:"img_data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunPipelineRequest) Reset() {
+	*x = RunPipelineRequest{}
+	mi := &file_Api_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunPipelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunPipelineRequest) ProtoMessage() {}
+
+func (x *RunPipelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunPipelineRequest.ProtoReflect.Descriptor instead.
+func (*RunPipelineRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RunPipelineRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RunPipelineRequest) GetImgData() *ImageData {
+	if x != nil {
+		return x.ImgData
+	}
+	return nil
+}
+
+type PipelineLabel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Confidence    float32                `protobuf:"fixed32,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PipelineLabel) Reset() {
+	*x = PipelineLabel{}
+	mi := &file_Api_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelineLabel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelineLabel) ProtoMessage() {}
+
+func (x *PipelineLabel) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelineLabel.ProtoReflect.Descriptor instead.
+func (*PipelineLabel) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *PipelineLabel) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *PipelineLabel) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PipelineLabel) GetConfidence() float32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+type PipelineDetection struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 产生该结果的阶段
+	Stage      string  `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Name       string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Confidence float32 `protobuf:"fixed32,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	// 原图坐标
+	Box    []*Position `protobuf:"bytes,4,rep,name=box,proto3" json:"box,omitempty"`
+	Center *Position   `protobuf:"bytes,5,opt,name=center,proto3" json:"center,omitempty"`
+	// 分类阶段附加的标签
+	Labels []*PipelineLabel `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty"`
+	// 在该结果裁剪区域内检出的下游结果
+	Children      []*PipelineDetection `protobuf:"bytes,7,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PipelineDetection) Reset() {
+	*x = PipelineDetection{}
+	mi := &file_Api_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelineDetection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelineDetection) ProtoMessage() {}
+
+func (x *PipelineDetection) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelineDetection.ProtoReflect.Descriptor instead.
+func (*PipelineDetection) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *PipelineDetection) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *PipelineDetection) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PipelineDetection) GetConfidence() float32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *PipelineDetection) GetBox() []*Position {
+	if x != nil {
+		return x.Box
+	}
+	return nil
+}
+
+func (x *PipelineDetection) GetCenter() *Position {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *PipelineDetection) GetLabels() []*PipelineLabel {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *PipelineDetection) GetChildren() []*PipelineDetection {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type RunPipelineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Detections    []*PipelineDetection   `protobuf:"bytes,3,rep,name=detections,proto3" json:"detections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunPipelineResponse) Reset() {
+	*x = RunPipelineResponse{}
+	mi := &file_Api_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunPipelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunPipelineResponse) ProtoMessage() {}
+
+func (x *RunPipelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunPipelineResponse.ProtoReflect.Descriptor instead.
+func (*RunPipelineResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *RunPipelineResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RunPipelineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RunPipelineResponse) GetDetections() []*PipelineDetection {
+	if x != nil {
+		return x.Detections
+	}
+	return nil
+}
+
+type Preprocess struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mean          []float32              `protobuf:"fixed32,1,rep,packed,name=mean,proto3" json:"mean,omitempty"`
+	Norm          []float32              `protobuf:"fixed32,2,rep,packed,name=norm,proto3" json:"norm,omitempty"`
+	SwapRb        bool                   `protobuf:"varint,3,opt,name=swap_rb,json=swapRb,proto3" json:"swap_rb,omitempty"`
+	Letterbox     bool                   `protobuf:"varint,4,opt,name=letterbox,proto3" json:"letterbox,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Preprocess) Reset() {
+	*x = Preprocess{}
+	mi := &file_Api_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Preprocess) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Preprocess) ProtoMessage() {}
+
+func (x *Preprocess) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Preprocess.ProtoReflect.Descriptor instead.
+func (*Preprocess) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *Preprocess) GetMean() []float32 {
+	if x != nil {
+		return x.Mean
+	}
+	return nil
+}
+
+func (x *Preprocess) GetNorm() []float32 {
+	if x != nil {
+		return x.Norm
+	}
+	return nil
+}
+
+func (x *Preprocess) GetSwapRb() bool {
+	if x != nil {
+		return x.SwapRb
+	}
+	return false
+}
+
+func (x *Preprocess) GetLetterbox() bool {
+	if x != nil {
+		return x.Letterbox
+	}
+	return false
+}
+
+// ModelManifest 是模型旁 <模型名去掉扩展名>.manifest.yaml 中的默认参数
+type ModelManifest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	InputSize     int32                  `protobuf:"varint,2,opt,name=input_size,json=inputSize,proto3" json:"input_size,omitempty"`
+	InputBlob     string                 `protobuf:"bytes,3,opt,name=input_blob,json=inputBlob,proto3" json:"input_blob,omitempty"`
+	OutputBlob    string                 `protobuf:"bytes,4,opt,name=output_blob,json=outputBlob,proto3" json:"output_blob,omitempty"`
+	Confidence    float32                `protobuf:"fixed32,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Iou           float32                `protobuf:"fixed32,6,opt,name=iou,proto3" json:"iou,omitempty"`
+	Task          string                 `protobuf:"bytes,7,opt,name=task,proto3" json:"task,omitempty"`
+	Preprocess    *Preprocess            `protobuf:"bytes,8,opt,name=preprocess,proto3" json:"preprocess,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelManifest) Reset() {
+	*x = ModelManifest{}
+	mi := &file_Api_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelManifest) ProtoMessage() {}
+
+func (x *ModelManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelManifest.ProtoReflect.Descriptor instead.
+func (*ModelManifest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ModelManifest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *ModelManifest) GetInputSize() int32 {
+	if x != nil {
+		return x.InputSize
+	}
+	return 0
+}
+
+func (x *ModelManifest) GetInputBlob() string {
+	if x != nil {
+		return x.InputBlob
+	}
+	return ""
+}
+
+func (x *ModelManifest) GetOutputBlob() string {
+	if x != nil {
+		return x.OutputBlob
+	}
+	return ""
+}
+
+func (x *ModelManifest) GetConfidence() float32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *ModelManifest) GetIou() float32 {
+	if x != nil {
+		return x.Iou
+	}
+	return 0
+}
+
+func (x *ModelManifest) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *ModelManifest) GetPreprocess() *Preprocess {
+	if x != nil {
+		return x.Preprocess
+	}
+	return nil
+}
+
+type InspectModelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 仓库中的模型名或模型路径，支持 .onnx 和 ncnn .param
+	ModelPath     string `protobuf:"bytes,1,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectModelRequest) Reset() {
+	*x = InspectModelRequest{}
+	mi := &file_Api_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectModelRequest) ProtoMessage() {}
+
+func (x *InspectModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectModelRequest.ProtoReflect.Descriptor instead.
+func (*InspectModelRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *InspectModelRequest) GetModelPath() string {
+	if x != nil {
+		return x.ModelPath
+	}
+	return ""
+}
+
+type TensorInfo struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ElemType string                 `protobuf:"bytes,2,opt,name=elem_type,json=elemType,proto3" json:"elem_type,omitempty"`
+	// 动态维度为 -1，其符号名在 dim_params 的对应位置
+	Shape         []int64  `protobuf:"varint,3,rep,packed,name=shape,proto3" json:"shape,omitempty"`
+	DimParams     []string `protobuf:"bytes,4,rep,name=dim_params,json=dimParams,proto3" json:"dim_params,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TensorInfo) Reset() {
+	*x = TensorInfo{}
+	mi := &file_Api_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TensorInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TensorInfo) ProtoMessage() {}
+
+func (x *TensorInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TensorInfo.ProtoReflect.Descriptor instead.
+func (*TensorInfo) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *TensorInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TensorInfo) GetElemType() string {
+	if x != nil {
+		return x.ElemType
+	}
+	return ""
+}
+
+func (x *TensorInfo) GetShape() []int64 {
+	if x != nil {
+		return x.Shape
+	}
+	return nil
+}
+
+func (x *TensorInfo) GetDimParams() []string {
+	if x != nil {
+		return x.DimParams
+	}
+	return nil
+}
+
+type OpsetInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpsetInfo) Reset() {
+	*x = OpsetInfo{}
+	mi := &file_Api_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpsetInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpsetInfo) ProtoMessage() {}
+
+func (x *OpsetInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpsetInfo.ProtoReflect.Descriptor instead.
+func (*OpsetInfo) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *OpsetInfo) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *OpsetInfo) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type NcnnLayer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Bottoms       []string               `protobuf:"bytes,3,rep,name=bottoms,proto3" json:"bottoms,omitempty"`
+	Tops          []string               `protobuf:"bytes,4,rep,name=tops,proto3" json:"tops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NcnnLayer) Reset() {
+	*x = NcnnLayer{}
+	mi := &file_Api_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NcnnLayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NcnnLayer) ProtoMessage() {}
+
+func (x *NcnnLayer) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NcnnLayer.ProtoReflect.Descriptor instead.
+func (*NcnnLayer) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *NcnnLayer) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *NcnnLayer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NcnnLayer) GetBottoms() []string {
+	if x != nil {
+		return x.Bottoms
+	}
+	return nil
+}
+
+func (x *NcnnLayer) GetTops() []string {
+	if x != nil {
+		return x.Tops
+	}
+	return nil
+}
+
+type InspectModelResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ModelPath       string                 `protobuf:"bytes,1,opt,name=model_path,json=modelPath,proto3" json:"model_path,omitempty"`
+	IrVersion       int64                  `protobuf:"varint,2,opt,name=ir_version,json=irVersion,proto3" json:"ir_version,omitempty"`
+	ProducerName    string                 `protobuf:"bytes,3,opt,name=producer_name,json=producerName,proto3" json:"producer_name,omitempty"`
+	ProducerVersion string                 `protobuf:"bytes,4,opt,name=producer_version,json=producerVersion,proto3" json:"producer_version,omitempty"`
+	Opsets          []*OpsetInfo           `protobuf:"bytes,5,rep,name=opsets,proto3" json:"opsets,omitempty"`
+	Inputs          []*TensorInfo          `protobuf:"bytes,6,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs         []*TensorInfo          `protobuf:"bytes,7,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Metadata        map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// 从 metadata 解析出的类别名、输入尺寸和步长
+	Names      []string `protobuf:"bytes,9,rep,name=names,proto3" json:"names,omitempty"`
+	InputSize  int32    `protobuf:"varint,10,opt,name=input_size,json=inputSize,proto3" json:"input_size,omitempty"`
+	Stride     int32    `protobuf:"varint,11,opt,name=stride,proto3" json:"stride,omitempty"`
+	NamesError string   `protobuf:"bytes,12,opt,name=names_error,json=namesError,proto3" json:"names_error,omitempty"`
+	// 以下字段只用于 ncnn .param 模型，inputs / outputs 为推测的输入输出 blob
+	Layers        []*NcnnLayer `protobuf:"bytes,13,rep,name=layers,proto3" json:"layers,omitempty"`
+	Blobs         []string     `protobuf:"bytes,14,rep,name=blobs,proto3" json:"blobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectModelResponse) Reset() {
+	*x = InspectModelResponse{}
+	mi := &file_Api_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectModelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectModelResponse) ProtoMessage() {}
+
+func (x *InspectModelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectModelResponse.ProtoReflect.Descriptor instead.
+func (*InspectModelResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *InspectModelResponse) GetModelPath() string {
+	if x != nil {
+		return x.ModelPath
+	}
+	return ""
+}
+
+func (x *InspectModelResponse) GetIrVersion() int64 {
+	if x != nil {
+		return x.IrVersion
+	}
+	return 0
+}
+
+func (x *InspectModelResponse) GetProducerName() string {
+	if x != nil {
+		return x.ProducerName
+	}
+	return ""
+}
+
+func (x *InspectModelResponse) GetProducerVersion() string {
+	if x != nil {
+		return x.ProducerVersion
+	}
+	return ""
+}
+
+func (x *InspectModelResponse) GetOpsets() []*OpsetInfo {
+	if x != nil {
+		return x.Opsets
+	}
+	return nil
+}
+
+func (x *InspectModelResponse) GetInputs() []*TensorInfo {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *InspectModelResponse) GetOutputs() []*TensorInfo {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *InspectModelResponse) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *InspectModelResponse) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *InspectModelResponse) GetInputSize() int32 {
+	if x != nil {
+		return x.InputSize
+	}
+	return 0
+}
+
+func (x *InspectModelResponse) GetStride() int32 {
+	if x != nil {
+		return x.Stride
+	}
+	return 0
+}
+
+func (x *InspectModelResponse) GetNamesError() string {
+	if x != nil {
+		return x.NamesError
+	}
+	return ""
+}
+
+func (x *InspectModelResponse) GetLayers() []*NcnnLayer {
+	if x != nil {
+		return x.Layers
+	}
+	return nil
+}
+
+func (x *InspectModelResponse) GetBlobs() []string {
+	if x != nil {
+		return x.Blobs
+	}
+	return nil
+}
+
+type ModelInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path  string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Size  int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// ListModels 不计算 SHA-256，该字段为空
+	Sha256         string         `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	UploadedUnixMs int64          `protobuf:"varint,5,opt,name=uploaded_unix_ms,json=uploadedUnixMs,proto3" json:"uploaded_unix_ms,omitempty"`
+	Format         string         `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	EngineIds      []string       `protobuf:"bytes,7,rep,name=engine_ids,json=engineIds,proto3" json:"engine_ids,omitempty"`
+	Manifest       *ModelManifest `protobuf:"bytes,8,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	ManifestError  string         `protobuf:"bytes,9,opt,name=manifest_error,json=manifestError,proto3" json:"manifest_error,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ModelInfo) Reset() {
+	*x = ModelInfo{}
+	mi := &file_Api_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelInfo) ProtoMessage() {}
+
+func (x *ModelInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelInfo.ProtoReflect.Descriptor instead.
+func (*ModelInfo) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ModelInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
 }
 
-type CheckEngineRequest struct {
+func (x *ModelInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetUploadedUnixMs() int64 {
+	if x != nil {
+		return x.UploadedUnixMs
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetEngineIds() []string {
+	if x != nil {
+		return x.EngineIds
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetManifest() *ModelManifest {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetManifestError() string {
+	if x != nil {
+		return x.ManifestError
+	}
+	return ""
+}
+
+type ListModelsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Models        []*ModelInfo           `protobuf:"bytes,1,rep,name=models,proto3" json:"models,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckEngineRequest) Reset() {
-	*x = CheckEngineRequest{}
-	mi := &file_Api_proto_msgTypes[10]
+func (x *ListModelsResponse) Reset() {
+	*x = ListModelsResponse{}
+	mi := &file_Api_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckEngineRequest) String() string {
+func (x *ListModelsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckEngineRequest) ProtoMessage() {}
+func (*ListModelsResponse) ProtoMessage() {}
+
+func (x *ListModelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModelsResponse.ProtoReflect.Descriptor instead.
+func (*ListModelsResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListModelsResponse) GetModels() []*ModelInfo {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type ModelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelRequest) Reset() {
+	*x = ModelRequest{}
+	mi := &file_Api_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelRequest) ProtoMessage() {}
+
+func (x *ModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelRequest.ProtoReflect.Descriptor instead.
+func (*ModelRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ModelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteModelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteModelResponse) Reset() {
+	*x = DeleteModelResponse{}
+	mi := &file_Api_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteModelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteModelResponse) ProtoMessage() {}
+
+func (x *DeleteModelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteModelResponse.ProtoReflect.Descriptor instead.
+func (*DeleteModelResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *DeleteModelResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteModelResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type FileInfo struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size     int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	FileType string                 `protobuf:"bytes,3,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	// 可选，上传完成后校验的文件 SHA-256（十六进制）
+	Sha256 string `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// 续传时已上传的字节数，需与 GetUploadOffset 返回值一致
+	Offset int64 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	// 可选，与模型一起保存的 YAML 清单
+	Manifest      string `protobuf:"bytes,6,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileInfo) Reset() {
+	*x = FileInfo{}
+	mi := &file_Api_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInfo) ProtoMessage() {}
+
+func (x *FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *FileInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileInfo) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *FileInfo) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *FileInfo) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *FileInfo) GetManifest() string {
+	if x != nil {
+		return x.Manifest
+	}
+	return ""
+}
+
+type UploadFileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadFileRequest_FileInfo
+	//	*UploadFileRequest_ChunkData
+	Data          isUploadFileRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadFileRequest) Reset() {
+	*x = UploadFileRequest{}
+	mi := &file_Api_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileRequest) ProtoMessage() {}
+
+func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileRequest.ProtoReflect.Descriptor instead.
+func (*UploadFileRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *UploadFileRequest) GetData() isUploadFileRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadFileRequest) GetFileInfo() *FileInfo {
+	if x != nil {
+		if x, ok := x.Data.(*UploadFileRequest_FileInfo); ok {
+			return x.FileInfo
+		}
+	}
+	return nil
+}
 
-func (x *CheckEngineRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_Api_proto_msgTypes[10]
+func (x *UploadFileRequest) GetChunkData() []byte {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		if x, ok := x.Data.(*UploadFileRequest_ChunkData); ok {
+			return x.ChunkData
 		}
-		return ms
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use CheckEngineRequest.ProtoReflect.Descriptor instead.
-func (*CheckEngineRequest) Descriptor() ([]byte, []int) {
-	return file_Api_proto_rawDescGZIP(), []int{10}
+type isUploadFileRequest_Data interface {
+	isUploadFileRequest_Data()
 }
 
-func (x *CheckEngineRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+type UploadFileRequest_FileInfo struct {
+	FileInfo *FileInfo `protobuf:"bytes,1,opt,name=file_info,json=fileInfo,proto3,oneof"`
 }
 
-type CheckEngineResponse struct {
+type UploadFileRequest_ChunkData struct {
+	ChunkData []byte `protobuf:"bytes,2,opt,name=chunk_data,json=chunkData,proto3,oneof"`
+}
+
+func (*UploadFileRequest_FileInfo) isUploadFileRequest_Data() {}
+
+func (*UploadFileRequest_ChunkData) isUploadFileRequest_Data() {}
+
+type UploadFileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	EngineInfo    *EngineInfo            `protobuf:"bytes,2,opt,name=engine_info,json=engineInfo,proto3" json:"engine_info,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	FilePath      string                 `protobuf:"bytes,3,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        string                 `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckEngineResponse) Reset() {
-	*x = CheckEngineResponse{}
-	mi := &file_Api_proto_msgTypes[11]
+func (x *UploadFileResponse) Reset() {
+	*x = UploadFileResponse{}
+	mi := &file_Api_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckEngineResponse) String() string {
+func (x *UploadFileResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckEngineResponse) ProtoMessage() {}
+func (*UploadFileResponse) ProtoMessage() {}
 
-func (x *CheckEngineResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_Api_proto_msgTypes[11]
+func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -748,56 +5210,73 @@ func (x *CheckEngineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckEngineResponse.ProtoReflect.Descriptor instead.
-func (*CheckEngineResponse) Descriptor() ([]byte, []int) {
-	return file_Api_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use UploadFileResponse.ProtoReflect.Descriptor instead.
+func (*UploadFileResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *CheckEngineResponse) GetSuccess() bool {
+func (x *UploadFileResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *CheckEngineResponse) GetEngineInfo() *EngineInfo {
+func (x *UploadFileResponse) GetMessage() string {
 	if x != nil {
-		return x.EngineInfo
+		return x.Message
 	}
-	return nil
+	return ""
 }
 
-func (x *CheckEngineResponse) GetMessage() string {
+func (x *UploadFileResponse) GetFilePath() string {
 	if x != nil {
-		return x.Message
+		return x.FilePath
 	}
 	return ""
 }
 
-type CheckAllEngineResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Engines       []*EngineInfo          `protobuf:"bytes,2,rep,name=engines,proto3" json:"engines,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+func (x *UploadFileResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadFileResponse) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type DownloadModelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// 从 offset 开始下载 length 字节，length 为 0 时下载到文件末尾
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length int64 `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	// 每个数据块的字节数，为 0 时使用默认值
+	ChunkSize     int32 `protobuf:"varint,4,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckAllEngineResponse) Reset() {
-	*x = CheckAllEngineResponse{}
-	mi := &file_Api_proto_msgTypes[12]
+func (x *DownloadModelRequest) Reset() {
+	*x = DownloadModelRequest{}
+	mi := &file_Api_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckAllEngineResponse) String() string {
+func (x *DownloadModelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckAllEngineResponse) ProtoMessage() {}
+func (*DownloadModelRequest) ProtoMessage() {}
 
-func (x *CheckAllEngineResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_Api_proto_msgTypes[12]
+func (x *DownloadModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -808,56 +5287,67 @@ func (x *CheckAllEngineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckAllEngineResponse.ProtoReflect.Descriptor instead.
-func (*CheckAllEngineResponse) Descriptor() ([]byte, []int) {
-	return file_Api_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use DownloadModelRequest.ProtoReflect.Descriptor instead.
+func (*DownloadModelRequest) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *CheckAllEngineResponse) GetSuccess() bool {
+func (x *DownloadModelRequest) GetName() string {
 	if x != nil {
-		return x.Success
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-func (x *CheckAllEngineResponse) GetEngines() []*EngineInfo {
+func (x *DownloadModelRequest) GetOffset() int64 {
 	if x != nil {
-		return x.Engines
+		return x.Offset
 	}
-	return nil
+	return 0
 }
 
-func (x *CheckAllEngineResponse) GetMessage() string {
+func (x *DownloadModelRequest) GetLength() int64 {
 	if x != nil {
-		return x.Message
+		return x.Length
 	}
-	return ""
+	return 0
 }
 
-type FileInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
-	FileType      string                 `protobuf:"bytes,3,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+func (x *DownloadModelRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+type DownloadTrailer struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 整个文件的大小和 SHA-256
+	Size   int64  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256 string `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// 本次发送的字节范围的 SHA-256
+	RangeOffset   int64  `protobuf:"varint,3,opt,name=range_offset,json=rangeOffset,proto3" json:"range_offset,omitempty"`
+	RangeLength   int64  `protobuf:"varint,4,opt,name=range_length,json=rangeLength,proto3" json:"range_length,omitempty"`
+	RangeSha256   string `protobuf:"bytes,5,opt,name=range_sha256,json=rangeSha256,proto3" json:"range_sha256,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FileInfo) Reset() {
-	*x = FileInfo{}
-	mi := &file_Api_proto_msgTypes[13]
+func (x *DownloadTrailer) Reset() {
+	*x = DownloadTrailer{}
+	mi := &file_Api_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FileInfo) String() string {
+func (x *DownloadTrailer) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FileInfo) ProtoMessage() {}
+func (*DownloadTrailer) ProtoMessage() {}
 
-func (x *FileInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_Api_proto_msgTypes[13]
+func (x *DownloadTrailer) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -868,58 +5358,74 @@ func (x *FileInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
-func (*FileInfo) Descriptor() ([]byte, []int) {
-	return file_Api_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use DownloadTrailer.ProtoReflect.Descriptor instead.
+func (*DownloadTrailer) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *FileInfo) GetName() string {
+func (x *DownloadTrailer) GetSize() int64 {
 	if x != nil {
-		return x.Name
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DownloadTrailer) GetSha256() string {
+	if x != nil {
+		return x.Sha256
 	}
 	return ""
 }
 
-func (x *FileInfo) GetSize() int64 {
+func (x *DownloadTrailer) GetRangeOffset() int64 {
 	if x != nil {
-		return x.Size
+		return x.RangeOffset
 	}
 	return 0
 }
 
-func (x *FileInfo) GetFileType() string {
+func (x *DownloadTrailer) GetRangeLength() int64 {
 	if x != nil {
-		return x.FileType
+		return x.RangeLength
+	}
+	return 0
+}
+
+func (x *DownloadTrailer) GetRangeSha256() string {
+	if x != nil {
+		return x.RangeSha256
 	}
 	return ""
 }
 
-type UploadFileRequest struct {
+// DownloadModelResponse 与 UploadFileRequest 的格式一致：先发送 file_info，再发送数据块，最后发送 trailer
+type DownloadModelResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Data:
 	//
-	//	*UploadFileRequest_FileInfo
-	//	*UploadFileRequest_ChunkData
-	Data          isUploadFileRequest_Data `protobuf_oneof:"data"`
+	//	*DownloadModelResponse_FileInfo
+	//	*DownloadModelResponse_ChunkData
+	//	*DownloadModelResponse_Trailer
+	Data          isDownloadModelResponse_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UploadFileRequest) Reset() {
-	*x = UploadFileRequest{}
-	mi := &file_Api_proto_msgTypes[14]
+func (x *DownloadModelResponse) Reset() {
+	*x = DownloadModelResponse{}
+	mi := &file_Api_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UploadFileRequest) String() string {
+func (x *DownloadModelResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadFileRequest) ProtoMessage() {}
+func (*DownloadModelResponse) ProtoMessage() {}
 
-func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_Api_proto_msgTypes[14]
+func (x *DownloadModelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -930,76 +5436,90 @@ func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadFileRequest.ProtoReflect.Descriptor instead.
-func (*UploadFileRequest) Descriptor() ([]byte, []int) {
-	return file_Api_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use DownloadModelResponse.ProtoReflect.Descriptor instead.
+func (*DownloadModelResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *UploadFileRequest) GetData() isUploadFileRequest_Data {
+func (x *DownloadModelResponse) GetData() isDownloadModelResponse_Data {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-func (x *UploadFileRequest) GetFileInfo() *FileInfo {
+func (x *DownloadModelResponse) GetFileInfo() *FileInfo {
 	if x != nil {
-		if x, ok := x.Data.(*UploadFileRequest_FileInfo); ok {
+		if x, ok := x.Data.(*DownloadModelResponse_FileInfo); ok {
 			return x.FileInfo
 		}
 	}
 	return nil
 }
 
-func (x *UploadFileRequest) GetChunkData() []byte {
+func (x *DownloadModelResponse) GetChunkData() []byte {
 	if x != nil {
-		if x, ok := x.Data.(*UploadFileRequest_ChunkData); ok {
+		if x, ok := x.Data.(*DownloadModelResponse_ChunkData); ok {
 			return x.ChunkData
 		}
 	}
 	return nil
 }
 
-type isUploadFileRequest_Data interface {
-	isUploadFileRequest_Data()
+func (x *DownloadModelResponse) GetTrailer() *DownloadTrailer {
+	if x != nil {
+		if x, ok := x.Data.(*DownloadModelResponse_Trailer); ok {
+			return x.Trailer
+		}
+	}
+	return nil
 }
 
-type UploadFileRequest_FileInfo struct {
+type isDownloadModelResponse_Data interface {
+	isDownloadModelResponse_Data()
+}
+
+type DownloadModelResponse_FileInfo struct {
 	FileInfo *FileInfo `protobuf:"bytes,1,opt,name=file_info,json=fileInfo,proto3,oneof"`
 }
 
-type UploadFileRequest_ChunkData struct {
+type DownloadModelResponse_ChunkData struct {
 	ChunkData []byte `protobuf:"bytes,2,opt,name=chunk_data,json=chunkData,proto3,oneof"`
 }
 
-func (*UploadFileRequest_FileInfo) isUploadFileRequest_Data() {}
+type DownloadModelResponse_Trailer struct {
+	Trailer *DownloadTrailer `protobuf:"bytes,3,opt,name=trailer,proto3,oneof"`
+}
 
-func (*UploadFileRequest_ChunkData) isUploadFileRequest_Data() {}
+func (*DownloadModelResponse_FileInfo) isDownloadModelResponse_Data() {}
 
-type UploadFileResponse struct {
+func (*DownloadModelResponse_ChunkData) isDownloadModelResponse_Data() {}
+
+func (*DownloadModelResponse_Trailer) isDownloadModelResponse_Data() {}
+
+type UploadOffsetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	FilePath      string                 `protobuf:"bytes,3,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UploadFileResponse) Reset() {
-	*x = UploadFileResponse{}
-	mi := &file_Api_proto_msgTypes[15]
+func (x *UploadOffsetResponse) Reset() {
+	*x = UploadOffsetResponse{}
+	mi := &file_Api_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UploadFileResponse) String() string {
+func (x *UploadOffsetResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadFileResponse) ProtoMessage() {}
+func (*UploadOffsetResponse) ProtoMessage() {}
 
-func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_Api_proto_msgTypes[15]
+func (x *UploadOffsetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_Api_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1010,37 +5530,30 @@ func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadFileResponse.ProtoReflect.Descriptor instead.
-func (*UploadFileResponse) Descriptor() ([]byte, []int) {
-	return file_Api_proto_rawDescGZIP(), []int{15}
-}
-
-func (x *UploadFileResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Deprecated: Use UploadOffsetResponse.ProtoReflect.Descriptor instead.
+func (*UploadOffsetResponse) Descriptor() ([]byte, []int) {
+	return file_Api_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *UploadFileResponse) GetMessage() string {
+func (x *UploadOffsetResponse) GetName() string {
 	if x != nil {
-		return x.Message
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UploadFileResponse) GetFilePath() string {
+func (x *UploadOffsetResponse) GetOffset() int64 {
 	if x != nil {
-		return x.FilePath
+		return x.Offset
 	}
-	return ""
+	return 0
 }
 
 var File_Api_proto protoreflect.FileDescriptor
 
 const file_Api_proto_rawDesc = "" +
 	"\n" +
-	"\tApi.proto\x12\x05proto\x1a\x1bgoogle/protobuf/empty.proto\"\xdf\x01\n" +
+	"\tApi.proto\x12\x05proto\x1a\x1bgoogle/protobuf/empty.proto\"\xe2\x05\n" +
 	"\n" +
 	"EngineInfo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
@@ -1054,17 +5567,35 @@ const file_Api_proto_rawDesc = "" +
 	"confidence\x18\x06 \x01(\x02R\n" +
 	"confidence\x12\x10\n" +
 	"\x03iou\x18\a \x01(\x02R\x03iou\x12\x17\n" +
-	"\ause_gpu\x18\b \x01(\bR\x06useGpu\"&\n" +
+	"\ause_gpu\x18\b \x01(\bR\x06useGpu\x12(\n" +
+	"\x05state\x18\t \x01(\x0e2\x12.proto.EngineStateR\x05state\x12\x1b\n" +
+	"\tin_flight\x18\n" +
+	" \x01(\x05R\binFlight\x12(\n" +
+	"\x10idle_ttl_seconds\x18\v \x01(\x05R\x0eidleTtlSeconds\x12,\n" +
+	"\x12memory_estimate_mb\x18\f \x01(\x03R\x10memoryEstimateMb\x12)\n" +
+	"\x11last_used_unix_ms\x18\r \x01(\x03R\x0elastUsedUnixMs\x12#\n" +
+	"\rlease_seconds\x18\x0e \x01(\x05R\fleaseSeconds\x121\n" +
+	"\x15lease_expires_unix_ms\x18\x0f \x01(\x03R\x12leaseExpiresUnixMs\x12\x16\n" +
+	"\x06shared\x18\x10 \x01(\bR\x06shared\x12 \n" +
+	"\vfingerprint\x18\x11 \x01(\tR\vfingerprint\x12\x1f\n" +
+	"\vshare_count\x18\x12 \x01(\x05R\n" +
+	"shareCount\x12\x1c\n" +
+	"\tpreloaded\x18\x13 \x01(\bR\tpreloaded\x12'\n" +
+	"\x0fconfig_revision\x18\x14 \x01(\x03R\x0econfigRevision\x12\x18\n" +
+	"\amembers\x18\x15 \x03(\tR\amembers\x12#\n" +
+	"\rfusion_method\x18\x16 \x01(\tR\ffusionMethod\"&\n" +
 	"\bPosition\x12\f\n" +
 	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
-	"\x01y\x18\x02 \x01(\x05R\x01y\"\x8e\x01\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\"\xc6\x01\n" +
 	"\fSingleResult\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
 	"\n" +
 	"confidence\x18\x02 \x01(\x02R\n" +
 	"confidence\x12!\n" +
 	"\x03box\x18\x03 \x03(\v2\x0f.proto.PositionR\x03box\x12'\n" +
-	"\x06center\x18\x04 \x01(\v2\x0f.proto.PositionR\x06center\"\xf5\x01\n" +
+	"\x06center\x18\x04 \x01(\v2\x0f.proto.PositionR\x06center\x12\x19\n" +
+	"\btrack_id\x18\x05 \x01(\x03R\atrackId\x12\x1b\n" +
+	"\ttrack_age\x18\x06 \x01(\x05R\btrackAge\"\x8b\x04\n" +
 	"\x11InitEngineRequest\x12\x1f\n" +
 	"\vengine_type\x18\x01 \x01(\x05R\n" +
 	"engineType\x12\x1d\n" +
@@ -1078,7 +5609,19 @@ const file_Api_proto_rawDesc = "" +
 	"confidence\x12\x10\n" +
 	"\x03iou\x18\x06 \x01(\x02R\x03iou\x12\x17\n" +
 	"\ause_gpu\x18\a \x01(\bR\x06useGpu\x12 \n" +
-	"\vdescription\x18\b \x01(\tR\vdescription\"X\n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12'\n" +
+	"\x0fisolation_group\x18\t \x01(\tR\x0eisolationGroup\x12(\n" +
+	"\x10idle_ttl_seconds\x18\n" +
+	" \x01(\x05R\x0eidleTtlSeconds\x12#\n" +
+	"\rlease_seconds\x18\v \x01(\x05R\fleaseSeconds\x12\x14\n" +
+	"\x05share\x18\f \x01(\bR\x05share\x12'\n" +
+	"\x0fidempotency_key\x18\r \x01(\tR\x0eidempotencyKey\x12\x1d\n" +
+	"\n" +
+	"input_blob\x18\x0e \x01(\tR\tinputBlob\x12\x1f\n" +
+	"\voutput_blob\x18\x0f \x01(\tR\n" +
+	"outputBlob\x12\x1d\n" +
+	"\n" +
+	"names_file\x18\x10 \x01(\tR\tnamesFile\"X\n" +
 	"\x12InitEngineResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12\x18\n" +
@@ -1087,15 +5630,105 @@ const file_Api_proto_rawDesc = "" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12\x14\n" +
 	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
 	"\x06height\x18\x03 \x01(\x05R\x06height\x12\x1a\n" +
-	"\bchannels\x18\x04 \x01(\x05R\bchannels\"O\n" +
+	"\bchannels\x18\x04 \x01(\x05R\bchannels\"\xc7\x01\n" +
 	"\x10InferenceRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
-	"\bimg_data\x18\x02 \x01(\v2\x10.proto.ImageDataR\aimgData\"\\\n" +
+	"\bimg_data\x18\x02 \x01(\v2\x10.proto.ImageDataR\aimgData\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12.\n" +
+	"\atracker\x18\x04 \x01(\v2\x14.proto.TrackerConfigR\atracker\x12'\n" +
+	"\x05zones\x18\x05 \x01(\v2\x11.proto.ZoneConfigR\x05zones\"\xc0\x01\n" +
+	"\rTrackerConfig\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x1f\n" +
+	"\vhigh_thresh\x18\x02 \x01(\x02R\n" +
+	"highThresh\x12\x1d\n" +
+	"\n" +
+	"low_thresh\x18\x03 \x01(\x02R\tlowThresh\x12\x1b\n" +
+	"\tmatch_iou\x18\x04 \x01(\x02R\bmatchIou\x12\x19\n" +
+	"\bmax_lost\x18\x05 \x01(\x05R\amaxLost\x12\x1f\n" +
+	"\vcross_class\x18\x06 \x01(\bR\n" +
+	"crossClass\"a\n" +
+	"\n" +
+	"TrackEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x19\n" +
+	"\btrack_id\x18\x02 \x01(\x03R\atrackId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x10\n" +
+	"\x03age\x18\x04 \x01(\x05R\x03age\"\x89\x01\n" +
+	"\bZoneRule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12)\n" +
+	"\apolygon\x18\x02 \x03(\v2\x0f.proto.PositionR\apolygon\x12\x18\n" +
+	"\aclasses\x18\x03 \x03(\tR\aclasses\x12$\n" +
+	"\x0edwell_alert_ms\x18\x04 \x01(\x03R\fdwellAlertMs\"~\n" +
+	"\bLineRule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12#\n" +
+	"\x04from\x18\x02 \x01(\v2\x0f.proto.PositionR\x04from\x12\x1f\n" +
+	"\x02to\x18\x03 \x01(\v2\x0f.proto.PositionR\x02to\x12\x18\n" +
+	"\aclasses\x18\x04 \x03(\tR\aclasses\"Z\n" +
+	"\n" +
+	"ZoneConfig\x12%\n" +
+	"\x05zones\x18\x01 \x03(\v2\x0f.proto.ZoneRuleR\x05zones\x12%\n" +
+	"\x05lines\x18\x02 \x03(\v2\x0f.proto.LineRuleR\x05lines\"\xaa\x01\n" +
+	"\tZoneCount\x12\x12\n" +
+	"\x04zone\x18\x01 \x01(\tR\x04zone\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x127\n" +
+	"\aclasses\x18\x03 \x03(\v2\x1d.proto.ZoneCount.ClassesEntryR\aclasses\x1a:\n" +
+	"\fClassesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x9b\x01\n" +
+	"\tZoneEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04zone\x18\x02 \x01(\tR\x04zone\x12\x19\n" +
+	"\btrack_id\x18\x03 \x01(\x03R\atrackId\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1c\n" +
+	"\tdirection\x18\x05 \x01(\tR\tdirection\x12\x19\n" +
+	"\bdwell_ms\x18\x06 \x01(\x03R\adwellMs\"J\n" +
+	"\x0fSetZonesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x05zones\x18\x02 \x01(\v2\x11.proto.ZoneConfigR\x05zones\"F\n" +
+	"\x10SetZonesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xc5\x02\n" +
 	"\x11InferenceResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12-\n" +
-	"\aresults\x18\x02 \x03(\v2\x13.proto.SingleResultR\aresults\"&\n" +
+	"\aresults\x18\x02 \x03(\v2\x13.proto.SingleResultR\aresults\x12\x1b\n" +
+	"\tengine_id\x18\x03 \x01(\tR\bengineId\x12\x14\n" +
+	"\x05alias\x18\x04 \x01(\tR\x05alias\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x124\n" +
+	"\ftrack_events\x18\x06 \x03(\v2\x11.proto.TrackEventR\vtrackEvents\x121\n" +
+	"\vzone_counts\x18\a \x03(\v2\x10.proto.ZoneCountR\n" +
+	"zoneCounts\x121\n" +
+	"\vzone_events\x18\b \x03(\v2\x10.proto.ZoneEventR\n" +
+	"zoneEvents\"\xd6\x02\n" +
+	"\x16StreamInferenceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\rmax_in_flight\x18\x02 \x01(\x05R\vmaxInFlight\x12\x1f\n" +
+	"\vkeep_latest\x18\x03 \x01(\bR\n" +
+	"keepLatest\x12'\n" +
+	"\x10max_frame_age_ms\x18\x04 \x01(\x05R\rmaxFrameAgeMs\x12\x10\n" +
+	"\x03seq\x18\x05 \x01(\x03R\x03seq\x12&\n" +
+	"\x0fcapture_unix_ms\x18\x06 \x01(\x03R\rcaptureUnixMs\x12+\n" +
+	"\bimg_data\x18\a \x01(\v2\x10.proto.ImageDataR\aimgData\x12.\n" +
+	"\atracker\x18\b \x01(\v2\x14.proto.TrackerConfigR\atracker\x12'\n" +
+	"\x05zones\x18\t \x01(\v2\x11.proto.ZoneConfigR\x05zones\"\xd8\x01\n" +
+	"\x17StreamInferenceResponse\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x12&\n" +
+	"\x0fcapture_unix_ms\x18\x02 \x01(\x03R\rcaptureUnixMs\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\bR\adropped\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x120\n" +
+	"\x06result\x18\x05 \x01(\v2\x18.proto.InferenceResponseR\x06result\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x06 \x01(\x03R\tlatencyMs\"=\n" +
+	"\x1cDeleteTrackingSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"S\n" +
+	"\x1dDeleteTrackingSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"[\n" +
 	"\x14DestroyEngineRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"K\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x03 \x01(\x05R\ttimeoutMs\"K\n" +
 	"\x15DestroyEngineResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\"$\n" +
@@ -1105,33 +5738,336 @@ const file_Api_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x122\n" +
 	"\vengine_info\x18\x02 \x01(\v2\x11.proto.EngineInfoR\n" +
 	"engineInfo\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"y\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"w\n" +
+	"\x0eRestoreFailure\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"model_path\x18\x03 \x01(\tR\tmodelPath\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xfd\x01\n" +
 	"\x16CheckAllEngineResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12+\n" +
 	"\aengines\x18\x02 \x03(\v2\x11.proto.EngineInfoR\aengines\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"O\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12@\n" +
+	"\x10restore_failures\x18\x04 \x03(\v2\x15.proto.RestoreFailureR\x0frestoreFailures\x12@\n" +
+	"\x10preload_failures\x18\x05 \x03(\v2\x15.proto.RestoreFailureR\x0fpreloadFailures\"J\n" +
+	"\x11RenewLeaseRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12#\n" +
+	"\rlease_seconds\x18\x02 \x01(\x05R\fleaseSeconds\"y\n" +
+	"\vLeaseStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\arenewed\x18\x02 \x01(\bR\arenewed\x12&\n" +
+	"\x0fexpires_unix_ms\x18\x03 \x01(\x03R\rexpiresUnixMs\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"t\n" +
+	"\x12RenewLeaseResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12*\n" +
+	"\x06leases\x18\x02 \x03(\v2\x12.proto.LeaseStatusR\x06leases\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x98\x01\n" +
+	"\x13ReloadEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"model_path\x18\x02 \x01(\tR\tmodelPath\x123\n" +
+	"\fwarmup_image\x18\x03 \x01(\v2\x10.proto.ImageDataR\vwarmupImage\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x04 \x01(\x05R\ttimeoutMs\"Z\n" +
+	"\x14ReloadEngineResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xc1\x01\n" +
+	"\x13UpdateEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\n" +
+	"confidence\x18\x02 \x01(\x02H\x00R\n" +
+	"confidence\x88\x01\x01\x12\x15\n" +
+	"\x03iou\x18\x03 \x01(\x02H\x01R\x03iou\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"input_size\x18\x04 \x01(\x05H\x02R\tinputSize\x88\x01\x01\x12\x14\n" +
+	"\x05names\x18\x05 \x03(\tR\x05namesB\r\n" +
+	"\v_confidenceB\x06\n" +
+	"\x04_iouB\r\n" +
+	"\v_input_size\"\xa4\x01\n" +
+	"\x14UpdateEngineResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
+	"\rreinitialized\x18\x03 \x01(\bR\rreinitialized\x122\n" +
+	"\vengine_info\x18\x04 \x01(\v2\x11.proto.EngineInfoR\n" +
+	"engineInfo\"\\\n" +
+	"\vAliasTarget\x12\x1b\n" +
+	"\tengine_id\x18\x01 \x01(\tR\bengineId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\rR\x06weight\"q\n" +
+	"\x05Alias\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12,\n" +
+	"\atargets\x18\x02 \x03(\v2\x12.proto.AliasTargetR\atargets\x12&\n" +
+	"\x0fupdated_unix_ms\x18\x03 \x01(\x03R\rupdatedUnixMs\"S\n" +
+	"\x0fSetAliasRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12,\n" +
+	"\atargets\x18\x02 \x03(\v2\x12.proto.AliasTargetR\atargets\"(\n" +
+	"\x12DeleteAliasRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"g\n" +
+	"\rAliasResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\"\n" +
+	"\x05alias\x18\x03 \x01(\v2\f.proto.AliasR\x05alias\"=\n" +
+	"\x13ListAliasesResponse\x12&\n" +
+	"\aaliases\x18\x01 \x03(\v2\f.proto.AliasR\aaliases\"\x85\x01\n" +
+	"\x10SetShadowRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tshadow_id\x18\x02 \x01(\tR\bshadowId\x12\x1f\n" +
+	"\vsample_rate\x18\x03 \x01(\x02R\n" +
+	"sampleRate\x12#\n" +
+	"\riou_threshold\x18\x04 \x01(\x02R\fiouThreshold\"G\n" +
+	"\x11SetShadowResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"$\n" +
+	"\x12ShadowStatsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xe0\x01\n" +
+	"\x0eClassAgreement\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\amatched\x18\x02 \x01(\x03R\amatched\x12\x14\n" +
+	"\x05extra\x18\x03 \x01(\x03R\x05extra\x12\x18\n" +
+	"\amissing\x18\x04 \x01(\x03R\amissing\x12&\n" +
+	"\x0fmean_conf_delta\x18\x05 \x01(\x02R\rmeanConfDelta\x12-\n" +
+	"\x13mean_abs_conf_delta\x18\x06 \x01(\x02R\x10meanAbsConfDelta\x12\x19\n" +
+	"\bmean_iou\x18\a \x01(\x02R\ameanIou\"\xab\x02\n" +
+	"\x13ShadowStatsResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tshadow_id\x18\x02 \x01(\tR\bshadowId\x12\x1f\n" +
+	"\vsample_rate\x18\x03 \x01(\x02R\n" +
+	"sampleRate\x12#\n" +
+	"\riou_threshold\x18\x04 \x01(\x02R\fiouThreshold\x12\x1a\n" +
+	"\bcompared\x18\x05 \x01(\x03R\bcompared\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x03R\x06failed\x12\x18\n" +
+	"\adropped\x18\a \x01(\x03R\adropped\x12/\n" +
+	"\aclasses\x18\b \x03(\v2\x15.proto.ClassAgreementR\aclasses\x12\"\n" +
+	"\rsince_unix_ms\x18\t \x01(\x03R\vsinceUnixMs\"\xc4\x01\n" +
+	"\x0eEnsembleMember\x12\x1b\n" +
+	"\tengine_id\x18\x01 \x01(\tR\bengineId\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x02R\x06weight\x12@\n" +
+	"\tclass_map\x18\x03 \x03(\v2#.proto.EnsembleMember.ClassMapEntryR\bclassMap\x1a;\n" +
+	"\rClassMapEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xce\x01\n" +
+	"\x15CreateEnsembleRequest\x12/\n" +
+	"\amembers\x18\x01 \x03(\v2\x15.proto.EnsembleMemberR\amembers\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12#\n" +
+	"\riou_threshold\x18\x03 \x01(\x02R\fiouThreshold\x12%\n" +
+	"\x0eskip_threshold\x18\x04 \x01(\x02R\rskipThreshold\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\"V\n" +
+	"\x15MultiInferenceRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12+\n" +
+	"\bimg_data\x18\x02 \x01(\v2\x10.proto.ImageDataR\aimgData\"\xca\x01\n" +
+	"\fEngineResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12-\n" +
+	"\aresults\x18\x04 \x03(\v2\x13.proto.SingleResultR\aresults\x12\x1b\n" +
+	"\tengine_id\x18\x05 \x01(\tR\bengineId\x12\x14\n" +
+	"\x05alias\x18\x06 \x01(\tR\x05alias\x12\x18\n" +
+	"\aversion\x18\a \x01(\tR\aversion\"a\n" +
+	"\x16MultiInferenceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12-\n" +
+	"\aresults\x18\x02 \x03(\v2\x13.proto.EngineResultR\aresults\"G\n" +
+	"\x17RegisterPipelineRequest\x12\x12\n" +
+	"\x04yaml\x18\x01 \x01(\tR\x04yaml\x12\x18\n" +
+	"\areplace\x18\x02 \x01(\bR\areplace\"Z\n" +
+	"\x10PipelineResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"+\n" +
+	"\x15DeletePipelineRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"h\n" +
+	"\fPipelineInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06stages\x18\x02 \x03(\tR\x06stages\x12\x18\n" +
+	"\aengines\x18\x03 \x03(\tR\aengines\x12\x12\n" +
+	"\x04yaml\x18\x04 \x01(\tR\x04yaml\"J\n" +
+	"\x15ListPipelinesResponse\x121\n" +
+	"\tpipelines\x18\x01 \x03(\v2\x13.proto.PipelineInfoR\tpipelines\"U\n" +
+	"\x12RunPipelineRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12+\n" +
+	"\bimg_data\x18\x02 \x01(\v2\x10.proto.ImageDataR\aimgData\"Y\n" +
+	"\rPipelineLabel\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x02R\n" +
+	"confidence\"\x8d\x02\n" +
+	"\x11PipelineDetection\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x02R\n" +
+	"confidence\x12!\n" +
+	"\x03box\x18\x04 \x03(\v2\x0f.proto.PositionR\x03box\x12'\n" +
+	"\x06center\x18\x05 \x01(\v2\x0f.proto.PositionR\x06center\x12,\n" +
+	"\x06labels\x18\x06 \x03(\v2\x14.proto.PipelineLabelR\x06labels\x124\n" +
+	"\bchildren\x18\a \x03(\v2\x18.proto.PipelineDetectionR\bchildren\"\x83\x01\n" +
+	"\x13RunPipelineResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x128\n" +
+	"\n" +
+	"detections\x18\x03 \x03(\v2\x18.proto.PipelineDetectionR\n" +
+	"detections\"k\n" +
+	"\n" +
+	"Preprocess\x12\x12\n" +
+	"\x04mean\x18\x01 \x03(\x02R\x04mean\x12\x12\n" +
+	"\x04norm\x18\x02 \x03(\x02R\x04norm\x12\x17\n" +
+	"\aswap_rb\x18\x03 \x01(\bR\x06swapRb\x12\x1c\n" +
+	"\tletterbox\x18\x04 \x01(\bR\tletterbox\"\xfd\x01\n" +
+	"\rModelManifest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\x12\x1d\n" +
+	"\n" +
+	"input_size\x18\x02 \x01(\x05R\tinputSize\x12\x1d\n" +
+	"\n" +
+	"input_blob\x18\x03 \x01(\tR\tinputBlob\x12\x1f\n" +
+	"\voutput_blob\x18\x04 \x01(\tR\n" +
+	"outputBlob\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x05 \x01(\x02R\n" +
+	"confidence\x12\x10\n" +
+	"\x03iou\x18\x06 \x01(\x02R\x03iou\x12\x12\n" +
+	"\x04task\x18\a \x01(\tR\x04task\x121\n" +
+	"\n" +
+	"preprocess\x18\b \x01(\v2\x11.proto.PreprocessR\n" +
+	"preprocess\"4\n" +
+	"\x13InspectModelRequest\x12\x1d\n" +
+	"\n" +
+	"model_path\x18\x01 \x01(\tR\tmodelPath\"r\n" +
+	"\n" +
+	"TensorInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\telem_type\x18\x02 \x01(\tR\belemType\x12\x14\n" +
+	"\x05shape\x18\x03 \x03(\x03R\x05shape\x12\x1d\n" +
+	"\n" +
+	"dim_params\x18\x04 \x03(\tR\tdimParams\"=\n" +
+	"\tOpsetInfo\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"a\n" +
+	"\tNcnnLayer\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\abottoms\x18\x03 \x03(\tR\abottoms\x12\x12\n" +
+	"\x04tops\x18\x04 \x03(\tR\x04tops\"\xd8\x04\n" +
+	"\x14InspectModelResponse\x12\x1d\n" +
+	"\n" +
+	"model_path\x18\x01 \x01(\tR\tmodelPath\x12\x1d\n" +
+	"\n" +
+	"ir_version\x18\x02 \x01(\x03R\tirVersion\x12#\n" +
+	"\rproducer_name\x18\x03 \x01(\tR\fproducerName\x12)\n" +
+	"\x10producer_version\x18\x04 \x01(\tR\x0fproducerVersion\x12(\n" +
+	"\x06opsets\x18\x05 \x03(\v2\x10.proto.OpsetInfoR\x06opsets\x12)\n" +
+	"\x06inputs\x18\x06 \x03(\v2\x11.proto.TensorInfoR\x06inputs\x12+\n" +
+	"\aoutputs\x18\a \x03(\v2\x11.proto.TensorInfoR\aoutputs\x12E\n" +
+	"\bmetadata\x18\b \x03(\v2).proto.InspectModelResponse.MetadataEntryR\bmetadata\x12\x14\n" +
+	"\x05names\x18\t \x03(\tR\x05names\x12\x1d\n" +
+	"\n" +
+	"input_size\x18\n" +
+	" \x01(\x05R\tinputSize\x12\x16\n" +
+	"\x06stride\x18\v \x01(\x05R\x06stride\x12\x1f\n" +
+	"\vnames_error\x18\f \x01(\tR\n" +
+	"namesError\x12(\n" +
+	"\x06layers\x18\r \x03(\v2\x10.proto.NcnnLayerR\x06layers\x12\x14\n" +
+	"\x05blobs\x18\x0e \x03(\tR\x05blobs\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x99\x02\n" +
+	"\tModelInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\x12(\n" +
+	"\x10uploaded_unix_ms\x18\x05 \x01(\x03R\x0euploadedUnixMs\x12\x16\n" +
+	"\x06format\x18\x06 \x01(\tR\x06format\x12\x1d\n" +
+	"\n" +
+	"engine_ids\x18\a \x03(\tR\tengineIds\x120\n" +
+	"\bmanifest\x18\b \x01(\v2\x14.proto.ModelManifestR\bmanifest\x12%\n" +
+	"\x0emanifest_error\x18\t \x01(\tR\rmanifestError\">\n" +
+	"\x12ListModelsResponse\x12(\n" +
+	"\x06models\x18\x01 \x03(\v2\x10.proto.ModelInfoR\x06models\"\"\n" +
+	"\fModelRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"I\n" +
+	"\x13DeleteModelResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9b\x01\n" +
 	"\bFileInfo\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
 	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x1b\n" +
-	"\tfile_type\x18\x03 \x01(\tR\bfileType\"l\n" +
+	"\tfile_type\x18\x03 \x01(\tR\bfileType\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x03R\x06offset\x12\x1a\n" +
+	"\bmanifest\x18\x06 \x01(\tR\bmanifest\"l\n" +
 	"\x11UploadFileRequest\x12.\n" +
 	"\tfile_info\x18\x01 \x01(\v2\x0f.proto.FileInfoH\x00R\bfileInfo\x12\x1f\n" +
 	"\n" +
 	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkDataB\x06\n" +
-	"\x04data\"e\n" +
+	"\x04data\"\x91\x01\n" +
 	"\x12UploadFileResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
-	"\tfile_path\x18\x03 \x01(\tR\bfilePath2\xef\x03\n" +
+	"\tfile_path\x18\x03 \x01(\tR\bfilePath\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\tR\x06sha256\"y\n" +
+	"\x14DownloadModelRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x03R\x06length\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x04 \x01(\x05R\tchunkSize\"\xa6\x01\n" +
+	"\x0fDownloadTrailer\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\x12!\n" +
+	"\frange_offset\x18\x03 \x01(\x03R\vrangeOffset\x12!\n" +
+	"\frange_length\x18\x04 \x01(\x03R\vrangeLength\x12!\n" +
+	"\frange_sha256\x18\x05 \x01(\tR\vrangeSha256\"\xa4\x01\n" +
+	"\x15DownloadModelResponse\x12.\n" +
+	"\tfile_info\x18\x01 \x01(\v2\x0f.proto.FileInfoH\x00R\bfileInfo\x12\x1f\n" +
+	"\n" +
+	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkData\x122\n" +
+	"\atrailer\x18\x03 \x01(\v2\x16.proto.DownloadTrailerH\x00R\atrailerB\x06\n" +
+	"\x04data\"B\n" +
+	"\x14UploadOffsetResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset*]\n" +
+	"\vEngineState\x12\x17\n" +
+	"\x13ENGINE_STATE_ACTIVE\x10\x00\x12\x19\n" +
+	"\x15ENGINE_STATE_DRAINING\x10\x01\x12\x1a\n" +
+	"\x16ENGINE_STATE_DESTROYED\x10\x022\x92\x11\n" +
 	"\rDetectService\x12A\n" +
 	"\n" +
 	"InitEngine\x12\x18.proto.InitEngineRequest\x1a\x19.proto.InitEngineResponse\x12>\n" +
-	"\tInference\x12\x17.proto.InferenceRequest\x1a\x18.proto.InferenceResponse\x12J\n" +
+	"\tInference\x12\x17.proto.InferenceRequest\x1a\x18.proto.InferenceResponse\x12T\n" +
+	"\x0fStreamInference\x12\x1d.proto.StreamInferenceRequest\x1a\x1e.proto.StreamInferenceResponse(\x010\x01\x12b\n" +
+	"\x15DeleteTrackingSession\x12#.proto.DeleteTrackingSessionRequest\x1a$.proto.DeleteTrackingSessionResponse\x12;\n" +
+	"\bSetZones\x12\x16.proto.SetZonesRequest\x1a\x17.proto.SetZonesResponse\x12J\n" +
 	"\rDestroyEngine\x12\x1b.proto.DestroyEngineRequest\x1a\x1c.proto.DestroyEngineResponse\x12D\n" +
 	"\vCheckEngine\x12\x19.proto.CheckEngineRequest\x1a\x1a.proto.CheckEngineResponse\x12G\n" +
 	"\x0eCheckAllEngine\x12\x16.google.protobuf.Empty\x1a\x1d.proto.CheckAllEngineResponse\x12:\n" +
 	"\bShutdown\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\x12D\n" +
-	"\vUploadModel\x12\x18.proto.UploadFileRequest\x1a\x19.proto.UploadFileResponse(\x01B\n" +
+	"\vUploadModel\x12\x18.proto.UploadFileRequest\x1a\x19.proto.UploadFileResponse(\x01\x12C\n" +
+	"\x0fGetUploadOffset\x12\x13.proto.ModelRequest\x1a\x1b.proto.UploadOffsetResponse\x12L\n" +
+	"\rDownloadModel\x12\x1b.proto.DownloadModelRequest\x1a\x1c.proto.DownloadModelResponse0\x01\x12A\n" +
+	"\n" +
+	"RenewLease\x12\x18.proto.RenewLeaseRequest\x1a\x19.proto.RenewLeaseResponse\x12G\n" +
+	"\fReloadEngine\x12\x1a.proto.ReloadEngineRequest\x1a\x1b.proto.ReloadEngineResponse\x12G\n" +
+	"\fUpdateEngine\x12\x1a.proto.UpdateEngineRequest\x1a\x1b.proto.UpdateEngineResponse\x12;\n" +
+	"\vCreateAlias\x12\x16.proto.SetAliasRequest\x1a\x14.proto.AliasResponse\x12;\n" +
+	"\vUpdateAlias\x12\x16.proto.SetAliasRequest\x1a\x14.proto.AliasResponse\x12>\n" +
+	"\vDeleteAlias\x12\x19.proto.DeleteAliasRequest\x1a\x14.proto.AliasResponse\x12A\n" +
+	"\vListAliases\x12\x16.google.protobuf.Empty\x1a\x1a.proto.ListAliasesResponse\x12>\n" +
+	"\tSetShadow\x12\x17.proto.SetShadowRequest\x1a\x18.proto.SetShadowResponse\x12G\n" +
+	"\x0eGetShadowStats\x12\x19.proto.ShadowStatsRequest\x1a\x1a.proto.ShadowStatsResponse\x12I\n" +
+	"\x0eCreateEnsemble\x12\x1c.proto.CreateEnsembleRequest\x1a\x19.proto.InitEngineResponse\x12M\n" +
+	"\x0eMultiInference\x12\x1c.proto.MultiInferenceRequest\x1a\x1d.proto.MultiInferenceResponse\x12K\n" +
+	"\x10RegisterPipeline\x12\x1e.proto.RegisterPipelineRequest\x1a\x17.proto.PipelineResponse\x12G\n" +
+	"\x0eDeletePipeline\x12\x1c.proto.DeletePipelineRequest\x1a\x17.proto.PipelineResponse\x12E\n" +
+	"\rListPipelines\x12\x16.google.protobuf.Empty\x1a\x1c.proto.ListPipelinesResponse\x12D\n" +
+	"\vRunPipeline\x12\x19.proto.RunPipelineRequest\x1a\x1a.proto.RunPipelineResponse\x12?\n" +
+	"\n" +
+	"ListModels\x12\x16.google.protobuf.Empty\x1a\x19.proto.ListModelsResponse\x125\n" +
+	"\fGetModelInfo\x12\x13.proto.ModelRequest\x1a\x10.proto.ModelInfo\x12>\n" +
+	"\vDeleteModel\x12\x13.proto.ModelRequest\x1a\x1a.proto.DeleteModelResponse\x12G\n" +
+	"\fInspectModel\x12\x1a.proto.InspectModelRequest\x1a\x1b.proto.InspectModelResponseB\n" +
 	"Z\b./;protob\x06proto3"
 
 var (
@@ -1146,53 +6082,216 @@ func file_Api_proto_rawDescGZIP() []byte {
 	return file_Api_proto_rawDescData
 }
 
-var file_Api_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_Api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_Api_proto_msgTypes = make([]protoimpl.MessageInfo, 80)
 var file_Api_proto_goTypes = []any{
-	(*EngineInfo)(nil),             // 0: proto.EngineInfo
-	(*Position)(nil),               // 1: proto.Position
-	(*SingleResult)(nil),           // 2: proto.SingleResult
-	(*InitEngineRequest)(nil),      // 3: proto.InitEngineRequest
-	(*InitEngineResponse)(nil),     // 4: proto.InitEngineResponse
-	(*ImageData)(nil),              // 5: proto.ImageData
-	(*InferenceRequest)(nil),       // 6: proto.InferenceRequest
-	(*InferenceResponse)(nil),      // 7: proto.InferenceResponse
-	(*DestroyEngineRequest)(nil),   // 8: proto.DestroyEngineRequest
-	(*DestroyEngineResponse)(nil),  // 9: proto.DestroyEngineResponse
-	(*CheckEngineRequest)(nil),     // 10: proto.CheckEngineRequest
-	(*CheckEngineResponse)(nil),    // 11: proto.CheckEngineResponse
-	(*CheckAllEngineResponse)(nil), // 12: proto.CheckAllEngineResponse
-	(*FileInfo)(nil),               // 13: proto.FileInfo
-	(*UploadFileRequest)(nil),      // 14: proto.UploadFileRequest
-	(*UploadFileResponse)(nil),     // 15: proto.UploadFileResponse
-	(*emptypb.Empty)(nil),          // 16: google.protobuf.Empty
+	(EngineState)(0),                      // 0: proto.EngineState
+	(*EngineInfo)(nil),                    // 1: proto.EngineInfo
+	(*Position)(nil),                      // 2: proto.Position
+	(*SingleResult)(nil),                  // 3: proto.SingleResult
+	(*InitEngineRequest)(nil),             // 4: proto.InitEngineRequest
+	(*InitEngineResponse)(nil),            // 5: proto.InitEngineResponse
+	(*ImageData)(nil),                     // 6: proto.ImageData
+	(*InferenceRequest)(nil),              // 7: proto.InferenceRequest
+	(*TrackerConfig)(nil),                 // 8: proto.TrackerConfig
+	(*TrackEvent)(nil),                    // 9: proto.TrackEvent
+	(*ZoneRule)(nil),                      // 10: proto.ZoneRule
+	(*LineRule)(nil),                      // 11: proto.LineRule
+	(*ZoneConfig)(nil),                    // 12: proto.ZoneConfig
+	(*ZoneCount)(nil),                     // 13: proto.ZoneCount
+	(*ZoneEvent)(nil),                     // 14: proto.ZoneEvent
+	(*SetZonesRequest)(nil),               // 15: proto.SetZonesRequest
+	(*SetZonesResponse)(nil),              // 16: proto.SetZonesResponse
+	(*InferenceResponse)(nil),             // 17: proto.InferenceResponse
+	(*StreamInferenceRequest)(nil),        // 18: proto.StreamInferenceRequest
+	(*StreamInferenceResponse)(nil),       // 19: proto.StreamInferenceResponse
+	(*DeleteTrackingSessionRequest)(nil),  // 20: proto.DeleteTrackingSessionRequest
+	(*DeleteTrackingSessionResponse)(nil), // 21: proto.DeleteTrackingSessionResponse
+	(*DestroyEngineRequest)(nil),          // 22: proto.DestroyEngineRequest
+	(*DestroyEngineResponse)(nil),         // 23: proto.DestroyEngineResponse
+	(*CheckEngineRequest)(nil),            // 24: proto.CheckEngineRequest
+	(*CheckEngineResponse)(nil),           // 25: proto.CheckEngineResponse
+	(*RestoreFailure)(nil),                // 26: proto.RestoreFailure
+	(*CheckAllEngineResponse)(nil),        // 27: proto.CheckAllEngineResponse
+	(*RenewLeaseRequest)(nil),             // 28: proto.RenewLeaseRequest
+	(*LeaseStatus)(nil),                   // 29: proto.LeaseStatus
+	(*RenewLeaseResponse)(nil),            // 30: proto.RenewLeaseResponse
+	(*ReloadEngineRequest)(nil),           // 31: proto.ReloadEngineRequest
+	(*ReloadEngineResponse)(nil),          // 32: proto.ReloadEngineResponse
+	(*UpdateEngineRequest)(nil),           // 33: proto.UpdateEngineRequest
+	(*UpdateEngineResponse)(nil),          // 34: proto.UpdateEngineResponse
+	(*AliasTarget)(nil),                   // 35: proto.AliasTarget
+	(*Alias)(nil),                         // 36: proto.Alias
+	(*SetAliasRequest)(nil),               // 37: proto.SetAliasRequest
+	(*DeleteAliasRequest)(nil),            // 38: proto.DeleteAliasRequest
+	(*AliasResponse)(nil),                 // 39: proto.AliasResponse
+	(*ListAliasesResponse)(nil),           // 40: proto.ListAliasesResponse
+	(*SetShadowRequest)(nil),              // 41: proto.SetShadowRequest
+	(*SetShadowResponse)(nil),             // 42: proto.SetShadowResponse
+	(*ShadowStatsRequest)(nil),            // 43: proto.ShadowStatsRequest
+	(*ClassAgreement)(nil),                // 44: proto.ClassAgreement
+	(*ShadowStatsResponse)(nil),           // 45: proto.ShadowStatsResponse
+	(*EnsembleMember)(nil),                // 46: proto.EnsembleMember
+	(*CreateEnsembleRequest)(nil),         // 47: proto.CreateEnsembleRequest
+	(*MultiInferenceRequest)(nil),         // 48: proto.MultiInferenceRequest
+	(*EngineResult)(nil),                  // 49: proto.EngineResult
+	(*MultiInferenceResponse)(nil),        // 50: proto.MultiInferenceResponse
+	(*RegisterPipelineRequest)(nil),       // 51: proto.RegisterPipelineRequest
+	(*PipelineResponse)(nil),              // 52: proto.PipelineResponse
+	(*DeletePipelineRequest)(nil),         // 53: proto.DeletePipelineRequest
+	(*PipelineInfo)(nil),                  // 54: proto.PipelineInfo
+	(*ListPipelinesResponse)(nil),         // 55: proto.ListPipelinesResponse
+	(*RunPipelineRequest)(nil),            // 56: proto.RunPipelineRequest
+	(*PipelineLabel)(nil),                 // 57: proto.PipelineLabel
+	(*PipelineDetection)(nil),             // 58: proto.PipelineDetection
+	(*RunPipelineResponse)(nil),           // 59: proto.RunPipelineResponse
+	(*Preprocess)(nil),                    // 60: proto.Preprocess
+	(*ModelManifest)(nil),                 // 61: proto.ModelManifest
+	(*InspectModelRequest)(nil),           // 62: proto.InspectModelRequest
+	(*TensorInfo)(nil),                    // 63: proto.TensorInfo
+	(*OpsetInfo)(nil),                     // 64: proto.OpsetInfo
+	(*NcnnLayer)(nil),                     // 65: proto.NcnnLayer
+	(*InspectModelResponse)(nil),          // 66: proto.InspectModelResponse
+	(*ModelInfo)(nil),                     // 67: proto.ModelInfo
+	(*ListModelsResponse)(nil),            // 68: proto.ListModelsResponse
+	(*ModelRequest)(nil),                  // 69: proto.ModelRequest
+	(*DeleteModelResponse)(nil),           // 70: proto.DeleteModelResponse
+	(*FileInfo)(nil),                      // 71: proto.FileInfo
+	(*UploadFileRequest)(nil),             // 72: proto.UploadFileRequest
+	(*UploadFileResponse)(nil),            // 73: proto.UploadFileResponse
+	(*DownloadModelRequest)(nil),          // 74: proto.DownloadModelRequest
+	(*DownloadTrailer)(nil),               // 75: proto.DownloadTrailer
+	(*DownloadModelResponse)(nil),         // 76: proto.DownloadModelResponse
+	(*UploadOffsetResponse)(nil),          // 77: proto.UploadOffsetResponse
+	nil,                                   // 78: proto.ZoneCount.ClassesEntry
+	nil,                                   // 79: proto.EnsembleMember.ClassMapEntry
+	nil,                                   // 80: proto.InspectModelResponse.MetadataEntry
+	(*emptypb.Empty)(nil),                 // 81: google.protobuf.Empty
 }
 var file_Api_proto_depIdxs = []int32{
-	1,  // 0: proto.SingleResult.box:type_name -> proto.Position
-	1,  // 1: proto.SingleResult.center:type_name -> proto.Position
-	5,  // 2: proto.InferenceRequest.img_data:type_name -> proto.ImageData
-	2,  // 3: proto.InferenceResponse.results:type_name -> proto.SingleResult
-	0,  // 4: proto.CheckEngineResponse.engine_info:type_name -> proto.EngineInfo
-	0,  // 5: proto.CheckAllEngineResponse.engines:type_name -> proto.EngineInfo
-	13, // 6: proto.UploadFileRequest.file_info:type_name -> proto.FileInfo
-	3,  // 7: proto.DetectService.InitEngine:input_type -> proto.InitEngineRequest
-	6,  // 8: proto.DetectService.Inference:input_type -> proto.InferenceRequest
-	8,  // 9: proto.DetectService.DestroyEngine:input_type -> proto.DestroyEngineRequest
-	10, // 10: proto.DetectService.CheckEngine:input_type -> proto.CheckEngineRequest
-	16, // 11: proto.DetectService.CheckAllEngine:input_type -> google.protobuf.Empty
-	16, // 12: proto.DetectService.Shutdown:input_type -> google.protobuf.Empty
-	14, // 13: proto.DetectService.UploadModel:input_type -> proto.UploadFileRequest
-	4,  // 14: proto.DetectService.InitEngine:output_type -> proto.InitEngineResponse
-	7,  // 15: proto.DetectService.Inference:output_type -> proto.InferenceResponse
-	9,  // 16: proto.DetectService.DestroyEngine:output_type -> proto.DestroyEngineResponse
-	11, // 17: proto.DetectService.CheckEngine:output_type -> proto.CheckEngineResponse
-	12, // 18: proto.DetectService.CheckAllEngine:output_type -> proto.CheckAllEngineResponse
-	16, // 19: proto.DetectService.Shutdown:output_type -> google.protobuf.Empty
-	15, // 20: proto.DetectService.UploadModel:output_type -> proto.UploadFileResponse
-	14, // [14:21] is the sub-list for method output_type
-	7,  // [7:14] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	0,  // 0: proto.EngineInfo.state:type_name -> proto.EngineState
+	2,  // 1: proto.SingleResult.box:type_name -> proto.Position
+	2,  // 2: proto.SingleResult.center:type_name -> proto.Position
+	6,  // 3: proto.InferenceRequest.img_data:type_name -> proto.ImageData
+	8,  // 4: proto.InferenceRequest.tracker:type_name -> proto.TrackerConfig
+	12, // 5: proto.InferenceRequest.zones:type_name -> proto.ZoneConfig
+	2,  // 6: proto.ZoneRule.polygon:type_name -> proto.Position
+	2,  // 7: proto.LineRule.from:type_name -> proto.Position
+	2,  // 8: proto.LineRule.to:type_name -> proto.Position
+	10, // 9: proto.ZoneConfig.zones:type_name -> proto.ZoneRule
+	11, // 10: proto.ZoneConfig.lines:type_name -> proto.LineRule
+	78, // 11: proto.ZoneCount.classes:type_name -> proto.ZoneCount.ClassesEntry
+	12, // 12: proto.SetZonesRequest.zones:type_name -> proto.ZoneConfig
+	3,  // 13: proto.InferenceResponse.results:type_name -> proto.SingleResult
+	9,  // 14: proto.InferenceResponse.track_events:type_name -> proto.TrackEvent
+	13, // 15: proto.InferenceResponse.zone_counts:type_name -> proto.ZoneCount
+	14, // 16: proto.InferenceResponse.zone_events:type_name -> proto.ZoneEvent
+	6,  // 17: proto.StreamInferenceRequest.img_data:type_name -> proto.ImageData
+	8,  // 18: proto.StreamInferenceRequest.tracker:type_name -> proto.TrackerConfig
+	12, // 19: proto.StreamInferenceRequest.zones:type_name -> proto.ZoneConfig
+	17, // 20: proto.StreamInferenceResponse.result:type_name -> proto.InferenceResponse
+	1,  // 21: proto.CheckEngineResponse.engine_info:type_name -> proto.EngineInfo
+	1,  // 22: proto.CheckAllEngineResponse.engines:type_name -> proto.EngineInfo
+	26, // 23: proto.CheckAllEngineResponse.restore_failures:type_name -> proto.RestoreFailure
+	26, // 24: proto.CheckAllEngineResponse.preload_failures:type_name -> proto.RestoreFailure
+	29, // 25: proto.RenewLeaseResponse.leases:type_name -> proto.LeaseStatus
+	6,  // 26: proto.ReloadEngineRequest.warmup_image:type_name -> proto.ImageData
+	1,  // 27: proto.UpdateEngineResponse.engine_info:type_name -> proto.EngineInfo
+	35, // 28: proto.Alias.targets:type_name -> proto.AliasTarget
+	35, // 29: proto.SetAliasRequest.targets:type_name -> proto.AliasTarget
+	36, // 30: proto.AliasResponse.alias:type_name -> proto.Alias
+	36, // 31: proto.ListAliasesResponse.aliases:type_name -> proto.Alias
+	44, // 32: proto.ShadowStatsResponse.classes:type_name -> proto.ClassAgreement
+	79, // 33: proto.EnsembleMember.class_map:type_name -> proto.EnsembleMember.ClassMapEntry
+	46, // 34: proto.CreateEnsembleRequest.members:type_name -> proto.EnsembleMember
+	6,  // 35: proto.MultiInferenceRequest.img_data:type_name -> proto.ImageData
+	3,  // 36: proto.EngineResult.results:type_name -> proto.SingleResult
+	49, // 37: proto.MultiInferenceResponse.results:type_name -> proto.EngineResult
+	54, // 38: proto.ListPipelinesResponse.pipelines:type_name -> proto.PipelineInfo
+	6,  // 39: proto.RunPipelineRequest.img_data:type_name -> proto.ImageData
+	2,  // 40: proto.PipelineDetection.box:type_name -> proto.Position
+	2,  // 41: proto.PipelineDetection.center:type_name -> proto.Position
+	57, // 42: proto.PipelineDetection.labels:type_name -> proto.PipelineLabel
+	58, // 43: proto.PipelineDetection.children:type_name -> proto.PipelineDetection
+	58, // 44: proto.RunPipelineResponse.detections:type_name -> proto.PipelineDetection
+	60, // 45: proto.ModelManifest.preprocess:type_name -> proto.Preprocess
+	64, // 46: proto.InspectModelResponse.opsets:type_name -> proto.OpsetInfo
+	63, // 47: proto.InspectModelResponse.inputs:type_name -> proto.TensorInfo
+	63, // 48: proto.InspectModelResponse.outputs:type_name -> proto.TensorInfo
+	80, // 49: proto.InspectModelResponse.metadata:type_name -> proto.InspectModelResponse.MetadataEntry
+	65, // 50: proto.InspectModelResponse.layers:type_name -> proto.NcnnLayer
+	61, // 51: proto.ModelInfo.manifest:type_name -> proto.ModelManifest
+	67, // 52: proto.ListModelsResponse.models:type_name -> proto.ModelInfo
+	71, // 53: proto.UploadFileRequest.file_info:type_name -> proto.FileInfo
+	71, // 54: proto.DownloadModelResponse.file_info:type_name -> proto.FileInfo
+	75, // 55: proto.DownloadModelResponse.trailer:type_name -> proto.DownloadTrailer
+	4,  // 56: proto.DetectService.InitEngine:input_type -> proto.InitEngineRequest
+	7,  // 57: proto.DetectService.Inference:input_type -> proto.InferenceRequest
+	18, // 58: proto.DetectService.StreamInference:input_type -> proto.StreamInferenceRequest
+	20, // 59: proto.DetectService.DeleteTrackingSession:input_type -> proto.DeleteTrackingSessionRequest
+	15, // 60: proto.DetectService.SetZones:input_type -> proto.SetZonesRequest
+	22, // 61: proto.DetectService.DestroyEngine:input_type -> proto.DestroyEngineRequest
+	24, // 62: proto.DetectService.CheckEngine:input_type -> proto.CheckEngineRequest
+	81, // 63: proto.DetectService.CheckAllEngine:input_type -> google.protobuf.Empty
+	81, // 64: proto.DetectService.Shutdown:input_type -> google.protobuf.Empty
+	72, // 65: proto.DetectService.UploadModel:input_type -> proto.UploadFileRequest
+	69, // 66: proto.DetectService.GetUploadOffset:input_type -> proto.ModelRequest
+	74, // 67: proto.DetectService.DownloadModel:input_type -> proto.DownloadModelRequest
+	28, // 68: proto.DetectService.RenewLease:input_type -> proto.RenewLeaseRequest
+	31, // 69: proto.DetectService.ReloadEngine:input_type -> proto.ReloadEngineRequest
+	33, // 70: proto.DetectService.UpdateEngine:input_type -> proto.UpdateEngineRequest
+	37, // 71: proto.DetectService.CreateAlias:input_type -> proto.SetAliasRequest
+	37, // 72: proto.DetectService.UpdateAlias:input_type -> proto.SetAliasRequest
+	38, // 73: proto.DetectService.DeleteAlias:input_type -> proto.DeleteAliasRequest
+	81, // 74: proto.DetectService.ListAliases:input_type -> google.protobuf.Empty
+	41, // 75: proto.DetectService.SetShadow:input_type -> proto.SetShadowRequest
+	43, // 76: proto.DetectService.GetShadowStats:input_type -> proto.ShadowStatsRequest
+	47, // 77: proto.DetectService.CreateEnsemble:input_type -> proto.CreateEnsembleRequest
+	48, // 78: proto.DetectService.MultiInference:input_type -> proto.MultiInferenceRequest
+	51, // 79: proto.DetectService.RegisterPipeline:input_type -> proto.RegisterPipelineRequest
+	53, // 80: proto.DetectService.DeletePipeline:input_type -> proto.DeletePipelineRequest
+	81, // 81: proto.DetectService.ListPipelines:input_type -> google.protobuf.Empty
+	56, // 82: proto.DetectService.RunPipeline:input_type -> proto.RunPipelineRequest
+	81, // 83: proto.DetectService.ListModels:input_type -> google.protobuf.Empty
+	69, // 84: proto.DetectService.GetModelInfo:input_type -> proto.ModelRequest
+	69, // 85: proto.DetectService.DeleteModel:input_type -> proto.ModelRequest
+	62, // 86: proto.DetectService.InspectModel:input_type -> proto.InspectModelRequest
+	5,  // 87: proto.DetectService.InitEngine:output_type -> proto.InitEngineResponse
+	17, // 88: proto.DetectService.Inference:output_type -> proto.InferenceResponse
+	19, // 89: proto.DetectService.StreamInference:output_type -> proto.StreamInferenceResponse
+	21, // 90: proto.DetectService.DeleteTrackingSession:output_type -> proto.DeleteTrackingSessionResponse
+	16, // 91: proto.DetectService.SetZones:output_type -> proto.SetZonesResponse
+	23, // 92: proto.DetectService.DestroyEngine:output_type -> proto.DestroyEngineResponse
+	25, // 93: proto.DetectService.CheckEngine:output_type -> proto.CheckEngineResponse
+	27, // 94: proto.DetectService.CheckAllEngine:output_type -> proto.CheckAllEngineResponse
+	81, // 95: proto.DetectService.Shutdown:output_type -> google.protobuf.Empty
+	73, // 96: proto.DetectService.UploadModel:output_type -> proto.UploadFileResponse
+	77, // 97: proto.DetectService.GetUploadOffset:output_type -> proto.UploadOffsetResponse
+	76, // 98: proto.DetectService.DownloadModel:output_type -> proto.DownloadModelResponse
+	30, // 99: proto.DetectService.RenewLease:output_type -> proto.RenewLeaseResponse
+	32, // 100: proto.DetectService.ReloadEngine:output_type -> proto.ReloadEngineResponse
+	34, // 101: proto.DetectService.UpdateEngine:output_type -> proto.UpdateEngineResponse
+	39, // 102: proto.DetectService.CreateAlias:output_type -> proto.AliasResponse
+	39, // 103: proto.DetectService.UpdateAlias:output_type -> proto.AliasResponse
+	39, // 104: proto.DetectService.DeleteAlias:output_type -> proto.AliasResponse
+	40, // 105: proto.DetectService.ListAliases:output_type -> proto.ListAliasesResponse
+	42, // 106: proto.DetectService.SetShadow:output_type -> proto.SetShadowResponse
+	45, // 107: proto.DetectService.GetShadowStats:output_type -> proto.ShadowStatsResponse
+	5,  // 108: proto.DetectService.CreateEnsemble:output_type -> proto.InitEngineResponse
+	50, // 109: proto.DetectService.MultiInference:output_type -> proto.MultiInferenceResponse
+	52, // 110: proto.DetectService.RegisterPipeline:output_type -> proto.PipelineResponse
+	52, // 111: proto.DetectService.DeletePipeline:output_type -> proto.PipelineResponse
+	55, // 112: proto.DetectService.ListPipelines:output_type -> proto.ListPipelinesResponse
+	59, // 113: proto.DetectService.RunPipeline:output_type -> proto.RunPipelineResponse
+	68, // 114: proto.DetectService.ListModels:output_type -> proto.ListModelsResponse
+	67, // 115: proto.DetectService.GetModelInfo:output_type -> proto.ModelInfo
+	70, // 116: proto.DetectService.DeleteModel:output_type -> proto.DeleteModelResponse
+	66, // 117: proto.DetectService.InspectModel:output_type -> proto.InspectModelResponse
+	87, // [87:118] is the sub-list for method output_type
+	56, // [56:87] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
 }
 
 func init() { file_Api_proto_init() }
@@ -1200,22 +6299,29 @@ func file_Api_proto_init() {
 	if File_Api_proto != nil {
 		return
 	}
-	file_Api_proto_msgTypes[14].OneofWrappers = []any{
+	file_Api_proto_msgTypes[32].OneofWrappers = []any{}
+	file_Api_proto_msgTypes[71].OneofWrappers = []any{
 		(*UploadFileRequest_FileInfo)(nil),
 		(*UploadFileRequest_ChunkData)(nil),
 	}
+	file_Api_proto_msgTypes[75].OneofWrappers = []any{
+		(*DownloadModelResponse_FileInfo)(nil),
+		(*DownloadModelResponse_ChunkData)(nil),
+		(*DownloadModelResponse_Trailer)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_Api_proto_rawDesc), len(file_Api_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   16,
+			NumEnums:      1,
+			NumMessages:   80,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_Api_proto_goTypes,
 		DependencyIndexes: file_Api_proto_depIdxs,
+		EnumInfos:         file_Api_proto_enumTypes,
 		MessageInfos:      file_Api_proto_msgTypes,
 	}.Build()
 	File_Api_proto = out.File
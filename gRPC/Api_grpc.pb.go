@@ -20,13 +20,37 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DetectService_InitEngine_FullMethodName     = "/proto.DetectService/InitEngine"
-	DetectService_Inference_FullMethodName      = "/proto.DetectService/Inference"
-	DetectService_DestroyEngine_FullMethodName  = "/proto.DetectService/DestroyEngine"
-	DetectService_CheckEngine_FullMethodName    = "/proto.DetectService/CheckEngine"
-	DetectService_CheckAllEngine_FullMethodName = "/proto.DetectService/CheckAllEngine"
-	DetectService_Shutdown_FullMethodName       = "/proto.DetectService/Shutdown"
-	DetectService_UploadModel_FullMethodName    = "/proto.DetectService/UploadModel"
+	DetectService_InitEngine_FullMethodName            = "/proto.DetectService/InitEngine"
+	DetectService_Inference_FullMethodName             = "/proto.DetectService/Inference"
+	DetectService_StreamInference_FullMethodName       = "/proto.DetectService/StreamInference"
+	DetectService_DeleteTrackingSession_FullMethodName = "/proto.DetectService/DeleteTrackingSession"
+	DetectService_SetZones_FullMethodName              = "/proto.DetectService/SetZones"
+	DetectService_DestroyEngine_FullMethodName         = "/proto.DetectService/DestroyEngine"
+	DetectService_CheckEngine_FullMethodName           = "/proto.DetectService/CheckEngine"
+	DetectService_CheckAllEngine_FullMethodName        = "/proto.DetectService/CheckAllEngine"
+	DetectService_Shutdown_FullMethodName              = "/proto.DetectService/Shutdown"
+	DetectService_UploadModel_FullMethodName           = "/proto.DetectService/UploadModel"
+	DetectService_GetUploadOffset_FullMethodName       = "/proto.DetectService/GetUploadOffset"
+	DetectService_DownloadModel_FullMethodName         = "/proto.DetectService/DownloadModel"
+	DetectService_RenewLease_FullMethodName            = "/proto.DetectService/RenewLease"
+	DetectService_ReloadEngine_FullMethodName          = "/proto.DetectService/ReloadEngine"
+	DetectService_UpdateEngine_FullMethodName          = "/proto.DetectService/UpdateEngine"
+	DetectService_CreateAlias_FullMethodName           = "/proto.DetectService/CreateAlias"
+	DetectService_UpdateAlias_FullMethodName           = "/proto.DetectService/UpdateAlias"
+	DetectService_DeleteAlias_FullMethodName           = "/proto.DetectService/DeleteAlias"
+	DetectService_ListAliases_FullMethodName           = "/proto.DetectService/ListAliases"
+	DetectService_SetShadow_FullMethodName             = "/proto.DetectService/SetShadow"
+	DetectService_GetShadowStats_FullMethodName        = "/proto.DetectService/GetShadowStats"
+	DetectService_CreateEnsemble_FullMethodName        = "/proto.DetectService/CreateEnsemble"
+	DetectService_MultiInference_FullMethodName        = "/proto.DetectService/MultiInference"
+	DetectService_RegisterPipeline_FullMethodName      = "/proto.DetectService/RegisterPipeline"
+	DetectService_DeletePipeline_FullMethodName        = "/proto.DetectService/DeletePipeline"
+	DetectService_ListPipelines_FullMethodName         = "/proto.DetectService/ListPipelines"
+	DetectService_RunPipeline_FullMethodName           = "/proto.DetectService/RunPipeline"
+	DetectService_ListModels_FullMethodName            = "/proto.DetectService/ListModels"
+	DetectService_GetModelInfo_FullMethodName          = "/proto.DetectService/GetModelInfo"
+	DetectService_DeleteModel_FullMethodName           = "/proto.DetectService/DeleteModel"
+	DetectService_InspectModel_FullMethodName          = "/proto.DetectService/InspectModel"
 )
 
 // DetectServiceClient is the client API for DetectService service.
@@ -35,11 +59,35 @@ const (
 type DetectServiceClient interface {
 	InitEngine(ctx context.Context, in *InitEngineRequest, opts ...grpc.CallOption) (*InitEngineResponse, error)
 	Inference(ctx context.Context, in *InferenceRequest, opts ...grpc.CallOption) (*InferenceResponse, error)
+	StreamInference(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamInferenceRequest, StreamInferenceResponse], error)
+	DeleteTrackingSession(ctx context.Context, in *DeleteTrackingSessionRequest, opts ...grpc.CallOption) (*DeleteTrackingSessionResponse, error)
+	SetZones(ctx context.Context, in *SetZonesRequest, opts ...grpc.CallOption) (*SetZonesResponse, error)
 	DestroyEngine(ctx context.Context, in *DestroyEngineRequest, opts ...grpc.CallOption) (*DestroyEngineResponse, error)
 	CheckEngine(ctx context.Context, in *CheckEngineRequest, opts ...grpc.CallOption) (*CheckEngineResponse, error)
 	CheckAllEngine(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CheckAllEngineResponse, error)
 	Shutdown(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UploadModel(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileRequest, UploadFileResponse], error)
+	GetUploadOffset(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*UploadOffsetResponse, error)
+	DownloadModel(ctx context.Context, in *DownloadModelRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadModelResponse], error)
+	RenewLease(ctx context.Context, in *RenewLeaseRequest, opts ...grpc.CallOption) (*RenewLeaseResponse, error)
+	ReloadEngine(ctx context.Context, in *ReloadEngineRequest, opts ...grpc.CallOption) (*ReloadEngineResponse, error)
+	UpdateEngine(ctx context.Context, in *UpdateEngineRequest, opts ...grpc.CallOption) (*UpdateEngineResponse, error)
+	CreateAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*AliasResponse, error)
+	UpdateAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*AliasResponse, error)
+	DeleteAlias(ctx context.Context, in *DeleteAliasRequest, opts ...grpc.CallOption) (*AliasResponse, error)
+	ListAliases(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListAliasesResponse, error)
+	SetShadow(ctx context.Context, in *SetShadowRequest, opts ...grpc.CallOption) (*SetShadowResponse, error)
+	GetShadowStats(ctx context.Context, in *ShadowStatsRequest, opts ...grpc.CallOption) (*ShadowStatsResponse, error)
+	CreateEnsemble(ctx context.Context, in *CreateEnsembleRequest, opts ...grpc.CallOption) (*InitEngineResponse, error)
+	MultiInference(ctx context.Context, in *MultiInferenceRequest, opts ...grpc.CallOption) (*MultiInferenceResponse, error)
+	RegisterPipeline(ctx context.Context, in *RegisterPipelineRequest, opts ...grpc.CallOption) (*PipelineResponse, error)
+	DeletePipeline(ctx context.Context, in *DeletePipelineRequest, opts ...grpc.CallOption) (*PipelineResponse, error)
+	ListPipelines(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListPipelinesResponse, error)
+	RunPipeline(ctx context.Context, in *RunPipelineRequest, opts ...grpc.CallOption) (*RunPipelineResponse, error)
+	ListModels(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListModelsResponse, error)
+	GetModelInfo(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*ModelInfo, error)
+	DeleteModel(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*DeleteModelResponse, error)
+	InspectModel(ctx context.Context, in *InspectModelRequest, opts ...grpc.CallOption) (*InspectModelResponse, error)
 }
 
 type detectServiceClient struct {
@@ -70,6 +118,39 @@ func (c *detectServiceClient) Inference(ctx context.Context, in *InferenceReques
 	return out, nil
 }
 
+func (c *detectServiceClient) StreamInference(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamInferenceRequest, StreamInferenceResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DetectService_ServiceDesc.Streams[0], DetectService_StreamInference_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamInferenceRequest, StreamInferenceResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DetectService_StreamInferenceClient = grpc.BidiStreamingClient[StreamInferenceRequest, StreamInferenceResponse]
+
+func (c *detectServiceClient) DeleteTrackingSession(ctx context.Context, in *DeleteTrackingSessionRequest, opts ...grpc.CallOption) (*DeleteTrackingSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTrackingSessionResponse)
+	err := c.cc.Invoke(ctx, DetectService_DeleteTrackingSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) SetZones(ctx context.Context, in *SetZonesRequest, opts ...grpc.CallOption) (*SetZonesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetZonesResponse)
+	err := c.cc.Invoke(ctx, DetectService_SetZones_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *detectServiceClient) DestroyEngine(ctx context.Context, in *DestroyEngineRequest, opts ...grpc.CallOption) (*DestroyEngineResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DestroyEngineResponse)
@@ -112,7 +193,7 @@ func (c *detectServiceClient) Shutdown(ctx context.Context, in *emptypb.Empty, o
 
 func (c *detectServiceClient) UploadModel(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileRequest, UploadFileResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &DetectService_ServiceDesc.Streams[0], DetectService_UploadModel_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &DetectService_ServiceDesc.Streams[1], DetectService_UploadModel_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -123,17 +204,260 @@ func (c *detectServiceClient) UploadModel(ctx context.Context, opts ...grpc.Call
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DetectService_UploadModelClient = grpc.ClientStreamingClient[UploadFileRequest, UploadFileResponse]
 
+func (c *detectServiceClient) GetUploadOffset(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*UploadOffsetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadOffsetResponse)
+	err := c.cc.Invoke(ctx, DetectService_GetUploadOffset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) DownloadModel(ctx context.Context, in *DownloadModelRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadModelResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DetectService_ServiceDesc.Streams[2], DetectService_DownloadModel_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadModelRequest, DownloadModelResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DetectService_DownloadModelClient = grpc.ServerStreamingClient[DownloadModelResponse]
+
+func (c *detectServiceClient) RenewLease(ctx context.Context, in *RenewLeaseRequest, opts ...grpc.CallOption) (*RenewLeaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenewLeaseResponse)
+	err := c.cc.Invoke(ctx, DetectService_RenewLease_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) ReloadEngine(ctx context.Context, in *ReloadEngineRequest, opts ...grpc.CallOption) (*ReloadEngineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReloadEngineResponse)
+	err := c.cc.Invoke(ctx, DetectService_ReloadEngine_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) UpdateEngine(ctx context.Context, in *UpdateEngineRequest, opts ...grpc.CallOption) (*UpdateEngineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateEngineResponse)
+	err := c.cc.Invoke(ctx, DetectService_UpdateEngine_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) CreateAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*AliasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AliasResponse)
+	err := c.cc.Invoke(ctx, DetectService_CreateAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) UpdateAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*AliasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AliasResponse)
+	err := c.cc.Invoke(ctx, DetectService_UpdateAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) DeleteAlias(ctx context.Context, in *DeleteAliasRequest, opts ...grpc.CallOption) (*AliasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AliasResponse)
+	err := c.cc.Invoke(ctx, DetectService_DeleteAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) ListAliases(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListAliasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAliasesResponse)
+	err := c.cc.Invoke(ctx, DetectService_ListAliases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) SetShadow(ctx context.Context, in *SetShadowRequest, opts ...grpc.CallOption) (*SetShadowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetShadowResponse)
+	err := c.cc.Invoke(ctx, DetectService_SetShadow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) GetShadowStats(ctx context.Context, in *ShadowStatsRequest, opts ...grpc.CallOption) (*ShadowStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShadowStatsResponse)
+	err := c.cc.Invoke(ctx, DetectService_GetShadowStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) CreateEnsemble(ctx context.Context, in *CreateEnsembleRequest, opts ...grpc.CallOption) (*InitEngineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InitEngineResponse)
+	err := c.cc.Invoke(ctx, DetectService_CreateEnsemble_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) MultiInference(ctx context.Context, in *MultiInferenceRequest, opts ...grpc.CallOption) (*MultiInferenceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MultiInferenceResponse)
+	err := c.cc.Invoke(ctx, DetectService_MultiInference_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) RegisterPipeline(ctx context.Context, in *RegisterPipelineRequest, opts ...grpc.CallOption) (*PipelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PipelineResponse)
+	err := c.cc.Invoke(ctx, DetectService_RegisterPipeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) DeletePipeline(ctx context.Context, in *DeletePipelineRequest, opts ...grpc.CallOption) (*PipelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PipelineResponse)
+	err := c.cc.Invoke(ctx, DetectService_DeletePipeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) ListPipelines(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListPipelinesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPipelinesResponse)
+	err := c.cc.Invoke(ctx, DetectService_ListPipelines_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) RunPipeline(ctx context.Context, in *RunPipelineRequest, opts ...grpc.CallOption) (*RunPipelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunPipelineResponse)
+	err := c.cc.Invoke(ctx, DetectService_RunPipeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) ListModels(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListModelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModelsResponse)
+	err := c.cc.Invoke(ctx, DetectService_ListModels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) GetModelInfo(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*ModelInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ModelInfo)
+	err := c.cc.Invoke(ctx, DetectService_GetModelInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) DeleteModel(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*DeleteModelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteModelResponse)
+	err := c.cc.Invoke(ctx, DetectService_DeleteModel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectServiceClient) InspectModel(ctx context.Context, in *InspectModelRequest, opts ...grpc.CallOption) (*InspectModelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InspectModelResponse)
+	err := c.cc.Invoke(ctx, DetectService_InspectModel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DetectServiceServer is the server API for DetectService service.
 // All implementations must embed UnimplementedDetectServiceServer
 // for forward compatibility.
 type DetectServiceServer interface {
 	InitEngine(context.Context, *InitEngineRequest) (*InitEngineResponse, error)
 	Inference(context.Context, *InferenceRequest) (*InferenceResponse, error)
+	StreamInference(grpc.BidiStreamingServer[StreamInferenceRequest, StreamInferenceResponse]) error
+	DeleteTrackingSession(context.Context, *DeleteTrackingSessionRequest) (*DeleteTrackingSessionResponse, error)
+	SetZones(context.Context, *SetZonesRequest) (*SetZonesResponse, error)
 	DestroyEngine(context.Context, *DestroyEngineRequest) (*DestroyEngineResponse, error)
 	CheckEngine(context.Context, *CheckEngineRequest) (*CheckEngineResponse, error)
 	CheckAllEngine(context.Context, *emptypb.Empty) (*CheckAllEngineResponse, error)
 	Shutdown(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	UploadModel(grpc.ClientStreamingServer[UploadFileRequest, UploadFileResponse]) error
+	GetUploadOffset(context.Context, *ModelRequest) (*UploadOffsetResponse, error)
+	DownloadModel(*DownloadModelRequest, grpc.ServerStreamingServer[DownloadModelResponse]) error
+	RenewLease(context.Context, *RenewLeaseRequest) (*RenewLeaseResponse, error)
+	ReloadEngine(context.Context, *ReloadEngineRequest) (*ReloadEngineResponse, error)
+	UpdateEngine(context.Context, *UpdateEngineRequest) (*UpdateEngineResponse, error)
+	CreateAlias(context.Context, *SetAliasRequest) (*AliasResponse, error)
+	UpdateAlias(context.Context, *SetAliasRequest) (*AliasResponse, error)
+	DeleteAlias(context.Context, *DeleteAliasRequest) (*AliasResponse, error)
+	ListAliases(context.Context, *emptypb.Empty) (*ListAliasesResponse, error)
+	SetShadow(context.Context, *SetShadowRequest) (*SetShadowResponse, error)
+	GetShadowStats(context.Context, *ShadowStatsRequest) (*ShadowStatsResponse, error)
+	CreateEnsemble(context.Context, *CreateEnsembleRequest) (*InitEngineResponse, error)
+	MultiInference(context.Context, *MultiInferenceRequest) (*MultiInferenceResponse, error)
+	RegisterPipeline(context.Context, *RegisterPipelineRequest) (*PipelineResponse, error)
+	DeletePipeline(context.Context, *DeletePipelineRequest) (*PipelineResponse, error)
+	ListPipelines(context.Context, *emptypb.Empty) (*ListPipelinesResponse, error)
+	RunPipeline(context.Context, *RunPipelineRequest) (*RunPipelineResponse, error)
+	ListModels(context.Context, *emptypb.Empty) (*ListModelsResponse, error)
+	GetModelInfo(context.Context, *ModelRequest) (*ModelInfo, error)
+	DeleteModel(context.Context, *ModelRequest) (*DeleteModelResponse, error)
+	InspectModel(context.Context, *InspectModelRequest) (*InspectModelResponse, error)
 	mustEmbedUnimplementedDetectServiceServer()
 }
 
@@ -150,6 +474,15 @@ func (UnimplementedDetectServiceServer) InitEngine(context.Context, *InitEngineR
 func (UnimplementedDetectServiceServer) Inference(context.Context, *InferenceRequest) (*InferenceResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Inference not implemented")
 }
+func (UnimplementedDetectServiceServer) StreamInference(grpc.BidiStreamingServer[StreamInferenceRequest, StreamInferenceResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamInference not implemented")
+}
+func (UnimplementedDetectServiceServer) DeleteTrackingSession(context.Context, *DeleteTrackingSessionRequest) (*DeleteTrackingSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteTrackingSession not implemented")
+}
+func (UnimplementedDetectServiceServer) SetZones(context.Context, *SetZonesRequest) (*SetZonesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetZones not implemented")
+}
 func (UnimplementedDetectServiceServer) DestroyEngine(context.Context, *DestroyEngineRequest) (*DestroyEngineResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DestroyEngine not implemented")
 }
@@ -165,6 +498,69 @@ func (UnimplementedDetectServiceServer) Shutdown(context.Context, *emptypb.Empty
 func (UnimplementedDetectServiceServer) UploadModel(grpc.ClientStreamingServer[UploadFileRequest, UploadFileResponse]) error {
 	return status.Error(codes.Unimplemented, "method UploadModel not implemented")
 }
+func (UnimplementedDetectServiceServer) GetUploadOffset(context.Context, *ModelRequest) (*UploadOffsetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUploadOffset not implemented")
+}
+func (UnimplementedDetectServiceServer) DownloadModel(*DownloadModelRequest, grpc.ServerStreamingServer[DownloadModelResponse]) error {
+	return status.Error(codes.Unimplemented, "method DownloadModel not implemented")
+}
+func (UnimplementedDetectServiceServer) RenewLease(context.Context, *RenewLeaseRequest) (*RenewLeaseResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenewLease not implemented")
+}
+func (UnimplementedDetectServiceServer) ReloadEngine(context.Context, *ReloadEngineRequest) (*ReloadEngineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReloadEngine not implemented")
+}
+func (UnimplementedDetectServiceServer) UpdateEngine(context.Context, *UpdateEngineRequest) (*UpdateEngineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateEngine not implemented")
+}
+func (UnimplementedDetectServiceServer) CreateAlias(context.Context, *SetAliasRequest) (*AliasResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateAlias not implemented")
+}
+func (UnimplementedDetectServiceServer) UpdateAlias(context.Context, *SetAliasRequest) (*AliasResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateAlias not implemented")
+}
+func (UnimplementedDetectServiceServer) DeleteAlias(context.Context, *DeleteAliasRequest) (*AliasResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteAlias not implemented")
+}
+func (UnimplementedDetectServiceServer) ListAliases(context.Context, *emptypb.Empty) (*ListAliasesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAliases not implemented")
+}
+func (UnimplementedDetectServiceServer) SetShadow(context.Context, *SetShadowRequest) (*SetShadowResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetShadow not implemented")
+}
+func (UnimplementedDetectServiceServer) GetShadowStats(context.Context, *ShadowStatsRequest) (*ShadowStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetShadowStats not implemented")
+}
+func (UnimplementedDetectServiceServer) CreateEnsemble(context.Context, *CreateEnsembleRequest) (*InitEngineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateEnsemble not implemented")
+}
+func (UnimplementedDetectServiceServer) MultiInference(context.Context, *MultiInferenceRequest) (*MultiInferenceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MultiInference not implemented")
+}
+func (UnimplementedDetectServiceServer) RegisterPipeline(context.Context, *RegisterPipelineRequest) (*PipelineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterPipeline not implemented")
+}
+func (UnimplementedDetectServiceServer) DeletePipeline(context.Context, *DeletePipelineRequest) (*PipelineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeletePipeline not implemented")
+}
+func (UnimplementedDetectServiceServer) ListPipelines(context.Context, *emptypb.Empty) (*ListPipelinesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPipelines not implemented")
+}
+func (UnimplementedDetectServiceServer) RunPipeline(context.Context, *RunPipelineRequest) (*RunPipelineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RunPipeline not implemented")
+}
+func (UnimplementedDetectServiceServer) ListModels(context.Context, *emptypb.Empty) (*ListModelsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListModels not implemented")
+}
+func (UnimplementedDetectServiceServer) GetModelInfo(context.Context, *ModelRequest) (*ModelInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetModelInfo not implemented")
+}
+func (UnimplementedDetectServiceServer) DeleteModel(context.Context, *ModelRequest) (*DeleteModelResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteModel not implemented")
+}
+func (UnimplementedDetectServiceServer) InspectModel(context.Context, *InspectModelRequest) (*InspectModelResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method InspectModel not implemented")
+}
 func (UnimplementedDetectServiceServer) mustEmbedUnimplementedDetectServiceServer() {}
 func (UnimplementedDetectServiceServer) testEmbeddedByValue()                       {}
 
@@ -222,6 +618,49 @@ func _DetectService_Inference_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DetectService_StreamInference_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DetectServiceServer).StreamInference(&grpc.GenericServerStream[StreamInferenceRequest, StreamInferenceResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DetectService_StreamInferenceServer = grpc.BidiStreamingServer[StreamInferenceRequest, StreamInferenceResponse]
+
+func _DetectService_DeleteTrackingSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTrackingSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).DeleteTrackingSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_DeleteTrackingSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).DeleteTrackingSession(ctx, req.(*DeleteTrackingSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_SetZones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetZonesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).SetZones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_SetZones_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).SetZones(ctx, req.(*SetZonesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DetectService_DestroyEngine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DestroyEngineRequest)
 	if err := dec(in); err != nil {
@@ -301,44 +740,514 @@ func _DetectService_UploadModel_Handler(srv interface{}, stream grpc.ServerStrea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DetectService_UploadModelServer = grpc.ClientStreamingServer[UploadFileRequest, UploadFileResponse]
 
-// DetectService_ServiceDesc is the grpc.ServiceDesc for DetectService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var DetectService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.DetectService",
-	HandlerType: (*DetectServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "InitEngine",
-			Handler:    _DetectService_InitEngine_Handler,
-		},
-		{
-			MethodName: "Inference",
-			Handler:    _DetectService_Inference_Handler,
-		},
-		{
-			MethodName: "DestroyEngine",
-			Handler:    _DetectService_DestroyEngine_Handler,
-		},
-		{
-			MethodName: "CheckEngine",
-			Handler:    _DetectService_CheckEngine_Handler,
-		},
-		{
-			MethodName: "CheckAllEngine",
+func _DetectService_GetUploadOffset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).GetUploadOffset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_GetUploadOffset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).GetUploadOffset(ctx, req.(*ModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_DownloadModel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadModelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DetectServiceServer).DownloadModel(m, &grpc.GenericServerStream[DownloadModelRequest, DownloadModelResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DetectService_DownloadModelServer = grpc.ServerStreamingServer[DownloadModelResponse]
+
+func _DetectService_RenewLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).RenewLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_RenewLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).RenewLease(ctx, req.(*RenewLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_ReloadEngine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadEngineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).ReloadEngine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_ReloadEngine_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).ReloadEngine(ctx, req.(*ReloadEngineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_UpdateEngine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEngineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).UpdateEngine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_UpdateEngine_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).UpdateEngine(ctx, req.(*UpdateEngineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_CreateAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).CreateAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_CreateAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).CreateAlias(ctx, req.(*SetAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_UpdateAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).UpdateAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_UpdateAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).UpdateAlias(ctx, req.(*SetAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_DeleteAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).DeleteAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_DeleteAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).DeleteAlias(ctx, req.(*DeleteAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).ListAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_ListAliases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).ListAliases(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_SetShadow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetShadowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).SetShadow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_SetShadow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).SetShadow(ctx, req.(*SetShadowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_GetShadowStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShadowStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).GetShadowStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_GetShadowStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).GetShadowStats(ctx, req.(*ShadowStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_CreateEnsemble_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEnsembleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).CreateEnsemble(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_CreateEnsemble_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).CreateEnsemble(ctx, req.(*CreateEnsembleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_MultiInference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiInferenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).MultiInference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_MultiInference_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).MultiInference(ctx, req.(*MultiInferenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_RegisterPipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterPipelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).RegisterPipeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_RegisterPipeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).RegisterPipeline(ctx, req.(*RegisterPipelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_DeletePipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePipelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).DeletePipeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_DeletePipeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).DeletePipeline(ctx, req.(*DeletePipelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_ListPipelines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).ListPipelines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_ListPipelines_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).ListPipelines(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_RunPipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunPipelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).RunPipeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_RunPipeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).RunPipeline(ctx, req.(*RunPipelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).ListModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_ListModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).ListModels(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_GetModelInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).GetModelInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_GetModelInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).GetModelInfo(ctx, req.(*ModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_DeleteModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).DeleteModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_DeleteModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).DeleteModel(ctx, req.(*ModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectService_InspectModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectServiceServer).InspectModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectService_InspectModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectServiceServer).InspectModel(ctx, req.(*InspectModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DetectService_ServiceDesc is the grpc.ServiceDesc for DetectService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DetectService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.DetectService",
+	HandlerType: (*DetectServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InitEngine",
+			Handler:    _DetectService_InitEngine_Handler,
+		},
+		{
+			MethodName: "Inference",
+			Handler:    _DetectService_Inference_Handler,
+		},
+		{
+			MethodName: "DeleteTrackingSession",
+			Handler:    _DetectService_DeleteTrackingSession_Handler,
+		},
+		{
+			MethodName: "SetZones",
+			Handler:    _DetectService_SetZones_Handler,
+		},
+		{
+			MethodName: "DestroyEngine",
+			Handler:    _DetectService_DestroyEngine_Handler,
+		},
+		{
+			MethodName: "CheckEngine",
+			Handler:    _DetectService_CheckEngine_Handler,
+		},
+		{
+			MethodName: "CheckAllEngine",
 			Handler:    _DetectService_CheckAllEngine_Handler,
 		},
 		{
 			MethodName: "Shutdown",
 			Handler:    _DetectService_Shutdown_Handler,
 		},
+		{
+			MethodName: "GetUploadOffset",
+			Handler:    _DetectService_GetUploadOffset_Handler,
+		},
+		{
+			MethodName: "RenewLease",
+			Handler:    _DetectService_RenewLease_Handler,
+		},
+		{
+			MethodName: "ReloadEngine",
+			Handler:    _DetectService_ReloadEngine_Handler,
+		},
+		{
+			MethodName: "UpdateEngine",
+			Handler:    _DetectService_UpdateEngine_Handler,
+		},
+		{
+			MethodName: "CreateAlias",
+			Handler:    _DetectService_CreateAlias_Handler,
+		},
+		{
+			MethodName: "UpdateAlias",
+			Handler:    _DetectService_UpdateAlias_Handler,
+		},
+		{
+			MethodName: "DeleteAlias",
+			Handler:    _DetectService_DeleteAlias_Handler,
+		},
+		{
+			MethodName: "ListAliases",
+			Handler:    _DetectService_ListAliases_Handler,
+		},
+		{
+			MethodName: "SetShadow",
+			Handler:    _DetectService_SetShadow_Handler,
+		},
+		{
+			MethodName: "GetShadowStats",
+			Handler:    _DetectService_GetShadowStats_Handler,
+		},
+		{
+			MethodName: "CreateEnsemble",
+			Handler:    _DetectService_CreateEnsemble_Handler,
+		},
+		{
+			MethodName: "MultiInference",
+			Handler:    _DetectService_MultiInference_Handler,
+		},
+		{
+			MethodName: "RegisterPipeline",
+			Handler:    _DetectService_RegisterPipeline_Handler,
+		},
+		{
+			MethodName: "DeletePipeline",
+			Handler:    _DetectService_DeletePipeline_Handler,
+		},
+		{
+			MethodName: "ListPipelines",
+			Handler:    _DetectService_ListPipelines_Handler,
+		},
+		{
+			MethodName: "RunPipeline",
+			Handler:    _DetectService_RunPipeline_Handler,
+		},
+		{
+			MethodName: "ListModels",
+			Handler:    _DetectService_ListModels_Handler,
+		},
+		{
+			MethodName: "GetModelInfo",
+			Handler:    _DetectService_GetModelInfo_Handler,
+		},
+		{
+			MethodName: "DeleteModel",
+			Handler:    _DetectService_DeleteModel_Handler,
+		},
+		{
+			MethodName: "InspectModel",
+			Handler:    _DetectService_InspectModel_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamInference",
+			Handler:       _DetectService_StreamInference_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "UploadModel",
 			Handler:       _DetectService_UploadModel_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "DownloadModel",
+			Handler:       _DetectService_DownloadModel_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "Api.proto",
 }
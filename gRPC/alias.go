@@ -0,0 +1,180 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	// aliases 把稳定的名称映射到一个或多个带权重的引擎版本
+	aliases = make(map[string]*Alias)
+	aliasMu sync.RWMutex
+)
+
+// validateAlias 检查别名定义：名称不能与引擎 ID 冲突，目标引擎必须存在且总权重大于 0。调用方需持有 mapMu
+func validateAlias(req *SetAliasRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("alias name cannot be empty")
+	}
+	if len(req.Targets) == 0 {
+		return fmt.Errorf("alias %s must have at least one target", req.Name)
+	}
+	if _, exists := DSequences[req.Name]; exists {
+		return fmt.Errorf("alias name %s conflicts with an engine ID", req.Name)
+	}
+	var total uint32
+	seen := make(map[string]bool, len(req.Targets))
+	for _, t := range req.Targets {
+		if _, exists := DSequences[t.EngineId]; !exists {
+			return fmt.Errorf("alias target %s: %w", t.EngineId, engineNotFound(t.EngineId))
+		}
+		if seen[t.EngineId] {
+			return fmt.Errorf("alias target %s is listed more than once", t.EngineId)
+		}
+		seen[t.EngineId] = true
+		total += t.Weight
+	}
+	if total == 0 {
+		return fmt.Errorf("alias %s must have a target with positive weight", req.Name)
+	}
+	return nil
+}
+
+// aliasExists 判断名称是否已被别名占用，引擎以固定 ID 注册时用它检查冲突
+func aliasExists(name string) bool {
+	aliasMu.RLock()
+	defer aliasMu.RUnlock()
+	_, exists := aliases[name]
+	return exists
+}
+
+// resolveEngine 把 UUID 或别名解析为引擎 ID；通过别名解析时按权重在存活的目标中选择一个，并返回该目标
+func resolveEngine(id string) (string, *AliasTarget, error) {
+	mapMu.RLock()
+	defer mapMu.RUnlock()
+	if _, exists := DSequences[id]; exists {
+		return id, nil, nil
+	}
+	aliasMu.RLock()
+	alias, ok := aliases[id]
+	aliasMu.RUnlock()
+	if !ok {
+		return id, nil, nil
+	}
+	live := make([]*AliasTarget, 0, len(alias.Targets))
+	var total uint32
+	for _, t := range alias.Targets {
+		if _, exists := DSequences[t.EngineId]; exists && t.Weight > 0 {
+			live = append(live, t)
+			total += t.Weight
+		}
+	}
+	if total == 0 {
+		return "", nil, status.Errorf(codes.NotFound, "alias %s has no live engines", id)
+	}
+	n := rand.Uint32N(total)
+	for _, t := range live {
+		if n < t.Weight {
+			return t.EngineId, t, nil
+		}
+		n -= t.Weight
+	}
+	return live[len(live)-1].EngineId, live[len(live)-1], nil
+}
+
+func aliasList() []*Alias {
+	aliasMu.RLock()
+	defer aliasMu.RUnlock()
+	out := make([]*Alias, 0, len(aliases))
+	for _, a := range aliases {
+		out = append(out, protobuf.Clone(a).(*Alias))
+	}
+	slices.SortFunc(out, func(a, b *Alias) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// setAlias 创建或更新别名，create 为 true 时别名必须不存在，否则必须已存在。
+// 校验到写入期间持有 mapMu，避免同时以相同 ID 注册引擎
+func setAlias(req *SetAliasRequest, create bool) (*AliasResponse, error) {
+	alias := &Alias{
+		Name:          req.Name,
+		Targets:       req.Targets,
+		UpdatedUnixMs: time.Now().UnixMilli(),
+	}
+	mapMu.RLock()
+	if err := validateAlias(req); err != nil {
+		mapMu.RUnlock()
+		return nil, err
+	}
+	aliasMu.Lock()
+	_, exists := aliases[req.Name]
+	if create && exists {
+		aliasMu.Unlock()
+		mapMu.RUnlock()
+		return nil, status.Errorf(codes.AlreadyExists, "alias %s already exists", req.Name)
+	}
+	if !create && !exists {
+		aliasMu.Unlock()
+		mapMu.RUnlock()
+		return nil, status.Errorf(codes.NotFound, "alias %s not found", req.Name)
+	}
+	aliases[req.Name] = alias
+	aliasMu.Unlock()
+	mapMu.RUnlock()
+	saveRegistry()
+	logger.Log().Info("Set engine alias", zap.String("alias", req.Name), zap.Bool("created", create), zap.Any("targets", req.Targets))
+	return &AliasResponse{
+		Success: true,
+		Message: "Successfully set alias",
+		Alias:   protobuf.Clone(alias).(*Alias),
+	}, nil
+}
+
+func (s *Server) CreateAlias(ctx context.Context, req *SetAliasRequest) (*AliasResponse, error) {
+	monitor.GRPCTotal.Inc()
+	return setAlias(req, true)
+}
+
+func (s *Server) UpdateAlias(ctx context.Context, req *SetAliasRequest) (*AliasResponse, error) {
+	monitor.GRPCTotal.Inc()
+	return setAlias(req, false)
+}
+
+func (s *Server) DeleteAlias(ctx context.Context, req *DeleteAliasRequest) (*AliasResponse, error) {
+	monitor.GRPCTotal.Inc()
+	aliasMu.Lock()
+	alias, exists := aliases[req.Name]
+	if !exists {
+		aliasMu.Unlock()
+		return nil, status.Errorf(codes.NotFound, "alias %s not found", req.Name)
+	}
+	delete(aliases, req.Name)
+	aliasMu.Unlock()
+	saveRegistry()
+	logger.Log().Info("Deleted engine alias", zap.String("alias", req.Name))
+	return &AliasResponse{
+		Success: true,
+		Message: "Successfully deleted alias",
+		Alias:   alias,
+	}, nil
+}
+
+func (s *Server) ListAliases(ctx context.Context, req *emptypb.Empty) (*ListAliasesResponse, error) {
+	monitor.GRPCTotal.Inc()
+	return &ListAliasesResponse{Aliases: aliasList()}, nil
+}
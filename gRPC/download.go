@@ -0,0 +1,109 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDownloadChunk = 64 << 10
+	maxDownloadChunk     = 1 << 20
+)
+
+// sendModel 按上传相同的格式发送仓库中的模型文件：先发送 FileInfo，再发送数据块，最后发送校验信息
+func sendModel(req *DownloadModelRequest, send func(*DownloadModelResponse) error) (*DownloadTrailer, error) {
+	m, err := modelInfo(req.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	chunkSize := int(req.ChunkSize)
+	if chunkSize < 0 || chunkSize > maxDownloadChunk {
+		return nil, status.Errorf(codes.InvalidArgument, "chunk size must be between 0 and %d, got %d", maxDownloadChunk, req.ChunkSize)
+	}
+	if chunkSize == 0 {
+		chunkSize = defaultDownloadChunk
+	}
+	f, err := os.Open(m.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// 以打开的文件为准，避免下载过程中文件被替换导致大小与内容不一致
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	length := req.Length
+	if length == 0 {
+		length = size - req.Offset
+	}
+	if req.Offset < 0 || req.Length < 0 || req.Offset > size || req.Offset+length > size {
+		return nil, status.Errorf(codes.OutOfRange, "range offset %d length %d is outside model %s of %d bytes", req.Offset, req.Length, req.Name, size)
+	}
+	if _, err := f.Seek(req.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := send(&DownloadModelResponse{Data: &DownloadModelResponse_FileInfo{FileInfo: &FileInfo{
+		Name:     m.Name,
+		Size:     size,
+		FileType: m.Format,
+		Offset:   req.Offset,
+	}}}); err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	buf := make([]byte, chunkSize)
+	remaining := length
+	for remaining > 0 {
+		n, err := io.ReadFull(f, buf[:min(int64(chunkSize), remaining)])
+		if err != nil {
+			return nil, err
+		}
+		h.Write(buf[:n])
+		if err := send(&DownloadModelResponse{Data: &DownloadModelResponse_ChunkData{ChunkData: buf[:n]}}); err != nil {
+			return nil, err
+		}
+		remaining -= int64(n)
+	}
+	rangeSum := hex.EncodeToString(h.Sum(nil))
+	fileSum := rangeSum
+	if length != size {
+		if fileSum, err = fileSHA256(m.Path, info); err != nil {
+			return nil, err
+		}
+	}
+	trailer := &DownloadTrailer{
+		Size:        size,
+		Sha256:      fileSum,
+		RangeOffset: req.Offset,
+		RangeLength: length,
+		RangeSha256: rangeSum,
+	}
+	if err := send(&DownloadModelResponse{Data: &DownloadModelResponse_Trailer{Trailer: trailer}}); err != nil {
+		return nil, err
+	}
+	return trailer, nil
+}
+
+func (s *Server) DownloadModel(req *DownloadModelRequest, stream DetectService_DownloadModelServer) error {
+	monitor.GRPCTotal.Inc()
+	trailer, err := sendModel(req, stream.Send)
+	if err != nil {
+		return err
+	}
+	// 同时放入 gRPC trailer，便于只看元数据的客户端校验
+	stream.SetTrailer(metadata.Pairs("x-model-sha256", trailer.Sha256, "x-range-sha256", trailer.RangeSha256))
+	logger.Log().Info("Downloaded model", zap.String("name", req.Name), zap.Int64("offset", trailer.RangeOffset), zap.Int64("length", trailer.RangeLength))
+	return nil
+}
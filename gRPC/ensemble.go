@@ -0,0 +1,244 @@
+package proto
+
+import (
+	"OnnxDetServer/engine"
+	"OnnxDetServer/fusion"
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const defaultEnsembleIoU = 0.55
+
+// ensembleBackend 是由多个已有引擎组成的集成引擎：对同一张图片并行调用成员引擎，
+// 按类别映射统一类别名后用 WBF 或 NMS 融合结果
+type ensembleBackend struct {
+	req     *CreateEnsembleRequest
+	method  string
+	iou     float32
+	weights []float32
+}
+
+func newEnsembleBackend(req *CreateEnsembleRequest) (*ensembleBackend, error) {
+	if len(req.Members) < 2 {
+		return nil, fmt.Errorf("an ensemble needs at least two members, got %d", len(req.Members))
+	}
+	method := strings.ToLower(req.Method)
+	if method == "" {
+		method = fusion.MethodWBF
+	}
+	if method != fusion.MethodWBF && method != fusion.MethodNMS {
+		return nil, fmt.Errorf("unsupported fusion method %q, use wbf or nms", req.Method)
+	}
+	if req.IouThreshold < 0 || req.IouThreshold > 1 {
+		return nil, fmt.Errorf("IoU must be between 0.0 and 1.0, got %f", req.IouThreshold)
+	}
+	if req.SkipThreshold < 0 || req.SkipThreshold > 1 {
+		return nil, fmt.Errorf("skip threshold must be between 0.0 and 1.0, got %f", req.SkipThreshold)
+	}
+	iou := req.IouThreshold
+	if iou == 0 {
+		iou = defaultEnsembleIoU
+	}
+	weights := make([]float32, len(req.Members))
+	seen := make(map[string]bool, len(req.Members))
+	mapMu.RLock()
+	defer mapMu.RUnlock()
+	for i, m := range req.Members {
+		if _, exists := DSequences[m.EngineId]; !exists {
+			return nil, fmt.Errorf("ensemble member %s: %w", m.EngineId, engineNotFound(m.EngineId))
+		}
+		if seen[m.EngineId] {
+			return nil, fmt.Errorf("ensemble member %s is listed more than once", m.EngineId)
+		}
+		seen[m.EngineId] = true
+		if m.Weight < 0 {
+			return nil, fmt.Errorf("ensemble member %s has negative weight", m.EngineId)
+		}
+		weights[i] = m.Weight
+		if weights[i] == 0 {
+			weights[i] = 1
+		}
+	}
+	return &ensembleBackend{
+		req:     protobuf.Clone(req).(*CreateEnsembleRequest),
+		method:  method,
+		iou:     iou,
+		weights: weights,
+	}, nil
+}
+
+func (e *ensembleBackend) memberIDs() []string {
+	ids := make([]string, len(e.req.Members))
+	for i, m := range e.req.Members {
+		ids[i] = m.EngineId
+	}
+	return ids
+}
+
+// mapClasses 把成员结果的类别名映射为集成类别名，映射为空字符串的类别被丢弃
+func mapClasses(results map[string][]iface.Result, classMap map[string]string) map[string][]iface.Result {
+	if len(classMap) == 0 {
+		return results
+	}
+	mapped := make(map[string][]iface.Result, len(results))
+	for class, list := range results {
+		if name, ok := classMap[class]; ok {
+			if name == "" {
+				continue
+			}
+			class = name
+		}
+		mapped[class] = append(mapped[class], list...)
+	}
+	return mapped
+}
+
+// runMember 通过工作协程对一个成员引擎推理
+func runMember(m *EnsembleMember, image iface.ImageData) (map[string][]iface.Result, error) {
+	detResults, err := detectWith(m.EngineId, image)
+	if err != nil {
+		return nil, err
+	}
+	return mapClasses(detResults, m.ClassMap), nil
+}
+
+// detectWith 通过工作协程用引擎（UUID 或别名）推理，返回 Go 侧过滤后的结果
+func detectWith(id string, image iface.ImageData) (map[string][]iface.Result, error) {
+	engineID, _, err := resolveEngine(id)
+	if err != nil {
+		return nil, err
+	}
+	detector, err := acquireEngine(engineID)
+	if err != nil {
+		return nil, err
+	}
+	defer detector.release()
+	result := submitJob(detector.detector, image)
+	if !result.Data.Success {
+		return nil, fmt.Errorf("detector %s returned failure", id)
+	}
+	detResults, ok := result.Data.Data.(map[string][]iface.Result)
+	if !ok {
+		return nil, fmt.Errorf("unexpected data type in results of detector %s: %T", id, result.Data.Data)
+	}
+	return detector.filterResults(detResults), nil
+}
+
+// Detect 并行调用全部成员，部分成员失败时用其余成员的结果融合，全部失败时返回失败
+func (e *ensembleBackend) Detect(image iface.ImageData) iface.RetData {
+	results := make([]map[string][]iface.Result, len(e.req.Members))
+	errs := make([]error, len(e.req.Members))
+	var wg sync.WaitGroup
+	for i, m := range e.req.Members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = runMember(m, image)
+		}()
+	}
+	wg.Wait()
+	ok := make([]map[string][]iface.Result, 0, len(results))
+	weights := make([]float32, 0, len(results))
+	for i, r := range results {
+		if errs[i] != nil {
+			logger.Log().Warn("Ensemble member failed", zap.String("member", e.req.Members[i].EngineId), zap.Error(errs[i]))
+			continue
+		}
+		ok = append(ok, r)
+		weights = append(weights, e.weights[i])
+	}
+	if len(ok) == 0 {
+		return iface.RetData{Success: false, Data: fmt.Sprintf("all ensemble members failed: %v", errors.Join(errs...))}
+	}
+	var fused map[string][]iface.Result
+	if e.method == fusion.MethodNMS {
+		fused = fusion.NMS(ok, weights, e.iou, e.req.SkipThreshold)
+	} else {
+		fused = fusion.WeightedBoxesFusion(ok, weights, e.iou, e.req.SkipThreshold)
+	}
+	return iface.RetData{Success: true, Data: fused}
+}
+
+func (e *ensembleBackend) LoadModel(modelPath string, names iface.NamesConf, conf float32, iou float32, useGPU bool) (bool, error) {
+	return false, fmt.Errorf("ensemble engines do not load models")
+}
+
+// Destroy 不释放成员引擎，成员由各自的所有者管理
+func (e *ensembleBackend) Destroy() {}
+
+// CheckConfig 返回成员映射后的类别名并集
+func (e *ensembleBackend) CheckConfig() iface.EngineConfig {
+	names := make([]string, 0)
+	mapMu.RLock()
+	for _, m := range e.req.Members {
+		d, exists := DSequences[m.EngineId]
+		if !exists {
+			continue
+		}
+		memberNames, _ := d.detector.CheckConfig().Names.Data.([]string)
+		if f := d.filter.Load(); f != nil {
+			memberNames = f.names
+		}
+		for _, n := range memberNames {
+			if mapped, ok := m.ClassMap[n]; ok {
+				n = mapped
+			}
+			if n != "" && !slices.Contains(names, n) {
+				names = append(names, n)
+			}
+		}
+	}
+	mapMu.RUnlock()
+	return iface.EngineConfig{
+		ModelPath: fmt.Sprintf("ensemble(%s)", strings.Join(e.memberIDs(), ",")),
+		Names:     iface.NamesConf{Data: names},
+		Conf:      e.req.SkipThreshold,
+		Iou:       e.iou,
+	}
+}
+
+func (e *ensembleBackend) SetInputSize(size int)                    {}
+func (e *ensembleBackend) SetBlobName(inputName, outputName string) {}
+
+// createEnsemble 创建并注册集成引擎；id 为空时生成新的 UUID
+func createEnsemble(id string, req *CreateEnsembleRequest) (*WorkerID, error) {
+	backend, err := newEnsembleBackend(req)
+	if err != nil {
+		return nil, err
+	}
+	seqdet := &WorkerID{
+		detector:    backend,
+		Description: req.Description,
+		EngineType:  engine.Ensemble,
+		revision:    1,
+	}
+	if err := registerEngine(id, seqdet); err != nil {
+		return nil, err
+	}
+	return seqdet, nil
+}
+
+func (s *Server) CreateEnsemble(ctx context.Context, req *CreateEnsembleRequest) (*InitEngineResponse, error) {
+	monitor.GRPCTotal.Inc()
+	seqdet, err := createEnsemble("", req)
+	if err != nil {
+		return nil, err
+	}
+	saveRegistry()
+	logger.Log().Info("Created ensemble engine", zap.String("ID", seqdet.id), zap.Strings("members", seqdet.detector.(*ensembleBackend).memberIDs()), zap.String("method", req.Method))
+	return &InitEngineResponse{
+		Success: true,
+		Id:      seqdet.id,
+		Message: "Successfully created ensemble engine",
+	}, nil
+}
@@ -0,0 +1,131 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	evictInterval     = 5 * time.Second
+	evictDrainTimeout = 100 * time.Millisecond
+)
+
+// touch 记录引擎最近一次被使用的时间，用于空闲 TTL 与 LRU 回收
+func (d *WorkerID) touch() {
+	d.lastUsed.Store(time.Now().UnixMilli())
+}
+
+func (d *WorkerID) idleSince() time.Time {
+	return time.UnixMilli(d.lastUsed.Load())
+}
+
+// modelFileSizeMB 返回模型文件大小（MB），ncnn 模型会加上同名 .bin 权重文件
+func modelFileSizeMB(modelPath string) uint64 {
+	var total int64
+	if info, err := os.Stat(modelPath); err == nil {
+		total += info.Size()
+	}
+	if strings.HasSuffix(modelPath, ".param") {
+		bin := strings.TrimSuffix(modelPath, filepath.Ext(modelPath)) + ".bin"
+		if info, err := os.Stat(bin); err == nil {
+			total += info.Size()
+		}
+	}
+	return uint64(total) / 1024 / 1024
+}
+
+// StartEvictor 周期性销毁租约到期或空闲超过 TTL 的引擎；budgetMB 大于 0 时，
+// 进程 RSS 超出预算后按最近最少使用顺序回收空闲引擎，直到估算内存回到预算以内
+func StartEvictor(ctx context.Context, budgetMB int) {
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			var budget uint64
+			if budgetMB > 0 {
+				budget = uint64(budgetMB)
+			}
+			evictOnce(now, budget, monitor.RSSMegabytes())
+		}
+	}
+}
+
+func evictOnce(now time.Time, budgetMB uint64, rssMB uint64) {
+	mapMu.RLock()
+	all := maps.Clone(DSequences)
+	mapMu.RUnlock()
+
+	monitor.EngineMemoryEstimate.Set(float64(totalMemoryEstimateMB(all)))
+
+	idle := make([]string, 0, len(all))
+	for id, d := range all {
+		if d.getState() != EngineState_ENGINE_STATE_ACTIVE {
+			continue
+		}
+		// 租约到期的引擎在没有在途任务时销毁，忙碌时留到下一轮
+		if d.leaseExpired(now) {
+			expiry := time.UnixMilli(d.leaseExpiry.Load())
+			evictEngine(id, d, "lease_expired", reasonLease, fmt.Sprintf("lease expired at %s", expiry.Format(time.RFC3339)))
+			continue
+		}
+		if d.jobs.count() > 0 {
+			continue
+		}
+		if d.idleTTL > 0 && now.Sub(d.idleSince()) > d.idleTTL {
+			evictEngine(id, d, "idle_ttl", reasonEvicted, fmt.Sprintf("evicted after being idle for more than %s", d.idleTTL))
+			continue
+		}
+		// 预加载的引擎需要常驻，不参与内存预算回收
+		if !d.preloaded {
+			idle = append(idle, id)
+		}
+	}
+
+	if budgetMB == 0 || rssMB <= budgetMB {
+		return
+	}
+	slices.SortFunc(idle, func(a, b string) int {
+		return all[a].idleSince().Compare(all[b].idleSince())
+	})
+	need := int64(rssMB - budgetMB)
+	for _, id := range idle {
+		if need <= 0 {
+			break
+		}
+		d := all[id]
+		if d.memoryEstimateMB() == 0 && d.shared != nil {
+			// 共享实例仍被其他句柄使用，回收该句柄释放不了内存
+			continue
+		}
+		detail := fmt.Sprintf("evicted to keep memory under budget (rss %dMB > %dMB)", rssMB, budgetMB)
+		if evictEngine(id, d, "memory_budget", reasonEvicted, detail) {
+			need -= int64(max(d.memoryEstimateMB(), 1))
+		}
+	}
+}
+
+func evictEngine(id string, d *WorkerID, kind, reason, detail string) bool {
+	if err := drainEngine(id, evictDrainTimeout, false, reason, detail); err != nil {
+		// 回收过程中有新请求进入，保留该引擎
+		logger.Log().Debug("Skip evicting busy engine", zap.String("ID", id), zap.Error(err))
+		return false
+	}
+	monitor.EngineEvictions.WithLabelValues(kind).Inc()
+	logger.Log().Warn("Evicted engine", zap.String("ID", id), zap.String("reason", kind),
+		zap.String("description", d.Description), zap.Uint64("memoryEstimateMB", d.memoryEstimateMB()),
+		zap.Time("lastUsed", d.idleSince()))
+	return true
+}
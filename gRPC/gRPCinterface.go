@@ -3,22 +3,28 @@ package proto
 import (
 	"OnnxDetServer/engine"
 	iface "OnnxDetServer/interface"
+	"OnnxDetServer/isolation"
 	"OnnxDetServer/logger"
 	"OnnxDetServer/monitor"
+	"OnnxDetServer/zones"
+	"cmp"
 	"context"
 	"fmt"
-	"io"
 	"log"
 	"maps"
 	"net"
-	"os"
 	"runtime"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -26,23 +32,70 @@ type WorkerID struct {
 	detector    iface.Backend
 	Description string
 	EngineType  int
+
+	jobs       inflight
+	mu         sync.Mutex
+	state      EngineState
+	freeOnIdle bool
+	freeOnce   sync.Once
+
+	idleTTL       time.Duration
+	memEstimateMB uint64
+	lastUsed      atomic.Int64
+	lease         time.Duration
+	leaseExpiry   atomic.Int64
+
+	shared         *sharedInstance
+	fingerprint    string
+	idempotencyKey string
+	request        *InitEngineRequest
+	id             string
+	// preloaded 的引擎来自 config.yaml，不写入状态文件，也不参与内存预算回收
+	preloaded bool
+
+	// native 是原生实例加载时的参数，filter 为就地更新后在 Go 侧追加的过滤与重命名
+	native   engineSpec
+	filter   atomic.Pointer[outputFilter]
+	revision int64
+	// shadow 非 nil 时按采样比例把请求异步复制给影子引擎对比
+	shadow atomic.Pointer[shadowConfig]
+	// zoneRules 非 nil 时每次推理按这些区域规则计数，带跟踪会话的请求同时产生区域事件
+	zoneRules atomic.Pointer[zones.Config]
 }
 
 var (
-	DSequences map[string]WorkerID
+	DSequences map[string]*WorkerID
 	seqMu      sync.Mutex
 	mapMu      sync.RWMutex
 )
 
+// Isolation 非 nil 时，引擎在由它管理的子进程中加载和推理
+var Isolation *isolation.Supervisor
+
+func newBackend(group string) (iface.Backend, error) {
+	if Isolation != nil {
+		return Isolation.NewBackend(group)
+	}
+	detector := &engine.Detector{}
+	detector.New()
+	return detector, nil
+}
+
 func (d *WorkerID) add2Seq(detector iface.Backend, description string, engineType int) string {
+	return d.add2SeqWithID(uuid.New().String(), detector, description, engineType)
+}
+
+// add2SeqWithID 以指定 ID 注册引擎，用于恢复持久化的引擎和预加载配置中的引擎
+func (d *WorkerID) add2SeqWithID(UUID string, detector iface.Backend, description string, engineType int) string {
 	d.detector = detector
 	d.Description = description
 	if engineType == engine.MultiThread {
 		panic("Multi-threading is not supported yet")
 	}
 	d.EngineType = engineType
-	UUID := uuid.New().String()
-	DSequences[UUID] = *d
+	d.id = UUID
+	d.touch()
+	DSequences[UUID] = d
 	output := fmt.Sprintf("Detector %s added with ID %s\n", description, UUID)
 	logger.Log().Info(output)
 	return UUID
@@ -58,11 +111,28 @@ type jobResult struct {
 	Data iface.RetData
 }
 
+// submitJob 把推理任务交给工作协程执行并等待结果
+func submitJob(worker iface.Backend, image iface.ImageData) jobResult {
+	// 集成引擎自身把成员任务分发给工作协程，不能再占用一个工作协程等待
+	if e, ok := worker.(*ensembleBackend); ok {
+		return jobResult{Data: e.Detect(image)}
+	}
+	inferResult := make(chan jobResult)
+	defer close(inferResult)
+	JobQueue <- JobPackage{
+		image:  image,
+		worker: worker,
+		Result: inferResult,
+	}
+	return <-inferResult
+}
+
 var JobQueue chan JobPackage
 
 var CloseChannel chan bool
 
 func StartWorker(workerNum int) {
+	setShadowLimit(workerNum)
 	for i := 0; i < workerNum; i++ {
 		go runWorker(i)
 	}
@@ -94,61 +164,216 @@ type Server struct {
 
 func (s *Server) InitEngine(ctx context.Context, req *InitEngineRequest) (*InitEngineResponse, error) {
 	monitor.GRPCTotal.Inc()
-	detector := engine.Detector{}
-	detector.New()
-	names := iface.NamesConf{}
-	names.IsFile = false
-	names.Data = req.Names
-	if req.Iou > 1.0 || req.Iou < 0.0 {
-		return nil, fmt.Errorf("IoU must be between 0.0 and 1.0, got %f", req.Iou)
+	if err := validateInitRequest(req); err != nil {
+		return nil, err
 	}
-	if req.Confidence > 1.0 || req.Confidence < 0.0 {
-		return nil, fmt.Errorf("confidence must be between 0.0 and 1.0, got %f", req.Confidence)
-	}
-	if req.ModelPath == "" {
-		return nil, fmt.Errorf("model path cannot be empty")
+	var release func(id string)
+	if req.IdempotencyKey != "" {
+		id, r, err := claimIdempotencyKey(ctx, req.IdempotencyKey, requestFingerprint(req))
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			logger.Log().Info("Returning existing engine for idempotency key", zap.String("ID", id), zap.String("key", req.IdempotencyKey))
+			return &InitEngineResponse{
+				Success: true,
+				Id:      id,
+				Message: "Engine already initialized for this idempotency key",
+			}, nil
+		}
+		release = r
 	}
-	seqMu.Lock()
-	_, err := detector.LoadModel(req.ModelPath, names, req.Confidence, req.Iou, req.UseGpu)
+
+	seqdet, reused, err := createEngine("", req)
 	if err != nil {
-		seqMu.Unlock()
+		if release != nil {
+			release("")
+		}
 		logger.Log().Error("Failed to load model", zap.String("ModelPath", req.ModelPath), zap.Error(err))
 		return &InitEngineResponse{
 			Success: false,
 			Id:      "",
-			Message: fmt.Sprintf("Failed to load model: %v", err),
+			Message: fmt.Sprintf("Failed to initialize engine: %v", err),
 		}, nil
 	}
-	detector.SetInputSize(int(req.InputSize))
-	seqMu.Unlock()
-	seqdet := WorkerID{}
-	seqdet.EngineType = int(req.EngineType)
-	seqdet.Description = req.Description
-	seqdet.detector = &detector
-	mapMu.Lock()
-	Id := seqdet.add2Seq(&detector, req.Description, int(req.EngineType))
-	mapMu.Unlock()
-	logger.Log().Info("Initialized new engine", zap.String("ID", Id), zap.String("ModelPath", req.ModelPath), zap.Float32("Confidence", req.Confidence), zap.Float32("IoU", req.Iou), zap.Bool("UseGPU", req.UseGpu))
+	Id := seqdet.id
+	if release != nil {
+		release(Id)
+	}
+	saveRegistry()
+	logger.Log().Info("Initialized new engine", zap.String("ID", Id), zap.String("ModelPath", req.ModelPath), zap.Float32("Confidence", req.Confidence), zap.Float32("IoU", req.Iou), zap.Bool("UseGPU", req.UseGpu), zap.Bool("shared", req.Share), zap.Bool("reusedInstance", reused))
+	message := "Successfully initialized engine"
+	if reused {
+		message = "Successfully initialized engine on a shared instance"
+	}
 	return &InitEngineResponse{
 		Success: true,
 		Id:      Id,
-		Message: "Successfully initialized engine",
+		Message: message,
 	}, nil
 }
 
+// validateInitRequest 检查创建引擎的参数
+func validateInitRequest(req *InitEngineRequest) error {
+	if req.Iou > 1.0 || req.Iou < 0.0 {
+		return fmt.Errorf("IoU must be between 0.0 and 1.0, got %f", req.Iou)
+	}
+	if req.Confidence > 1.0 || req.Confidence < 0.0 {
+		return fmt.Errorf("confidence must be between 0.0 and 1.0, got %f", req.Confidence)
+	}
+	if req.ModelPath == "" {
+		return fmt.Errorf("model path cannot be empty")
+	}
+	if len(req.Names) > 0 && req.NamesFile != "" {
+		return fmt.Errorf("names and names file cannot both be set")
+	}
+	if req.IdleTtlSeconds < 0 {
+		return fmt.Errorf("idle TTL cannot be negative, got %d", req.IdleTtlSeconds)
+	}
+	if req.LeaseSeconds < 0 {
+		return fmt.Errorf("lease cannot be negative, got %d", req.LeaseSeconds)
+	}
+	return nil
+}
+
+// createEngine 按请求加载原生实例并注册引擎；id 为空时生成新的 UUID
+func createEngine(id string, req *InitEngineRequest) (*WorkerID, bool, error) {
+	if id != "" {
+		mapMu.RLock()
+		_, exists := DSequences[id]
+		mapMu.RUnlock()
+		if exists {
+			return nil, false, fmt.Errorf("engine ID %s already exists", id)
+		}
+		if aliasExists(id) {
+			return nil, false, fmt.Errorf("engine ID %s conflicts with an alias", id)
+		}
+	}
+	seqdet, reused, err := newWorker(req)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := registerEngine(id, seqdet); err != nil {
+		seqdet.free()
+		return nil, false, err
+	}
+	return seqdet, reused, nil
+}
+
+// registerEngine 把已加载的引擎加入注册表；id 为空时生成新的 UUID
+func registerEngine(id string, seqdet *WorkerID) error {
+	mapMu.Lock()
+	defer mapMu.Unlock()
+	if id == "" {
+		seqdet.add2Seq(seqdet.detector, seqdet.Description, seqdet.EngineType)
+		return nil
+	}
+	if _, exists := DSequences[id]; exists {
+		return fmt.Errorf("engine ID %s already exists", id)
+	}
+	if aliasExists(id) {
+		return fmt.Errorf("engine ID %s conflicts with an alias", id)
+	}
+	delete(tombstones, id)
+	seqdet.add2SeqWithID(id, seqdet.detector, seqdet.Description, seqdet.EngineType)
+	return nil
+}
+
+// resolveSpec 按请求生成原生实例参数：依次读取类别名文件、套用模型清单与 ONNX 元数据、校验并补全 ncnn blob 名
+func resolveSpec(req *InitEngineRequest) (engineSpec, error) {
+	spec := specFromRequest(req)
+	if req.NamesFile != "" {
+		names, err := engine.ReadNamesFile(resolveModelPath(req.NamesFile))
+		if err != nil {
+			return spec, fmt.Errorf("failed to read names file: %w", err)
+		}
+		spec.Names = names
+	}
+	spec, err := withManifest(spec)
+	if err != nil {
+		return spec, err
+	}
+	if spec, err = withModelMetadata(spec); err != nil {
+		return spec, err
+	}
+	return checkBlobs(spec)
+}
+
+// newWorker 按请求加载（或复用共享的）原生实例，返回尚未注册的引擎
+func newWorker(req *InitEngineRequest) (*WorkerID, bool, error) {
+	spec, err := resolveSpec(req)
+	if err != nil {
+		return nil, false, err
+	}
+	seqdet := &WorkerID{}
+	reused := false
+	if req.Share {
+		inst, hit, err := attachShared(spec)
+		if err != nil {
+			return nil, false, err
+		}
+		seqdet.shared = inst
+		seqdet.detector = inst.backend
+		reused = hit
+	} else {
+		detector, memEstimate, err := loadBackend(spec)
+		if err != nil {
+			return nil, false, err
+		}
+		seqdet.detector = detector
+		seqdet.memEstimateMB = memEstimate
+	}
+	seqdet.fingerprint = spec.fingerprint()
+	seqdet.native = spec
+	seqdet.revision = 1
+	seqdet.idleTTL = time.Duration(req.IdleTtlSeconds) * time.Second
+	seqdet.renewLease(time.Now(), time.Duration(req.LeaseSeconds)*time.Second)
+	seqdet.idempotencyKey = req.IdempotencyKey
+	seqdet.request = protobuf.Clone(req).(*InitEngineRequest)
+	seqdet.EngineType = int(req.EngineType)
+	seqdet.Description = req.Description
+	return seqdet, reused, nil
+}
+
 func (s *Server) Inference(ctx context.Context, req *InferenceRequest) (*InferenceResponse, error) {
 	monitor.GRPCTotal.Inc()
-	UUID := req.Id
-	mapMu.RLock()
-	detector, exists := DSequences[UUID]
-	mapMu.RUnlock()
-	if !exists {
-		return nil, fmt.Errorf("detector with ID %s not found", UUID)
+	return runInference(req)
+}
+
+// runInference 对一个引擎（UUID 或别名）执行推理
+func runInference(req *InferenceRequest) (*InferenceResponse, error) {
+	return inferFrame(req, nil, "", time.Now())
+}
+
+// inferFrame 执行一帧推理；session 为 nil 时按 req.SessionId 查找跟踪会话，
+// streamID 为流式推理的流标识（webhook 据此区分不同的流），at 是计算区域停留时长用的帧时间
+func inferFrame(req *InferenceRequest, session *trackingSession, streamID string, at time.Time) (*InferenceResponse, error) {
+	UUID, target, err := resolveEngine(req.Id)
+	if err != nil {
+		return nil, err
 	}
+	detector, err := acquireEngine(UUID)
+	if err != nil {
+		return nil, err
+	}
+	var alias, version string
+	if target != nil {
+		alias, version = req.Id, target.Version
+		monitor.AliasRequests.WithLabelValues(alias, cmp.Or(version, UUID)).Inc()
+	}
+	defer detector.release()
 
 	if req.ImgData == nil || req.ImgData.Data == nil || len(req.ImgData.Data) == 0 || req.ImgData.Width == 0 || req.ImgData.Height == 0 || req.ImgData.Channels == 0 {
 		return nil, fmt.Errorf("image data is invalid")
 	}
+	if req.Zones != nil && req.SessionId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "zones on an inference request require a session id")
+	}
+	if session == nil && req.SessionId != "" {
+		if session, err = trackingSessionFor(req.SessionId, req.Tracker, req.Zones); err != nil {
+			return nil, err
+		}
+	}
 
 	imageData := iface.ImageData{
 		Data:     req.ImgData.Data,
@@ -156,20 +381,15 @@ func (s *Server) Inference(ctx context.Context, req *InferenceRequest) (*Inferen
 		Height:   req.ImgData.Height,
 		Channels: req.ImgData.Channels,
 	}
-	inferResult := make(chan jobResult)
-	defer close(inferResult)
-	job := JobPackage{
-		image:  imageData,
-		worker: detector.detector,
-		Result: inferResult,
-	}
-	JobQueue <- job
-	results := <-inferResult
+	results := submitJob(detector.detector, imageData)
 	if results.Data.Data == nil {
 		logger.Log().Error("detector returned nil result")
 		return &InferenceResponse{
-			Success: false,
-			Results: make([]*SingleResult, 0),
+			Success:  false,
+			Results:  make([]*SingleResult, 0),
+			EngineId: UUID,
+			Alias:    alias,
+			Version:  version,
 		}, nil
 	}
 	switch v := results.Data.Data.(type) {
@@ -177,13 +397,17 @@ func (s *Server) Inference(ctx context.Context, req *InferenceRequest) (*Inferen
 		{
 			logger.Log().Error("detector returned not supported string result:", zap.String("data", v))
 			return &InferenceResponse{
-				Success: false,
-				Results: make([]*SingleResult, 0),
+				Success:  false,
+				Results:  make([]*SingleResult, 0),
+				EngineId: UUID,
+				Alias:    alias,
+				Version:  version,
 			}, nil
 		}
 	case map[string][]iface.Result:
 		{
-			detResults := results.Data.Data.(map[string][]iface.Result)
+			detResults := detector.filterResults(results.Data.Data.(map[string][]iface.Result))
+			detector.maybeShadow(imageData, detResults)
 			singleResults := make([]*SingleResult, 0, len(detResults))
 			for class, resList := range detResults {
 				for _, res := range resList {
@@ -201,18 +425,32 @@ func (s *Server) Inference(ctx context.Context, req *InferenceRequest) (*Inferen
 					singleResults = append(singleResults, singleResult)
 				}
 			}
-			return &InferenceResponse{
-				Success: true,
-				Results: singleResults,
-			}, nil
+			resp := &InferenceResponse{
+				Success:  true,
+				Results:  singleResults,
+				EngineId: UUID,
+				Alias:    alias,
+				Version:  version,
+			}
+			rules := detector.zoneRules.Load()
+			if session != nil {
+				session.apply(resp, rules, at)
+			} else if rules != nil {
+				resp.ZoneCounts = zoneCounts(rules.Count(zoneObjects(resp.Results)))
+			}
+			publishFrame(req, resp, streamID)
+			return resp, nil
 		}
 	default:
 		{
 			output := fmt.Sprintf("Unknown type: %T", v)
 			logger.Log().Error(output)
 			return &InferenceResponse{
-				Success: false,
-				Results: make([]*SingleResult, 0),
+				Success:  false,
+				Results:  make([]*SingleResult, 0),
+				EngineId: UUID,
+				Alias:    alias,
+				Version:  version,
 			}, fmt.Errorf("unexpected data type in results: %T", results.Data.Data)
 		}
 	}
@@ -222,33 +460,31 @@ func (s *Server) Inference(ctx context.Context, req *InferenceRequest) (*Inferen
 func (s *Server) DestroyEngine(ctx context.Context, req *DestroyEngineRequest) (*DestroyEngineResponse, error) {
 	monitor.GRPCTotal.Inc()
 	UUID := req.Id
-	mapMu.Lock()
-	detector, exists := DSequences[UUID]
-	if !exists {
-		mapMu.Unlock()
-		logger.Log().Error("detector not found with ID", zap.String("ID", UUID))
-		return nil, fmt.Errorf("detector with ID %s not found", UUID)
-	}
-	detector.detector.Destroy()
-	delete(DSequences, UUID)
-	mapMu.Unlock()
-	logger.Log().Info("Destroyed engine", zap.String("ID", UUID))
+	timeout := time.Duration(req.TimeoutMs) * time.Millisecond
+	if discardFailedRestore(UUID) {
+		logger.Log().Info("Discarded engine definition that failed to restore", zap.String("ID", UUID))
+		return &DestroyEngineResponse{
+			Success: true,
+			Message: "Discarded engine definition that failed to restore",
+		}, nil
+	}
+	if err := drainEngine(UUID, timeout, req.Force, reasonDestroyed, "destroyed"); err != nil {
+		logger.Log().Error("Failed to destroy engine", zap.String("ID", UUID), zap.Error(err))
+		return nil, err
+	}
+	logger.Log().Info("Destroyed engine", zap.String("ID", UUID), zap.Bool("force", req.Force))
 	return &DestroyEngineResponse{
 		Success: true,
 		Message: "Detector destroyed successfully",
 	}, nil
 }
 
-func (s *Server) CheckEngine(ctx context.Context, req *CheckEngineRequest) (*CheckEngineResponse, error) {
-	monitor.GRPCTotal.Inc()
-	UUID := req.Id
-	mapMu.RLock()
-	detector, exists := DSequences[UUID]
-	mapMu.RUnlock()
-	if !exists {
-		return nil, fmt.Errorf("detector with ID %s not found", UUID)
-	}
+func engineInfo(id string, detector *WorkerID) (*EngineInfo, error) {
 	Dconfig := detector.detector.CheckConfig()
+	var shareCount int32
+	if detector.shared != nil {
+		shareCount = int32(detector.shared.refCount())
+	}
 	names := make([]string, 0)
 	switch v := Dconfig.Names.Data.(type) {
 	case []string:
@@ -261,15 +497,65 @@ func (s *Server) CheckEngine(ctx context.Context, req *CheckEngineRequest) (*Che
 		logger.Log().Error(output)
 		return nil, fmt.Errorf("unexpected type for names: %T", Dconfig.Names.Data)
 	}
-	ret := &EngineInfo{
-		Id:          UUID,
-		Description: detector.Description,
-		EngineType:  int32(detector.EngineType),
-		ModelPath:   Dconfig.ModelPath,
-		Names:       names,
-		Confidence:  Dconfig.Conf,
-		Iou:         Dconfig.Iou,
-		UseGpu:      Dconfig.UseGPU,
+	var members []string
+	var fusionMethod string
+	if e, ok := detector.detector.(*ensembleBackend); ok {
+		members = e.memberIDs()
+		fusionMethod = e.method
+	}
+	conf := Dconfig.Conf
+	if f := detector.filter.Load(); f != nil {
+		// 就地更新过的引擎以 Go 侧生效的阈值与类别名为准
+		conf = f.minConf
+		names = f.names
+	}
+	return &EngineInfo{
+		Id:                 id,
+		Description:        detector.Description,
+		EngineType:         int32(detector.EngineType),
+		ModelPath:          Dconfig.ModelPath,
+		Names:              names,
+		Confidence:         conf,
+		Iou:                Dconfig.Iou,
+		UseGpu:             Dconfig.UseGPU,
+		State:              detector.getState(),
+		InFlight:           int32(detector.jobs.count()),
+		IdleTtlSeconds:     int32(detector.idleTTL / time.Second),
+		MemoryEstimateMb:   int64(detector.memEstimateMB),
+		LastUsedUnixMs:     detector.lastUsed.Load(),
+		LeaseSeconds:       int32(detector.leaseDuration() / time.Second),
+		LeaseExpiresUnixMs: detector.leaseExpiry.Load(),
+		Shared:             detector.shared != nil,
+		Fingerprint:        detector.fingerprint,
+		ShareCount:         shareCount,
+		Preloaded:          detector.preloaded,
+		ConfigRevision:     detector.configRevision(),
+		Members:            members,
+		FusionMethod:       fusionMethod,
+	}, nil
+}
+
+func (s *Server) CheckEngine(ctx context.Context, req *CheckEngineRequest) (*CheckEngineResponse, error) {
+	monitor.GRPCTotal.Inc()
+	UUID := req.Id
+	mapMu.RLock()
+	detector, exists := DSequences[UUID]
+	if !exists {
+		t, destroyed := tombstones[UUID]
+		mapMu.RUnlock()
+		if destroyed {
+			return &CheckEngineResponse{
+				Success:    false,
+				EngineInfo: &EngineInfo{Id: UUID, State: EngineState_ENGINE_STATE_DESTROYED},
+				Message:    fmt.Sprintf("Detector %s at %s", t.detail, t.at.Format(time.RFC3339)),
+			}, nil
+		}
+		return nil, status.Errorf(codes.NotFound, "detector with ID %s not found", UUID)
+	}
+	mapMu.RUnlock()
+	ret, err := engineInfo(UUID, detector)
+	if err != nil {
+		return nil, err
 	}
 	return &CheckEngineResponse{
 		Success:    true,
@@ -285,33 +571,18 @@ func (s *Server) CheckAllEngine(ctx context.Context, req *emptypb.Empty) (*Check
 	mapMu.RUnlock()
 	engineInfos := make([]*EngineInfo, 0, len(allSeq))
 	for id, detector := range allSeq {
-		Dconfig := detector.detector.CheckConfig()
-		names := make([]string, 0)
-		switch Dconfig.Names.Data.(type) {
-		case []string:
-			names = Dconfig.Names.Data.([]string)
-		case string:
-			names = []string{}
-			names = append(names, "From File")
-		default:
-			return nil, fmt.Errorf("unexpected type for names: %T", Dconfig.Names.Data)
-		}
-		engineInfo := &EngineInfo{
-			Id:          id,
-			Description: detector.Description,
-			EngineType:  int32(detector.EngineType),
-			ModelPath:   Dconfig.ModelPath,
-			Names:       names,
-			Confidence:  Dconfig.Conf,
-			Iou:         Dconfig.Iou,
-			UseGpu:      Dconfig.UseGPU,
+		engineInfo, err := engineInfo(id, detector)
+		if err != nil {
+			return nil, err
 		}
 		engineInfos = append(engineInfos, engineInfo)
 	}
 	return &CheckAllEngineResponse{
-		Success: true,
-		Engines: engineInfos,
-		Message: "All Detectors status retrieved successfully",
+		Success:         true,
+		Engines:         engineInfos,
+		Message:         "All Detectors status retrieved successfully",
+		RestoreFailures: restoreFailureList(),
+		PreloadFailures: preloadFailureList(),
 	}, nil
 }
 
@@ -319,12 +590,14 @@ func (s *Server) Shutdown(ctx context.Context, req *emptypb.Empty) (*emptypb.Emp
 	monitor.GRPCTotal.Inc()
 	go func() {
 		time.Sleep(2 * time.Second)
-		mapMu.Lock()
-		for id, detector := range DSequences {
-			detector.detector.Destroy()
-			delete(DSequences, id)
+		mapMu.RLock()
+		ids := slices.Collect(maps.Keys(DSequences))
+		mapMu.RUnlock()
+		for _, id := range ids {
+			if err := drainEngine(id, defaultDrainTimeout, true, reasonShutdown, "server shutdown"); err != nil {
+				logger.Log().Warn("Failed to destroy engine on shutdown", zap.String("ID", id), zap.Error(err))
+			}
 		}
-		mapMu.Unlock()
 		close(JobQueue)
 		fmt.Println("Server shutting down in 1 second...")
 		time.Sleep(1 * time.Second)
@@ -335,57 +608,6 @@ func (s *Server) Shutdown(ctx context.Context, req *emptypb.Empty) (*emptypb.Emp
 	return &emptypb.Empty{}, nil
 }
 
-func (s *Server) UploadModel(stream DetectService_UploadModelServer) error {
-	monitor.GRPCTotal.Inc()
-	var outFile *os.File
-	var fileSize int
-	var filePath string
-
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			if outFile != nil {
-				err := outFile.Close()
-				if err != nil {
-					return err
-				}
-			}
-			return stream.SendAndClose(&UploadFileResponse{
-				Success:  true,
-				Message:  "File uploaded successfully",
-				FilePath: filePath,
-			})
-		}
-		if err != nil {
-			return err
-		}
-
-		switch payload := req.Data.(type) {
-		case *UploadFileRequest_FileInfo:
-			fmt.Printf("Received UploadFileRequest_FileInfo: %+v\n", payload.FileInfo.Name)
-			saveDir := "models/"
-			fileName := payload.FileInfo.Name
-			if fileName == "" {
-				return fmt.Errorf("file name cannot be empty")
-			}
-			filePath = saveDir + fileName
-			outFile, err = os.Create(filePath)
-			if err != nil {
-				return err
-			}
-		case *UploadFileRequest_ChunkData:
-			if outFile == nil {
-				return fmt.Errorf("file not opened, please send file info first")
-			}
-			n, writeErr := outFile.Write(payload.ChunkData)
-			if writeErr != nil {
-				return fmt.Errorf("failed to write chunk data: %v", writeErr)
-			}
-			fileSize += n
-		}
-	}
-}
-
 func StartGRPCServer(addr int) *grpc.Server {
 	CloseChannel = make(chan bool)
 	//ServChan = make(chan *grpc.Server)
@@ -1,25 +1,52 @@
 package proto
 
 import (
+	"OnnxDetServer/engine"
 	iface "OnnxDetServer/interface"
 	"OnnxDetServer/monitor"
+	"OnnxDetServer/onnx"
+	"OnnxDetServer/pipeline"
+	"OnnxDetServer/webhook"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	protobuf "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-type MockBackend struct{}
+type MockBackend struct {
+	destroyed  int
+	failDetect bool
+}
 
-func (m *MockBackend) LoadModel(modelPath string, names iface.NamesConf, conf float32, iou float32, useGPU bool) bool {
+func (m *MockBackend) LoadModel(modelPath string, names iface.NamesConf, conf float32, iou float32, useGPU bool) (bool, error) {
 	fmt.Printf("Mock LoadModel called with modelPath: %s, names: %v, conf: %f, iou: %f, useGPU: %v\n", modelPath, names, conf, iou, useGPU)
-	return true
+	return true, nil
 }
 func (m *MockBackend) Detect(mat iface.ImageData) iface.RetData {
+	if m.failDetect {
+		return iface.RetData{Success: false, Data: "mock failure"}
+	}
 	fakeResult := map[string][]iface.Result{}
 	fmt.Println("AAAA - Mock Detect Called") // 添加标识以确认被调用
 	fakeResult["mock"] = []iface.Result{
@@ -36,7 +63,7 @@ func (m *MockBackend) Detect(mat iface.ImageData) iface.RetData {
 	}
 	return iface.RetData{Success: true, Data: fakeResult}
 }
-func (m *MockBackend) Destroy() {}
+func (m *MockBackend) Destroy() { m.destroyed++ }
 func (m *MockBackend) CheckConfig() iface.EngineConfig {
 	return iface.EngineConfig{ModelPath: "mock", Names: iface.NamesConf{Data: []string{"mock"}}, Conf: 0.99, Iou: 0.5, UseGPU: false}
 }
@@ -50,7 +77,7 @@ func TestMockEngine(t *testing.T) {
 
 	backend := &MockBackend{}
 	worker := &WorkerID{}
-	DSequences = make(map[string]WorkerID)
+	DSequences = make(map[string]*WorkerID)
 	id := worker.add2Seq(backend, "mock_worker", 4097)
 
 	server := StartGRPCServer(50051)
@@ -133,3 +160,1517 @@ func TestMockEngine(t *testing.T) {
 
 	cancel()
 }
+
+func TestDestroyEngineDrain(t *testing.T) {
+	backend := &MockBackend{}
+	worker := &WorkerID{}
+	DSequences = make(map[string]*WorkerID)
+	id := worker.add2Seq(backend, "drain_worker", engine.SingleThread)
+
+	d, err := acquireEngine(id)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("Timeout keeps engine active", func(t *testing.T) {
+		err := drainEngine(id, 50*time.Millisecond, false, reasonDestroyed, "destroyed")
+		assert.Error(t, err)
+		assert.Equal(t, EngineState_ENGINE_STATE_ACTIVE, d.getState())
+		assert.Equal(t, 0, backend.destroyed)
+	})
+
+	t.Run("Destroy waits for in-flight jobs", func(t *testing.T) {
+		done := make(chan error, 1)
+		go func() { done <- drainEngine(id, 2*time.Second, false, reasonDestroyed, "destroyed") }()
+		assert.Eventually(t, func() bool {
+			return d.getState() == EngineState_ENGINE_STATE_DRAINING
+		}, time.Second, 5*time.Millisecond)
+		_, err := acquireEngine(id)
+		assert.Error(t, err)
+		assert.Equal(t, 0, backend.destroyed)
+
+		d.release()
+		assert.NoError(t, <-done)
+		assert.Equal(t, 1, backend.destroyed)
+		assert.Equal(t, EngineState_ENGINE_STATE_DESTROYED, d.getState())
+		_, err = acquireEngine(id)
+		assert.ErrorContains(t, err, "destroyed")
+	})
+
+	t.Run("Force frees after last job", func(t *testing.T) {
+		forced := &MockBackend{}
+		id := (&WorkerID{}).add2Seq(forced, "force_worker", engine.SingleThread)
+		d, err := acquireEngine(id)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, drainEngine(id, 0, true, reasonDestroyed, "destroyed"))
+		assert.Equal(t, 0, forced.destroyed)
+		d.release()
+		assert.Equal(t, 1, forced.destroyed)
+	})
+
+	t.Run("Replaced during drain keeps new engine", func(t *testing.T) {
+		oldBackend := &MockBackend{}
+		id := (&WorkerID{}).add2Seq(oldBackend, "swap_worker", engine.SingleThread)
+		d, err := acquireEngine(id)
+		if !assert.NoError(t, err) {
+			return
+		}
+		done := make(chan error, 1)
+		go func() { done <- drainEngine(id, 2*time.Second, false, reasonDestroyed, "destroyed") }()
+		assert.Eventually(t, func() bool {
+			return d.getState() == EngineState_ENGINE_STATE_DRAINING
+		}, time.Second, 5*time.Millisecond)
+		// 模拟重载在等待期间换入新引擎
+		next := &WorkerID{id: id, detector: &MockBackend{}}
+		mapMu.Lock()
+		DSequences[id] = next
+		mapMu.Unlock()
+
+		d.release()
+		assert.NoError(t, <-done)
+		assert.Equal(t, 1, oldBackend.destroyed)
+		mapMu.RLock()
+		assert.Same(t, next, DSequences[id])
+		mapMu.RUnlock()
+	})
+}
+
+func TestEvictEngines(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	now := time.Now()
+
+	t.Run("Idle TTL", func(t *testing.T) {
+		ttl := &WorkerID{idleTTL: time.Minute}
+		ttlID := ttl.add2Seq(&MockBackend{}, "ttl_worker", engine.SingleThread)
+		ttl.lastUsed.Store(now.Add(-2 * time.Minute).UnixMilli())
+		keep := &WorkerID{}
+		keepID := keep.add2Seq(&MockBackend{}, "keep_worker", engine.SingleThread)
+		keep.lastUsed.Store(now.Add(-2 * time.Hour).UnixMilli())
+
+		evictOnce(now, 0, 0)
+		_, err := acquireEngine(ttlID)
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		if assert.Len(t, st.Details(), 1) {
+			assert.Equal(t, reasonEvicted, st.Details()[0].(*errdetails.ErrorInfo).Reason)
+		}
+		d, err := acquireEngine(keepID)
+		assert.NoError(t, err)
+		d.release()
+	})
+
+	t.Run("Memory budget evicts least recently used", func(t *testing.T) {
+		DSequences = make(map[string]*WorkerID)
+		old := &WorkerID{memEstimateMB: 100}
+		oldID := old.add2Seq(&MockBackend{}, "old_worker", engine.SingleThread)
+		old.lastUsed.Store(now.Add(-time.Hour).UnixMilli())
+		recent := &WorkerID{memEstimateMB: 100}
+		recentID := recent.add2Seq(&MockBackend{}, "recent_worker", engine.SingleThread)
+		recent.lastUsed.Store(now.Add(-time.Minute).UnixMilli())
+
+		evictOnce(now, 150, 200)
+		mapMu.RLock()
+		_, oldExists := DSequences[oldID]
+		_, recentExists := DSequences[recentID]
+		mapMu.RUnlock()
+		assert.False(t, oldExists)
+		assert.True(t, recentExists)
+	})
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	now := time.Now()
+	leased := &WorkerID{}
+	leasedID := leased.add2Seq(&MockBackend{}, "leased_worker", engine.SingleThread)
+	leased.renewLease(now.Add(-time.Minute), 30*time.Second)
+	renewed := &WorkerID{}
+	renewedID := renewed.add2Seq(&MockBackend{}, "renewed_worker", engine.SingleThread)
+	renewed.renewLease(now.Add(-time.Minute), 30*time.Second)
+	plain := &WorkerID{}
+	plainID := plain.add2Seq(&MockBackend{}, "plain_worker", engine.SingleThread)
+
+	_, ok := plain.renewLease(now, 0)
+	assert.False(t, ok)
+	expires, ok := renewed.renewLease(now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(30*time.Second).UnixMilli(), expires.UnixMilli())
+
+	evictOnce(now, 0, 0)
+	mapMu.RLock()
+	_, leasedExists := DSequences[leasedID]
+	_, renewedExists := DSequences[renewedID]
+	_, plainExists := DSequences[plainID]
+	err := engineNotFound(leasedID)
+	mapMu.RUnlock()
+	assert.False(t, leasedExists)
+	assert.True(t, renewedExists)
+	assert.True(t, plainExists)
+	st, _ := status.FromError(err)
+	if assert.Len(t, st.Details(), 1) {
+		assert.Equal(t, reasonLease, st.Details()[0].(*errdetails.ErrorInfo).Reason)
+	}
+}
+
+// concurrencyBackend 记录同时进行的 Detect 调用数的峰值
+type concurrencyBackend struct {
+	MockBackend
+	active, peak atomic.Int32
+}
+
+func (c *concurrencyBackend) Detect(mat iface.ImageData) iface.RetData {
+	n := c.active.Add(1)
+	defer c.active.Add(-1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return iface.RetData{Success: true}
+}
+
+func TestLockedBackend(t *testing.T) {
+	inner := &concurrencyBackend{}
+	b := &lockedBackend{Backend: inner}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Detect(iface.ImageData{})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), inner.peak.Load())
+}
+
+func TestSharedInstance(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	spec := engineSpec{ModelPath: "models/shared.onnx", Names: []string{"mock"}, Confidence: 0.5, Iou: 0.4}
+	assert.Equal(t, spec.fingerprint(), engineSpec{ModelPath: "./models/../models/shared.onnx", Names: []string{"mock"}, Confidence: 0.5, Iou: 0.4}.fingerprint())
+	assert.NotEqual(t, spec.fingerprint(), engineSpec{ModelPath: "models/shared.onnx", Names: []string{"mock"}, Confidence: 0.6, Iou: 0.4}.fingerprint())
+
+	backend := &MockBackend{}
+	poolMu.Lock()
+	sharedPool[spec.fingerprint()] = &sharedInstance{fingerprint: spec.fingerprint(), backend: backend, memEstimateMB: 64, refs: 1}
+	poolMu.Unlock()
+	first, hit, err := attachShared(spec)
+	assert.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, 2, first.refCount())
+
+	a := &WorkerID{shared: first}
+	aID := a.add2Seq(first.backend, "shared_a", engine.SingleThread)
+	b := &WorkerID{shared: first}
+	bID := b.add2Seq(first.backend, "shared_b", engine.SingleThread)
+	assert.Equal(t, uint64(0), a.memoryEstimateMB())
+
+	assert.NoError(t, drainEngine(aID, time.Second, false, reasonDestroyed, "destroyed"))
+	assert.Equal(t, 0, backend.destroyed)
+	assert.Equal(t, uint64(64), b.memoryEstimateMB())
+	assert.NoError(t, drainEngine(bID, time.Second, false, reasonDestroyed, "destroyed"))
+	assert.Equal(t, 1, backend.destroyed)
+	poolMu.Lock()
+	assert.Empty(t, sharedPool)
+	poolMu.Unlock()
+
+	// 首次加载期间，相同指纹的请求等待加载结果，且不占用 poolMu
+	finish := func(inst *sharedInstance, b iface.Backend, err error) {
+		poolMu.Lock()
+		inst.backend, inst.err = b, err
+		if err != nil {
+			delete(sharedPool, inst.fingerprint)
+		}
+		close(inst.loading)
+		inst.loading = nil
+		poolMu.Unlock()
+	}
+	type attached struct {
+		inst *sharedInstance
+		hit  bool
+		err  error
+	}
+	for _, loadErr := range []error{nil, errors.New("load failed")} {
+		pending := &sharedInstance{fingerprint: spec.fingerprint(), refs: 1, loading: make(chan struct{})}
+		poolMu.Lock()
+		sharedPool[spec.fingerprint()] = pending
+		poolMu.Unlock()
+		done := make(chan attached, 1)
+		go func() {
+			inst, hit, err := attachShared(spec)
+			done <- attached{inst, hit, err}
+		}()
+		assert.Eventually(t, func() bool { return pending.refCount() == 2 }, time.Second, 5*time.Millisecond)
+		select {
+		case <-done:
+			t.Fatal("attachShared returned before the pending load finished")
+		default:
+		}
+		finish(pending, backend, loadErr)
+		got := <-done
+		if loadErr == nil {
+			assert.NoError(t, got.err)
+			assert.True(t, got.hit)
+			assert.Same(t, pending, got.inst)
+		} else {
+			assert.ErrorIs(t, got.err, loadErr)
+		}
+		poolMu.Lock()
+		sharedPool = make(map[string]*sharedInstance)
+		poolMu.Unlock()
+	}
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	req := &InitEngineRequest{ModelPath: "models/a.onnx", Names: []string{"a"}, Confidence: 0.5, IdempotencyKey: "k1"}
+	retry := &InitEngineRequest{ModelPath: "models/a.onnx", Names: []string{"a"}, Confidence: 0.5, IdempotencyKey: "k2"}
+	other := &InitEngineRequest{ModelPath: "models/b.onnx", Names: []string{"a"}, Confidence: 0.5, IdempotencyKey: "k1"}
+	assert.Equal(t, requestFingerprint(req), requestFingerprint(retry))
+	assert.NotEqual(t, requestFingerprint(req), requestFingerprint(other))
+	assert.Equal(t, "k1", req.IdempotencyKey)
+}
+
+func TestClaimIdempotencyKey(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	ctx := context.Background()
+	id, release, err := claimIdempotencyKey(ctx, "k1", "fp")
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	// 同一个键正在创建时其他键不受影响
+	_, releaseOther, err := claimIdempotencyKey(ctx, "k2", "fp")
+	require.NoError(t, err)
+	releaseOther("")
+
+	got := make(chan string, 1)
+	go func() {
+		id, _, _ := claimIdempotencyKey(ctx, "k1", "fp")
+		got <- id
+	}()
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	_, _, err = claimIdempotencyKey(timeout, "k1", "fp")
+	cancel()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	engineID := (&WorkerID{}).add2Seq(&MockBackend{}, "idem", engine.SingleThread)
+	release(engineID)
+	assert.Equal(t, engineID, <-got)
+	_, _, err = claimIdempotencyKey(ctx, "k1", "other")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// 创建失败时释放键，下一次请求重新创建
+	_, release, err = claimIdempotencyKey(ctx, "k3", "fp")
+	require.NoError(t, err)
+	release("")
+	id, release, err = claimIdempotencyKey(ctx, "k3", "fp")
+	require.NoError(t, err)
+	assert.Empty(t, id)
+	release("")
+	forgetIdempotencyKey("k1", engineID)
+}
+
+func TestRegistryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "engines.json")
+	EnablePersistence(path)
+	defer EnablePersistence("")
+	DSequences = make(map[string]*WorkerID)
+
+	w := &WorkerID{request: &InitEngineRequest{ModelPath: "models/a.onnx", Names: []string{"a"}, Description: "persisted"}}
+	id := w.add2Seq(&MockBackend{}, "persisted", engine.SingleThread)
+	saveRegistry()
+
+	var state registryState
+	data, err := os.ReadFile(path)
+	if assert.NoError(t, err) && assert.NoError(t, json.Unmarshal(data, &state)) && assert.Len(t, state.Engines, 1) {
+		assert.Equal(t, id, state.Engines[0].ID)
+		req := &InitEngineRequest{}
+		assert.NoError(t, protojson.Unmarshal(state.Engines[0].Request, req))
+		assert.Equal(t, "models/a.onnx", req.ModelPath)
+		assert.Equal(t, []string{"a"}, req.Names)
+	}
+
+	// 后端不一致的定义恢复失败，但仍保留在状态文件中，引用它的流水线同样保留
+	DSequences = make(map[string]*WorkerID)
+	aliases = make(map[string]*Alias)
+	defer func() { aliases = make(map[string]*Alias) }()
+	(&WorkerID{preloaded: true}).add2SeqWithID("warm", &MockBackend{}, "warm", engine.SingleThread)
+	state.Engines[0].Backend = "unknown"
+	state.Pipelines = []string{fmt.Sprintf("name: kept-pipeline\nstages:\n  - {name: det, type: detect, engine: %s}\n", id)}
+	// 与预加载引擎 ID 冲突的别名不恢复
+	for _, name := range []string{"warm", "stable"} {
+		raw, _ := protojson.Marshal(&Alias{Name: name, Targets: []*AliasTarget{{EngineId: "warm", Weight: 1}}})
+		state.Aliases = append(state.Aliases, raw)
+	}
+	data, _ = json.Marshal(state)
+	assert.NoError(t, os.WriteFile(path, data, 0o644))
+	assert.NoError(t, RestoreEngines())
+	assert.NotContains(t, aliases, "warm")
+	assert.Contains(t, aliases, "stable")
+	failures := restoreFailureList()
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, id, failures[0].Id)
+		assert.Equal(t, "persisted", failures[0].Description)
+		assert.Contains(t, failures[0].Error, "backend")
+	}
+	data, _ = os.ReadFile(path)
+	assert.Contains(t, string(data), id)
+	assert.Contains(t, string(data), "kept-pipeline")
+
+	assert.True(t, discardFailedRestore(id))
+	assert.Empty(t, restoreFailureList())
+	assert.True(t, discardFailedPipeline("kept-pipeline"))
+	saveRegistry()
+	data, _ = os.ReadFile(path)
+	assert.NotContains(t, string(data), id)
+	assert.NotContains(t, string(data), "kept-pipeline")
+}
+
+func TestPreloadEngines(t *testing.T) {
+	dir := t.TempDir()
+	model := filepath.Join(dir, "det.onnx")
+	namesFile := filepath.Join(dir, "det.names")
+	assert.NoError(t, os.WriteFile(model, []byte("onnx"), 0o644))
+	assert.NoError(t, os.WriteFile(namesFile, []byte("person\r\ncar\r\n"), 0o644))
+
+	req, err := PreloadEngine{ID: "det", ModelPath: model, NamesFile: namesFile, Confidence: 0.3, Iou: 0.5, InputSize: 640, InputBlob: "images", OutputBlob: "output0"}.request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"person", "car"}, req.Names[:2])
+		assert.Equal(t, int32(640), req.InputSize)
+		assert.Equal(t, "images", req.InputBlob)
+		assert.Equal(t, "output0", req.OutputBlob)
+	}
+
+	DSequences = make(map[string]*WorkerID)
+	preloadFailures = nil
+	bad := []PreloadEngine{
+		{ModelPath: model},
+		{ID: "both", ModelPath: model, Names: []string{"a"}, NamesFile: namesFile},
+		{ID: "missing", ModelPath: filepath.Join(dir, "missing.onnx")},
+		{ID: "conf", ModelPath: model, Confidence: 1.5},
+	}
+	err = PreloadEngines(bad, true)
+	assert.ErrorContains(t, err, "must have an id")
+	assert.Empty(t, preloadFailureList())
+
+	err = PreloadEngines(bad, false)
+	assert.Error(t, err)
+	failures := preloadFailureList()
+	if assert.Len(t, failures, 4) {
+		assert.Equal(t, "both", failures[1].Id)
+		assert.Contains(t, failures[1].Error, "cannot both be set")
+		assert.Contains(t, failures[2].Error, "model file")
+		assert.Contains(t, failures[3].Error, "confidence")
+	}
+	assert.Empty(t, DSequences)
+	preloadFailures = nil
+}
+
+func TestReloadEngine(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	oldBackend := &MockBackend{}
+	oldReq := &InitEngineRequest{ModelPath: "models/v1.onnx", Names: []string{"mock"}, Share: true, Description: "reload"}
+	worker := &WorkerID{request: oldReq}
+	id := worker.add2Seq(oldBackend, "reload", engine.SingleThread)
+
+	// 预先放入共享池，newWorker 直接复用而不加载原生实例
+	seed := func(modelPath string, b *MockBackend) {
+		req := protobuf.Clone(oldReq).(*InitEngineRequest)
+		req.ModelPath = modelPath
+		spec, err := resolveSpec(req)
+		require.NoError(t, err)
+		fp := spec.fingerprint()
+		poolMu.Lock()
+		sharedPool[fp] = &sharedInstance{fingerprint: fp, backend: b, refs: 0}
+		poolMu.Unlock()
+	}
+	warmup := &ImageData{Data: []byte{0, 0, 0}, Width: 1, Height: 1, Channels: 3}
+	reloadTo := func(modelPath string, timeout time.Duration) error {
+		req := protobuf.Clone(oldReq).(*InitEngineRequest)
+		req.ModelPath = modelPath
+		swapMu.Lock()
+		defer swapMu.Unlock()
+		_, err := swapEngine(id, req, warmup, timeout)
+		return err
+	}
+
+	broken := &MockBackend{failDetect: true}
+	seed("models/broken.onnx", broken)
+	assert.ErrorContains(t, reloadTo("models/broken.onnx", time.Second), "warmup failed")
+	assert.Equal(t, 1, broken.destroyed)
+	assert.Same(t, worker, DSequences[id])
+	assert.Equal(t, 0, oldBackend.destroyed)
+
+	newBackend := &MockBackend{}
+	seed("models/v2.onnx", newBackend)
+	assert.NoError(t, worker.acquire())
+	done := make(chan error)
+	go func() {
+		done <- reloadTo("models/v2.onnx", 2*time.Second)
+	}()
+	// 旧实例仍有在途任务时，新请求已经进入新实例
+	assert.Eventually(t, func() bool {
+		mapMu.RLock()
+		defer mapMu.RUnlock()
+		return DSequences[id] != worker
+	}, time.Second, 10*time.Millisecond)
+	next, err := acquireEngine(id)
+	if assert.NoError(t, err) {
+		assert.Same(t, newBackend, next.detector)
+		assert.Equal(t, "models/v2.onnx", next.request.ModelPath)
+		next.release()
+	}
+	assert.Equal(t, 0, oldBackend.destroyed)
+	worker.release()
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, oldBackend.destroyed)
+	assert.Equal(t, EngineState_ENGINE_STATE_DESTROYED, worker.getState())
+
+	assert.NoError(t, drainEngine(id, time.Second, false, reasonDestroyed, "destroyed"))
+	assert.Equal(t, 1, newBackend.destroyed)
+}
+
+func TestUpdateEngineInPlace(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	worker := &WorkerID{
+		native:   engineSpec{ModelPath: "mock", Names: []string{"mock", "other"}, Confidence: 0.5, Iou: 0.4, InputSize: 640},
+		revision: 1,
+	}
+	id := worker.add2Seq(&MockBackend{}, "update", engine.SingleThread)
+
+	_, ok := worker.inPlaceFilter(&InitEngineRequest{Names: []string{"mock", "other"}, Confidence: 0.3, Iou: 0.4, InputSize: 640})
+	assert.False(t, ok, "lowering confidence below the native threshold needs a re-init")
+	_, ok = worker.inPlaceFilter(&InitEngineRequest{Names: []string{"mock", "other"}, Confidence: 0.5, Iou: 0.6, InputSize: 640})
+	assert.False(t, ok, "changing IoU needs a re-init")
+	_, ok = worker.inPlaceFilter(&InitEngineRequest{Names: []string{"mock"}, Confidence: 0.5, Iou: 0.4, InputSize: 640})
+	assert.False(t, ok, "changing the class count needs a re-init")
+
+	f, ok := worker.inPlaceFilter(&InitEngineRequest{Names: []string{"cat", "other"}, Confidence: 0.995, Iou: 0.4, InputSize: 640})
+	if assert.True(t, ok) {
+		assert.Equal(t, map[string]string{"mock": "cat"}, f.rename)
+		worker.filter.Store(f)
+		worker.revision++
+	}
+	results := worker.filterResults((&MockBackend{}).Detect(iface.ImageData{}).Data.(map[string][]iface.Result))
+	assert.Empty(t, results)
+	f.minConf = 0.9
+	results = worker.filterResults((&MockBackend{}).Detect(iface.ImageData{}).Data.(map[string][]iface.Result))
+	assert.Len(t, results["cat"], 1)
+
+	info, err := engineInfo(id, worker)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(2), info.ConfigRevision)
+		assert.Equal(t, []string{"cat", "other"}, info.Names)
+		assert.InDelta(t, 0.9, info.Confidence, 0.0001)
+	}
+}
+
+func TestAliasRouting(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	aliases = make(map[string]*Alias)
+	defer func() { aliases = make(map[string]*Alias) }()
+	v3 := (&WorkerID{}).add2Seq(&MockBackend{}, "v3", engine.SingleThread)
+	v4 := (&WorkerID{}).add2Seq(&MockBackend{}, "v4", engine.SingleThread)
+
+	_, err := setAlias(&SetAliasRequest{Name: v3, Targets: []*AliasTarget{{EngineId: v4, Weight: 1}}}, true)
+	assert.ErrorContains(t, err, "conflicts with an engine ID")
+	_, err = setAlias(&SetAliasRequest{Name: "helmet", Targets: []*AliasTarget{{EngineId: "missing", Weight: 1}}}, true)
+	assert.Equal(t, codes.NotFound, status.Code(errors.Unwrap(err)))
+	_, err = setAlias(&SetAliasRequest{Name: "helmet", Targets: []*AliasTarget{{EngineId: v3}}}, true)
+	assert.ErrorContains(t, err, "positive weight")
+	_, err = setAlias(&SetAliasRequest{Name: "helmet", Targets: []*AliasTarget{{EngineId: v3, Weight: 1}}}, false)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := setAlias(&SetAliasRequest{Name: "helmet", Targets: []*AliasTarget{
+		{EngineId: v3, Version: "v3", Weight: 95},
+		{EngineId: v4, Version: "v4", Weight: 5},
+	}}, true)
+	if assert.NoError(t, err) {
+		assert.True(t, resp.Success)
+	}
+	_, err = setAlias(&SetAliasRequest{Name: "helmet", Targets: []*AliasTarget{{EngineId: v3, Weight: 1}}}, true)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	// 以固定 ID 注册（预加载、恢复）的引擎也不能与别名冲突
+	_, _, err = createEngine("helmet", &InitEngineRequest{ModelPath: "models/a.onnx", Names: []string{"mock"}})
+	assert.ErrorContains(t, err, "conflicts with an alias")
+	assert.ErrorContains(t, registerEngine("helmet", &WorkerID{detector: &MockBackend{}}), "conflicts with an alias")
+	assert.NotContains(t, DSequences, "helmet")
+
+	id, target, err := resolveEngine(v4)
+	assert.NoError(t, err)
+	assert.Equal(t, v4, id)
+	assert.Nil(t, target)
+
+	counts := map[string]int{}
+	for range 2000 {
+		id, target, err := resolveEngine("helmet")
+		if !assert.NoError(t, err) {
+			break
+		}
+		assert.Equal(t, target.EngineId, id)
+		counts[target.Version]++
+	}
+	assert.InDelta(t, 1900, counts["v3"], 100)
+	assert.Equal(t, 2000, counts["v3"]+counts["v4"])
+
+	// 目标引擎销毁后流量只流向存活的版本
+	assert.NoError(t, drainEngine(v3, time.Second, false, reasonDestroyed, "destroyed"))
+	for range 20 {
+		id, _, err := resolveEngine("helmet")
+		assert.NoError(t, err)
+		assert.Equal(t, v4, id)
+	}
+	assert.NoError(t, drainEngine(v4, time.Second, false, reasonDestroyed, "destroyed"))
+	_, _, err = resolveEngine("helmet")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Len(t, aliasList(), 1)
+}
+
+func TestShadowLimit(t *testing.T) {
+	defer setShadowLimit(1)
+	// 影子推理最多占用一半的 worker
+	setShadowLimit(8)
+	assert.Equal(t, 4, cap(shadowSlots))
+	setShadowLimit(1)
+	assert.Equal(t, 1, cap(shadowSlots))
+}
+
+func TestShadowAgreement(t *testing.T) {
+	box := func(x1, y1, x2, y2 float32, conf float32) iface.Result {
+		return iface.Result{Conf: conf, Box: iface.Box{LT: iface.Position{X: x1, Y: y1}, RB: iface.Position{X: x2, Y: y2}}}
+	}
+	c := &shadowConfig{engineID: "prod", shadowID: "cand", sampleRate: 1, iou: 0.5, classes: make(map[string]*classAgreement)}
+	prod := map[string][]iface.Result{
+		"person": {box(0, 0, 10, 10, 0.9), box(20, 20, 30, 30, 0.8)},
+		"car":    {box(50, 50, 60, 60, 0.7)},
+	}
+	shadow := map[string][]iface.Result{
+		"person": {box(1, 1, 10, 10, 0.7), box(100, 100, 110, 110, 0.6)},
+		"car":    {box(50, 50, 60, 60, 0.9)},
+	}
+	c.record(prod, shadow)
+	stats := c.stats()
+	assert.Equal(t, int64(1), stats.Compared)
+	if assert.Len(t, stats.Classes, 2) {
+		car, person := stats.Classes[0], stats.Classes[1]
+		assert.Equal(t, int64(1), car.Matched)
+		assert.InDelta(t, 0.2, car.MeanConfDelta, 1e-5)
+		assert.InDelta(t, 1.0, car.MeanIou, 1e-5)
+		assert.Equal(t, int64(1), person.Matched)
+		assert.Equal(t, int64(1), person.Extra)
+		assert.Equal(t, int64(1), person.Missing)
+		assert.InDelta(t, -0.2, person.MeanConfDelta, 1e-5)
+		assert.InDelta(t, 0.2, person.MeanAbsConfDelta, 1e-5)
+		assert.InDelta(t, 0.81, person.MeanIou, 1e-5)
+	}
+
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	prodWorker := &WorkerID{}
+	prodID := prodWorker.add2Seq(&MockBackend{}, "prod", engine.SingleThread)
+	candID := (&WorkerID{}).add2Seq(&MockBackend{}, "cand", engine.SingleThread)
+	c = &shadowConfig{engineID: prodID, shadowID: candID, sampleRate: 1, iou: 0.5, classes: make(map[string]*classAgreement)}
+	prodWorker.shadow.Store(c)
+	prodWorker.maybeShadow(iface.ImageData{Data: []byte{0}, Width: 1, Height: 1, Channels: 1}, map[string][]iface.Result{})
+	assert.Eventually(t, func() bool { return c.stats().Compared == 1 }, time.Second, 10*time.Millisecond)
+	stats = c.stats()
+	if assert.Len(t, stats.Classes, 1) {
+		assert.Equal(t, "mock", stats.Classes[0].Name)
+		assert.Equal(t, int64(1), stats.Classes[0].Extra)
+		assert.Zero(t, stats.Classes[0].Matched)
+	}
+}
+
+func TestEnsembleEngine(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	a := (&WorkerID{}).add2Seq(&MockBackend{}, "a", engine.SingleThread)
+	b := (&WorkerID{}).add2Seq(&MockBackend{}, "b", engine.SingleThread)
+
+	_, err := createEnsemble("", &CreateEnsembleRequest{Members: []*EnsembleMember{{EngineId: a}}})
+	assert.ErrorContains(t, err, "at least two members")
+	_, err = createEnsemble("", &CreateEnsembleRequest{Members: []*EnsembleMember{{EngineId: a}, {EngineId: b}}, Method: "vote"})
+	assert.ErrorContains(t, err, "unsupported fusion method")
+
+	ens, err := createEnsemble("", &CreateEnsembleRequest{
+		Members: []*EnsembleMember{
+			{EngineId: a, ClassMap: map[string]string{"mock": "helmet"}},
+			{EngineId: b, Weight: 1},
+		},
+		Description: "ensemble",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	image := iface.ImageData{Data: []byte{0}, Width: 1, Height: 1, Channels: 1}
+	result := submitJob(ens.detector, image)
+	if assert.True(t, result.Data.Success) {
+		fused := result.Data.Data.(map[string][]iface.Result)
+		assert.Len(t, fused["helmet"], 1)
+		assert.Len(t, fused["mock"], 1)
+		// 只被一个成员检出的框置信度按成员数缩放
+		assert.InDelta(t, 0.495, fused["mock"][0].Conf, 1e-5)
+	}
+
+	info, err := engineInfo(ens.id, ens)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{a, b}, info.Members)
+		assert.Equal(t, "wbf", info.FusionMethod)
+		assert.Equal(t, int32(engine.Ensemble), info.EngineType)
+		assert.ElementsMatch(t, []string{"helmet", "mock"}, info.Names)
+	}
+
+	// 成员被销毁后用剩余成员的结果融合，全部失败时返回失败
+	assert.NoError(t, drainEngine(b, time.Second, false, reasonDestroyed, "destroyed"))
+	result = submitJob(ens.detector, image)
+	if assert.True(t, result.Data.Success) {
+		fused := result.Data.Data.(map[string][]iface.Result)
+		assert.Len(t, fused["helmet"], 1)
+		assert.Empty(t, fused["mock"])
+	}
+	assert.NoError(t, drainEngine(a, time.Second, false, reasonDestroyed, "destroyed"))
+	result = submitJob(ens.detector, image)
+	assert.False(t, result.Data.Success)
+}
+
+func TestMultiInference(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	ok := (&WorkerID{}).add2Seq(&MockBackend{}, "ok", engine.SingleThread)
+	failing := (&WorkerID{}).add2Seq(&MockBackend{failDetect: true}, "failing", engine.SingleThread)
+	img := &ImageData{Data: []byte{0, 0, 0}, Width: 1, Height: 1, Channels: 3}
+
+	results := make([]*EngineResult, 0, 3)
+	for _, id := range []string{ok, failing, "missing"} {
+		results = append(results, inferOne(id, img))
+	}
+	assert.True(t, results[0].Success)
+	assert.Equal(t, ok, results[0].EngineId)
+	if assert.Len(t, results[0].Results, 1) {
+		assert.Equal(t, "mock", results[0].Results[0].Name)
+	}
+	assert.False(t, results[1].Success)
+	assert.NotEmpty(t, results[1].Error)
+	assert.False(t, results[2].Success)
+	assert.Contains(t, results[2].Error, "not found")
+}
+
+func TestPipelineRegistry(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	pipelines = make(map[string]*pipeline.Pipeline)
+	defer func() { pipelines = make(map[string]*pipeline.Pipeline) }()
+	id := (&WorkerID{}).add2Seq(&MockBackend{}, "mock", engine.SingleThread)
+
+	def := `name: mock-pipeline
+stages:
+  - {name: det, type: detect, engine: %s}
+  - {name: confident, type: filter, input: det, minConfidence: 0.5}
+`
+	_, err := registerPipeline([]byte(fmt.Sprintf(def, "missing")), false)
+	assert.ErrorContains(t, err, "unknown engine or alias missing")
+	p, err := registerPipeline([]byte(fmt.Sprintf(def, id)), false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = registerPipeline([]byte(fmt.Sprintf(def, id)), false)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Len(t, pipelineSources(), 1)
+
+	dets, err := p.Run(detectWith, iface.ImageData{Data: make([]byte, 4*4*3), Width: 4, Height: 4, Channels: 3})
+	if assert.NoError(t, err) {
+		out := toPipelineDetections(dets)
+		if assert.Len(t, out, 1) {
+			assert.Equal(t, "det", out[0].Stage)
+			assert.Equal(t, "mock", out[0].Name)
+			assert.Len(t, out[0].Box, 4)
+			assert.Empty(t, out[0].Children)
+		}
+	}
+}
+
+func TestModelRepository(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	oldDir := modelDir
+	modelDir = t.TempDir()
+	defer func() { modelDir = oldDir }()
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "repo.onnx"), []byte("onnx"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "tiny.param"), []byte("7767517\n1 1\nInput in0 0 1 in0\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "tiny.bin"), []byte("bin"), 0o644))
+
+	models, err := listModels()
+	if assert.NoError(t, err) && assert.Len(t, models, 3) {
+		assert.Equal(t, []string{"repo.onnx", "tiny.bin", "tiny.param"}, []string{models[0].Name, models[1].Name, models[2].Name})
+		assert.Equal(t, "ncnn-bin", models[1].Format)
+		assert.Empty(t, models[0].Sha256)
+	}
+	info, err := modelInfo("repo.onnx", true)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "87e93f89f2be0db364e8be052f79f389e6c2da239831922e24513288af522a43", info.Sha256)
+		assert.Equal(t, int64(4), info.Size)
+		assert.Equal(t, "onnx", info.Format)
+	}
+	// 上传中的临时文件等隐藏文件不能通过模型名访问
+	assert.NoError(t, os.WriteFile(partPath("other.onnx"), []byte("part"), 0o644))
+	for _, name := range []string{"", "..", "../go.mod", `sub\x.onnx`, ".upload-other.onnx.part", ".hidden"} {
+		_, err := modelInfo(name, true)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), name)
+		assert.Equal(t, codes.InvalidArgument, status.Code(deleteModel(name)), name)
+	}
+	assert.FileExists(t, partPath("other.onnx"))
+	assert.Equal(t, ".upload-other.onnx.part", resolveModelPath(".upload-other.onnx.part"))
+	_, err = modelInfo("missing.onnx", false)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// 仓库模型名解析为仓库内路径，其余按原始路径处理
+	assert.Equal(t, filepath.Join(modelDir, "repo.onnx"), resolveModelPath("repo.onnx"))
+	assert.Equal(t, "missing.onnx", resolveModelPath("missing.onnx"))
+	assert.Equal(t, "other/repo.onnx", resolveModelPath("other/repo.onnx"))
+
+	load := func(name string) string {
+		req := &InitEngineRequest{ModelPath: name, Names: []string{"mock"}, Share: true}
+		// 指纹取自补全 blob 名等之后的参数，与 createEngine 查找共享实例时一致
+		spec, err := resolveSpec(req)
+		require.NoError(t, err)
+		fp := spec.fingerprint()
+		poolMu.Lock()
+		sharedPool[fp] = &sharedInstance{fingerprint: fp, backend: &MockBackend{}, refs: 0}
+		poolMu.Unlock()
+		seqdet, reused, err := createEngine("", req)
+		require.NoError(t, err)
+		require.True(t, reused)
+		return seqdet.id
+	}
+	onnxID := load("repo.onnx")
+	ncnnID := load("tiny.param")
+	assert.Equal(t, filepath.Join(modelDir, "repo.onnx"), DSequences[onnxID].native.ModelPath)
+
+	err = deleteModel("repo.onnx")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.ErrorContains(t, err, onnxID)
+	// .bin 随同名 .param 一起被使用
+	assert.Equal(t, codes.FailedPrecondition, status.Code(deleteModel("tiny.bin")))
+
+	assert.NoError(t, drainEngine(onnxID, time.Second, false, reasonDestroyed, "destroyed"))
+	assert.NoError(t, deleteModel("repo.onnx"))
+	_, err = os.Stat(filepath.Join(modelDir, "repo.onnx"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoError(t, drainEngine(ncnnID, time.Second, false, reasonDestroyed, "destroyed"))
+	assert.NoError(t, deleteModel("tiny.bin"))
+}
+
+// fakeUpload 按顺序返回上传消息，消息耗尽后返回 err
+type fakeUpload struct {
+	reqs []*UploadFileRequest
+	err  error
+}
+
+func (f *fakeUpload) Recv() (*UploadFileRequest, error) {
+	if len(f.reqs) == 0 {
+		return nil, f.err
+	}
+	req := f.reqs[0]
+	f.reqs = f.reqs[1:]
+	return req, nil
+}
+
+func uploadStream(info *FileInfo, err error, chunks ...string) *fakeUpload {
+	f := &fakeUpload{err: err}
+	f.reqs = append(f.reqs, &UploadFileRequest{Data: &UploadFileRequest_FileInfo{FileInfo: info}})
+	for _, c := range chunks {
+		f.reqs = append(f.reqs, &UploadFileRequest{Data: &UploadFileRequest_ChunkData{ChunkData: []byte(c)}})
+	}
+	return f
+}
+
+func TestUploadModel(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	oldDir := modelDir
+	modelDir = t.TempDir()
+	defer func() { modelDir = oldDir }()
+	content := "0123456789"
+	sum := sha256.Sum256([]byte(content))
+	digest := hex.EncodeToString(sum[:])
+
+	for _, name := range []string{"../escape.onnx", "sub/x.onnx", ".hidden", ""} {
+		_, err := receiveModel(uploadStream(&FileInfo{Name: name}, io.EOF))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+	_, err := os.Stat(filepath.Join(filepath.Dir(modelDir), "escape.onnx"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// 传输中断后保留临时文件，最终路径不出现截断的模型
+	info := &FileInfo{Name: "m.onnx", Size: int64(len(content)), Sha256: digest}
+	_, err = receiveModel(uploadStream(info, errors.New("connection reset"), "0123", "45"))
+	assert.ErrorContains(t, err, "connection reset")
+	_, err = os.Stat(filepath.Join(modelDir, "m.onnx"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	offset, err := uploadOffset("m.onnx")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), offset)
+	models, _ := listModels()
+	assert.Empty(t, models)
+
+	// 客户端声称的进度超过服务端已有数据时拒绝续传
+	_, err = receiveModel(uploadStream(&FileInfo{Name: "m.onnx", Size: 10, Offset: 8}, io.EOF, "89"))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	resume := &FileInfo{Name: "m.onnx", Size: int64(len(content)), Sha256: digest, Offset: 4}
+	resp, err := receiveModel(uploadStream(resume, io.EOF, "456", "789"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, digest, resp.Sha256)
+		assert.Equal(t, int64(10), resp.Size)
+		data, _ := os.ReadFile(resp.FilePath)
+		assert.Equal(t, content, string(data))
+	}
+	offset, _ = uploadOffset("m.onnx")
+	assert.Equal(t, int64(0), offset)
+
+	_, err = receiveModel(uploadStream(&FileInfo{Name: "short.onnx", Size: 10}, io.EOF, "0123"))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = receiveModel(uploadStream(&FileInfo{Name: "long.onnx", Size: 2}, io.EOF, "0123"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	bad := &FileInfo{Name: "bad.onnx", Size: 10, Sha256: strings.Repeat("0", 64)}
+	_, err = receiveModel(uploadStream(bad, io.EOF, content))
+	assert.Equal(t, codes.DataLoss, status.Code(err))
+	offset, _ = uploadOffset("bad.onnx")
+	assert.Equal(t, int64(0), offset)
+	_, err = os.Stat(filepath.Join(modelDir, "bad.onnx"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDownloadModel(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	oldDir := modelDir
+	modelDir = t.TempDir()
+	defer func() { modelDir = oldDir }()
+	content := strings.Repeat("0123456789", 10)
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "d.onnx"), []byte(content), 0o644))
+	digest := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	download := func(req *DownloadModelRequest) (*FileInfo, []byte, int, *DownloadTrailer, error) {
+		var info *FileInfo
+		var data []byte
+		chunks := 0
+		trailer, err := sendModel(req, func(resp *DownloadModelResponse) error {
+			switch payload := resp.Data.(type) {
+			case *DownloadModelResponse_FileInfo:
+				info = payload.FileInfo
+			case *DownloadModelResponse_ChunkData:
+				data = append(data, payload.ChunkData...)
+				chunks++
+			}
+			return nil
+		})
+		return info, data, chunks, trailer, err
+	}
+
+	info, data, chunks, trailer, err := download(&DownloadModelRequest{Name: "d.onnx", ChunkSize: 30})
+	if assert.NoError(t, err) {
+		assert.Equal(t, &FileInfo{Name: "d.onnx", Size: 100, FileType: "onnx"}, info)
+		assert.Equal(t, content, string(data))
+		assert.Equal(t, 4, chunks)
+		assert.Equal(t, digest(content), trailer.Sha256)
+		assert.Equal(t, trailer.Sha256, trailer.RangeSha256)
+	}
+
+	// 续传：从 offset 下载到末尾，也可以只取一段
+	_, data, _, trailer, err = download(&DownloadModelRequest{Name: "d.onnx", Offset: 95})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "56789", string(data))
+		assert.Equal(t, digest(content), trailer.Sha256)
+		assert.Equal(t, digest("56789"), trailer.RangeSha256)
+	}
+	_, data, _, trailer, err = download(&DownloadModelRequest{Name: "d.onnx", Offset: 10, Length: 3})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "012", string(data))
+		assert.Equal(t, int64(3), trailer.RangeLength)
+	}
+	_, data, _, _, err = download(&DownloadModelRequest{Name: "d.onnx", Offset: 100})
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+
+	for _, req := range []*DownloadModelRequest{{Offset: 101}, {Offset: 90, Length: 11}, {Offset: -1}, {Length: -1}} {
+		req.Name = "d.onnx"
+		_, _, _, _, err := download(req)
+		assert.Equal(t, codes.OutOfRange, status.Code(err))
+	}
+	_, _, _, _, err = download(&DownloadModelRequest{Name: "../d.onnx"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, _, _, _, err = download(&DownloadModelRequest{Name: "missing.onnx"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestModelManifest(t *testing.T) {
+	DSequences = make(map[string]*WorkerID)
+	oldDir := modelDir
+	modelDir = t.TempDir()
+	defer func() { modelDir = oldDir }()
+
+	_, err := receiveModel(uploadStream(&FileInfo{Name: "bad.onnx", Manifest: "task: track"}, io.EOF, "x"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	manifest := "names: [cat, dog]\ninputSize: 320\ninputBlob: in0\noutputBlob: out0\nconfidence: 0.4\niou: 0.5\ntask: detect\npreprocess:\n  mean: [0, 0, 0]\n  norm: [0.0039, 0.0039, 0.0039]\n  swapRB: true\n"
+	_, err = receiveModel(uploadStream(&FileInfo{Name: "pets.param", Manifest: manifest}, io.EOF, "param"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(modelDir, "pets.manifest.yaml"))
+	assert.NoError(t, err)
+
+	info, err := modelInfo("pets.param", false)
+	if assert.NoError(t, err) && assert.NotNil(t, info.Manifest) {
+		assert.Equal(t, []string{"cat", "dog"}, info.Manifest.Names)
+		assert.Equal(t, "detect", info.Manifest.Task)
+		assert.True(t, info.Manifest.Preprocess.SwapRb)
+	}
+
+	// 请求中未填写的字段由清单补全，已填写的字段优先
+	spec, err := withManifest(specFromRequest(&InitEngineRequest{ModelPath: "pets.param", Confidence: 0.7}))
+	assert.NoError(t, err)
+	assert.Equal(t, engineSpec{
+		ModelPath:  filepath.Join(modelDir, "pets.param"),
+		Names:      []string{"cat", "dog"},
+		InputSize:  320,
+		InputBlob:  "in0",
+		OutputBlob: "out0",
+		Confidence: 0.7,
+		Iou:        0.5,
+	}, spec)
+	spec, err = withManifest(specFromRequest(&InitEngineRequest{ModelPath: "other.onnx", Names: []string{"a"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, engineSpec{ModelPath: "other.onnx", Names: []string{"a"}}, spec)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "broken.onnx"), []byte("onnx"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "broken.manifest.yaml"), []byte("confidence: 2"), 0o644))
+	_, _, err = newWorker(&InitEngineRequest{ModelPath: "broken.onnx"})
+	assert.ErrorContains(t, err, "confidence")
+	info, err = modelInfo("broken.onnx", false)
+	if assert.NoError(t, err) {
+		assert.Nil(t, info.Manifest)
+		assert.Contains(t, info.ManifestError, "confidence")
+	}
+
+	assert.NoError(t, deleteModel("pets.param"))
+	_, err = os.Stat(filepath.Join(modelDir, "pets.manifest.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// onnxModel 构造带 metadata_props 的最小 ONNX 模型，输入为 1x3xSxS
+func onnxModel(size int, metadata ...string) []byte {
+	return onnxModelWithOutput(size, nil, metadata...)
+}
+
+// onnxModelWithOutput 在 onnxModel 的基础上声明一个形状为 output 的输出，output 为空时不声明输出
+func onnxModelWithOutput(size int, output []int, metadata ...string) []byte {
+	field := func(b []byte, num protowire.Number, v []byte) []byte {
+		return protowire.AppendBytes(protowire.AppendTag(b, num, protowire.BytesType), v)
+	}
+	varint := func(b []byte, num protowire.Number, v int) []byte {
+		return protowire.AppendVarint(protowire.AppendTag(b, num, protowire.VarintType), uint64(v))
+	}
+	valueInfo := func(name string, dims []int) []byte {
+		var shape []byte
+		for _, d := range dims {
+			shape = field(shape, 1, varint(nil, 1, d))
+		}
+		tensor := field(varint(nil, 1, 1), 2, shape)
+		return field(field(nil, 1, []byte(name)), 2, field(nil, 1, tensor))
+	}
+	graph := field(nil, 11, valueInfo("images", []int{1, 3, size, size}))
+	if len(output) > 0 {
+		graph = field(graph, 12, valueInfo("output0", output))
+	}
+	model := field(varint(nil, 1, 8), 7, graph)
+	model = field(model, 8, varint(nil, 2, 17))
+	for i := 0; i+1 < len(metadata); i += 2 {
+		model = field(model, 14, field(field(nil, 1, []byte(metadata[i])), 2, []byte(metadata[i+1])))
+	}
+	return model
+}
+
+func TestInspectModel(t *testing.T) {
+	oldDir := modelDir
+	modelDir = t.TempDir()
+	defer func() { modelDir = oldDir }()
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "yolo.onnx"), onnxModel(320, "names", "{0: 'cat', 1: 'dog'}", "imgsz", "[640, 640]", "stride", "32"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "plain.onnx"), onnxModel(416), 0o644))
+
+	resp, err := inspectModel("yolo.onnx")
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(modelDir, "yolo.onnx"), resp.ModelPath)
+		assert.Equal(t, []string{"cat", "dog"}, resp.Names)
+		assert.Equal(t, int32(640), resp.InputSize)
+		assert.Equal(t, int32(32), resp.Stride)
+		assert.Equal(t, []*OpsetInfo{{Domain: "ai.onnx", Version: 17}}, resp.Opsets)
+		if assert.Len(t, resp.Inputs, 1) {
+			assert.Equal(t, []int64{1, 3, 320, 320}, resp.Inputs[0].Shape)
+		}
+	}
+	_, err = inspectModel("missing.onnx")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = inspectModel("model.bin")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// 请求、清单、ONNX 元数据依次补全
+	spec, err := withModelMetadata(specFromRequest(&InitEngineRequest{ModelPath: "yolo.onnx"}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cat", "dog"}, spec.Names)
+	assert.Equal(t, int32(640), spec.InputSize)
+	spec, err = withModelMetadata(specFromRequest(&InitEngineRequest{ModelPath: "yolo.onnx", Names: []string{"a", "b"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, spec.Names)
+	spec, err = withModelMetadata(specFromRequest(&InitEngineRequest{ModelPath: "plain.onnx"}))
+	assert.NoError(t, err)
+	assert.Nil(t, spec.Names)
+	assert.Equal(t, int32(416), spec.InputSize)
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "yolo.manifest.yaml"), []byte("inputSize: 512"), 0o644))
+	spec, err = withManifest(specFromRequest(&InitEngineRequest{ModelPath: "yolo.onnx"}))
+	assert.NoError(t, err)
+	spec, err = withModelMetadata(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(512), spec.InputSize)
+	assert.Equal(t, []string{"cat", "dog"}, spec.Names)
+
+	// 类别名数量与元数据或输出形状 [1, 4+nc, anchors] 推测的类别数不一致时拒绝加载
+	_, err = withModelMetadata(specFromRequest(&InitEngineRequest{ModelPath: "yolo.onnx", Names: []string{"a"}}))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "v8.onnx"), onnxModelWithOutput(640, []int{1, 6, 8400}), 0o644))
+	_, err = withModelMetadata(specFromRequest(&InitEngineRequest{ModelPath: "v8.onnx", Names: []string{"a", "b"}}))
+	assert.NoError(t, err)
+	_, err = withModelMetadata(specFromRequest(&InitEngineRequest{ModelPath: "v8.onnx", Names: []string{"a", "b", "c"}}))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestNcnnBlobCheck(t *testing.T) {
+	oldDir := modelDir
+	modelDir = t.TempDir()
+	defer func() { modelDir = oldDir }()
+	param := "7767517\n3 3\nInput in0 0 1 in0 0=320 1=320 2=3\nConvolution conv 1 1 in0 a 0=8\nSigmoid sig 1 1 a out0\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "det.param"), []byte(param), 0o644))
+	path := filepath.Join(modelDir, "det.param")
+
+	// 未指定时自动填写唯一的输入输出 blob
+	spec, err := checkBlobs(specFromRequest(&InitEngineRequest{ModelPath: "det.param"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "in0", spec.InputBlob)
+	assert.Equal(t, "out0", spec.OutputBlob)
+
+	_, err = checkBlobs(specFromRequest(&InitEngineRequest{ModelPath: "det.param", InputBlob: "images"}))
+	assert.ErrorContains(t, err, `input blob "images" not found`)
+	assert.ErrorContains(t, err, "likely input blobs: in0")
+	_, err = checkBlobs(specFromRequest(&InitEngineRequest{ModelPath: "det.param", InputBlob: "in0", OutputBlob: "output"}))
+	assert.ErrorContains(t, err, "likely output blobs: out0")
+	// 中间 blob 也可以作为输出
+	spec, err = checkBlobs(specFromRequest(&InitEngineRequest{ModelPath: "det.param", OutputBlob: "a"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", spec.OutputBlob)
+
+	_, _, err = newWorker(&InitEngineRequest{ModelPath: "det.param", OutputBlob: "missing"})
+	assert.ErrorContains(t, err, "not found in "+path)
+
+	resp, err := inspectModel("det.param")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"in0", "a", "out0"}, resp.Blobs)
+		assert.Len(t, resp.Layers, 3)
+		assert.Equal(t, []*TensorInfo{{Name: "in0", ElemType: "FLOAT", Shape: []int64{3, 320, 320}}}, resp.Inputs)
+		assert.Equal(t, "out0", resp.Outputs[0].Name)
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "bad.param"), []byte("7767517\n2 1\n"), 0o644))
+	_, err = inspectModel("bad.param")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestNamesFileAndCount(t *testing.T) {
+	oldDir := modelDir
+	modelDir = t.TempDir()
+	defer func() { modelDir = oldDir }()
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "yolo.onnx"), onnxModel(320, "names", "{0: 'cat', 1: 'dog'}"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "pets.yaml"), []byte("names:\n  1: dog\n  0: cat\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(modelDir, "three.json"), []byte(`{"0": "a", "1": "b", "2": "c"}`), 0o644))
+
+	assert.ErrorContains(t, validateInitRequest(&InitEngineRequest{ModelPath: "yolo.onnx", Names: []string{"a"}, NamesFile: "pets.yaml"}), "cannot both be set")
+
+	// 类别名文件可以是仓库中的文件名
+	seed := func(req *InitEngineRequest, names []string) {
+		spec := specFromRequest(req)
+		spec.Names = names
+		spec.InputSize = 320
+		fp := spec.fingerprint()
+		poolMu.Lock()
+		sharedPool[fp] = &sharedInstance{fingerprint: fp, backend: &MockBackend{}, refs: 0}
+		poolMu.Unlock()
+	}
+	req := &InitEngineRequest{ModelPath: "yolo.onnx", NamesFile: "pets.yaml", Share: true}
+	seed(req, []string{"cat", "dog"})
+	w, _, err := newWorker(req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"cat", "dog"}, w.native.Names)
+		// 就地更新比较解析后的类别名，类别名来自文件或模型元数据时同样可以不重新加载
+		f, ok := w.inPlaceFilter(&InitEngineRequest{ModelPath: "yolo.onnx", NamesFile: "pets.yaml"})
+		assert.True(t, ok)
+		assert.Nil(t, f)
+		f, ok = w.inPlaceFilter(&InitEngineRequest{ModelPath: "yolo.onnx", Confidence: 0.5})
+		if assert.True(t, ok) {
+			assert.Empty(t, f.rename)
+		}
+		w.free()
+	}
+
+	// 与模型元数据中的类别数不一致时加载失败
+	_, _, err = newWorker(&InitEngineRequest{ModelPath: "yolo.onnx", NamesFile: "three.json"})
+	assert.ErrorContains(t, err, "3 class names given but model metadata declares 2 classes")
+	_, _, err = newWorker(&InitEngineRequest{ModelPath: "yolo.onnx", NamesFile: "missing.names"})
+	assert.ErrorContains(t, err, "failed to read names file")
+
+	m := &onnx.Model{Outputs: []onnx.Tensor{{Shape: []onnx.Dim{{Value: 1}, {Value: 84}, {Value: 8400}}}}}
+	assert.Equal(t, []int{80, 79}, outputClassCounts(m))
+	m.Outputs[0].Shape = []onnx.Dim{{Value: 1}, {Value: 25200}, {Value: 85}}
+	assert.Equal(t, []int{81, 80}, outputClassCounts(m))
+	m.Outputs[0].Shape = []onnx.Dim{{Value: 1}, {Value: -1}, {Value: 85}}
+	assert.Nil(t, outputClassCounts(m))
+}
+
+// gateBackend 每次推理都等待 gate，用于模拟比摄像头慢的模型
+type gateBackend struct {
+	MockBackend
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func (g *gateBackend) Detect(mat iface.ImageData) iface.RetData {
+	g.started <- struct{}{}
+	<-g.gate
+	return g.MockBackend.Detect(mat)
+}
+
+// streamHarness 用通道模拟双向流
+type streamHarness struct {
+	frames chan *StreamInferenceRequest
+	out    chan *StreamInferenceResponse
+	done   chan error
+}
+
+func startStream(ctx context.Context) *streamHarness {
+	h := &streamHarness{
+		frames: make(chan *StreamInferenceRequest),
+		out:    make(chan *StreamInferenceResponse, 16),
+		done:   make(chan error, 1),
+	}
+	recv := func() (*StreamInferenceRequest, error) {
+		select {
+		case f, ok := <-h.frames:
+			if !ok {
+				return nil, io.EOF
+			}
+			return f, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	send := func(resp *StreamInferenceResponse) error {
+		h.out <- resp
+		return nil
+	}
+	go func() { h.done <- serveStream(ctx, recv, send) }()
+	return h
+}
+
+func TestStreamInferenceKeepLatest(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	backend := &gateBackend{started: make(chan struct{}, 4), gate: make(chan struct{})}
+	id := (&WorkerID{}).add2Seq(backend, "stream", engine.SingleThread)
+	img := &ImageData{Data: []byte{0, 0, 0}, Width: 1, Height: 1, Channels: 3}
+
+	h := startStream(context.Background())
+	now := time.Now().UnixMilli()
+	h.frames <- &StreamInferenceRequest{Id: id, KeepLatest: true, Seq: 1, CaptureUnixMs: now, ImgData: img}
+	<-backend.started
+	// 模型仍在处理第 1 帧，后续帧只保留最新的一帧
+	for seq := int64(2); seq <= 4; seq++ {
+		h.frames <- &StreamInferenceRequest{Seq: seq, CaptureUnixMs: now, ImgData: img}
+	}
+	for _, seq := range []int64{2, 3} {
+		resp := <-h.out
+		assert.Equal(t, seq, resp.Seq)
+		assert.True(t, resp.Dropped)
+		assert.Nil(t, resp.Result)
+	}
+	backend.gate <- struct{}{}
+	resp := <-h.out
+	assert.Equal(t, int64(1), resp.Seq)
+	assert.Equal(t, now, resp.CaptureUnixMs)
+	if assert.NotNil(t, resp.Result) {
+		assert.True(t, resp.Result.Success)
+		assert.Equal(t, id, resp.Result.EngineId)
+	}
+	<-backend.started
+	backend.gate <- struct{}{}
+	resp = <-h.out
+	assert.Equal(t, int64(4), resp.Seq)
+	assert.False(t, resp.Dropped)
+	close(h.frames)
+	assert.NoError(t, <-h.done)
+	assert.Empty(t, h.out)
+}
+
+func TestStreamInference(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	id := (&WorkerID{}).add2Seq(&MockBackend{}, "stream", engine.SingleThread)
+	img := &ImageData{Data: []byte{0, 0, 0}, Width: 1, Height: 1, Channels: 3}
+
+	// 不丢帧时所有帧都返回结果，过期的帧被丢弃
+	h := startStream(context.Background())
+	h.frames <- &StreamInferenceRequest{Id: id, MaxInFlight: 2, MaxFrameAgeMs: 1000, Seq: 1, ImgData: img}
+	h.frames <- &StreamInferenceRequest{Seq: 2, CaptureUnixMs: time.Now().Add(-time.Minute).UnixMilli(), ImgData: img}
+	h.frames <- &StreamInferenceRequest{Seq: 3, CaptureUnixMs: time.Now().UnixMilli(), ImgData: img}
+	h.frames <- &StreamInferenceRequest{Seq: 4}
+	close(h.frames)
+	assert.NoError(t, <-h.done)
+	close(h.out)
+	bySeq := make(map[int64]*StreamInferenceResponse)
+	for resp := range h.out {
+		bySeq[resp.Seq] = resp
+	}
+	if assert.Len(t, bySeq, 4) {
+		assert.True(t, bySeq[1].Result.Success)
+		assert.True(t, bySeq[2].Dropped)
+		assert.GreaterOrEqual(t, bySeq[2].LatencyMs, int64(60000))
+		assert.True(t, bySeq[3].Result.Success)
+		assert.Nil(t, bySeq[4].Result)
+		assert.Contains(t, bySeq[4].Message, "image data is invalid")
+	}
+
+	h = startStream(context.Background())
+	h.frames <- &StreamInferenceRequest{Seq: 1, ImgData: img}
+	assert.Equal(t, codes.InvalidArgument, status.Code(<-h.done))
+	h = startStream(context.Background())
+	h.frames <- &StreamInferenceRequest{Id: id, MaxInFlight: 100}
+	assert.Equal(t, codes.InvalidArgument, status.Code(<-h.done))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h = startStream(ctx)
+	h.frames <- &StreamInferenceRequest{Id: id, Seq: 1, ImgData: img}
+	cancel()
+	assert.ErrorIs(t, <-h.done, context.Canceled)
+}
+
+// movingBackend 返回一个随图像宽度水平移动的目标，单通道图像不返回检测
+type movingBackend struct {
+	MockBackend
+}
+
+func (m *movingBackend) Detect(mat iface.ImageData) iface.RetData {
+	res := map[string][]iface.Result{"person": {}}
+	if mat.Channels == 1 {
+		return iface.RetData{Success: true, Data: res}
+	}
+	x := float32(mat.Width * 5)
+	res["person"] = append(res["person"], iface.Result{
+		Conf: 0.9,
+		Box: iface.Box{
+			LT: iface.Position{X: x, Y: 10},
+			RT: iface.Position{X: x + 40, Y: 10},
+			RB: iface.Position{X: x + 40, Y: 90},
+			LB: iface.Position{X: x, Y: 90},
+		},
+		Center: iface.Position{X: x + 20, Y: 50},
+	})
+	return iface.RetData{Success: true, Data: res}
+}
+
+func TestTrackingSession(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	trackingSessions = make(map[string]*trackingSession)
+	id := (&WorkerID{}).add2Seq(&movingBackend{}, "tracking", engine.SingleThread)
+	frame := func(width, channels int32) *InferenceRequest {
+		return &InferenceRequest{Id: id, SessionId: "cam1", Tracker: &TrackerConfig{MaxLost: 1},
+			ImgData: &ImageData{Data: []byte{0, 0, 0}, Width: width, Height: 1, Channels: channels}}
+	}
+
+	for i := range int32(3) {
+		resp, err := runInference(frame(i+1, 3))
+		if !assert.NoError(t, err) || !assert.Len(t, resp.Results, 1) {
+			return
+		}
+		assert.Equal(t, int64(1), resp.Results[0].TrackId)
+		assert.Equal(t, i+1, resp.Results[0].TrackAge)
+		if i == 0 {
+			assert.Equal(t, []*TrackEvent{{Type: "new", TrackId: 1, Name: "person", Age: 1}}, resp.TrackEvents)
+		} else {
+			assert.Empty(t, resp.TrackEvents)
+		}
+	}
+	resp, err := runInference(frame(4, 1))
+	assert.NoError(t, err)
+	assert.Empty(t, resp.TrackEvents)
+	resp, _ = runInference(frame(5, 1))
+	assert.Equal(t, []*TrackEvent{{Type: "lost", TrackId: 1, Name: "person", Age: 5}}, resp.TrackEvents)
+
+	// 不带会话的请求不分配轨迹
+	resp, _ = runInference(&InferenceRequest{Id: id, ImgData: frame(1, 3).ImgData})
+	assert.Zero(t, resp.Results[0].TrackId)
+
+	_, err = runInference(&InferenceRequest{Id: id, SessionId: "cam2", Tracker: &TrackerConfig{Method: "deepsort"}, ImgData: frame(1, 3).ImgData})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// 已有会话可以省略配置，带了不同配置时拒绝
+	_, err = runInference(&InferenceRequest{Id: id, SessionId: "cam1", ImgData: frame(1, 3).ImgData})
+	assert.NoError(t, err)
+	_, err = runInference(&InferenceRequest{Id: id, SessionId: "cam1", Tracker: &TrackerConfig{MaxLost: 2}, ImgData: frame(1, 3).ImgData})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = runInference(&InferenceRequest{Id: id, SessionId: "cam1", Zones: &ZoneConfig{}, ImgData: frame(1, 3).ImgData})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// 空闲超过 TTL 的会话由清理协程删除
+	resp, _ = runInference(&InferenceRequest{Id: id, SessionId: "cam3", ImgData: frame(1, 3).ImgData})
+	assert.Equal(t, int64(1), resp.Results[0].TrackId)
+	trackingSessions["cam1"].lastUsed = time.Now().Add(-2 * time.Minute)
+	sweepTrackingSessions(time.Now(), time.Minute)
+	assert.NotContains(t, trackingSessions, "cam1")
+	assert.Contains(t, trackingSessions, "cam3")
+
+	// 流上的跟踪要求按顺序推理
+	h := startStream(context.Background())
+	h.frames <- &StreamInferenceRequest{Id: id, MaxInFlight: 2, Tracker: &TrackerConfig{}}
+	assert.Equal(t, codes.InvalidArgument, status.Code(<-h.done))
+
+	h = startStream(context.Background())
+	for i := range int64(3) {
+		h.frames <- &StreamInferenceRequest{Id: id, Tracker: &TrackerConfig{}, Seq: i, ImgData: frame(int32(i+1), 3).ImgData}
+	}
+	close(h.frames)
+	assert.NoError(t, <-h.done)
+	close(h.out)
+	for resp := range h.out {
+		if assert.NotNil(t, resp.Result) && assert.Len(t, resp.Result.Results, 1) {
+			assert.Equal(t, int64(1), resp.Result.Results[0].TrackId)
+			assert.Equal(t, int32(resp.Seq+1), resp.Result.Results[0].TrackAge)
+		}
+	}
+}
+
+func TestZones(t *testing.T) {
+	if JobQueue == nil {
+		JobQueue = make(chan JobPackage, 10)
+		StartWorker(1)
+	}
+	DSequences = make(map[string]*WorkerID)
+	trackingSessions = make(map[string]*trackingSession)
+	id := (&WorkerID{}).add2Seq(&movingBackend{}, "zones", engine.SingleThread)
+	// 目标中心 x = 宽度*5+20，y = 50；区域覆盖 x <= 60，向上的线 x = 50 从左到右穿越为 forward
+	cfg := &ZoneConfig{
+		Zones: []*ZoneRule{{Name: "door", Polygon: []*Position{{X: 0, Y: 0}, {X: 60, Y: 0}, {X: 60, Y: 100}, {X: 0, Y: 100}}}},
+		Lines: []*LineRule{{Name: "gate", From: &Position{X: 50, Y: 100}, To: &Position{X: 50, Y: 0}}},
+	}
+	rules, err := zoneConfig(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	DSequences[id].zoneRules.Store(rules)
+	img := func(width int32) *ImageData {
+		return &ImageData{Data: []byte{0, 0, 0}, Width: width, Height: 1, Channels: 3}
+	}
+
+	// 不带会话时只计数
+	resp, err := runInference(&InferenceRequest{Id: id, ImgData: img(1)})
+	if assert.NoError(t, err) && assert.Len(t, resp.ZoneCounts, 1) {
+		assert.Equal(t, &ZoneCount{Zone: "door", Count: 1, Classes: map[string]int32{"person": 1}}, resp.ZoneCounts[0])
+		assert.Empty(t, resp.ZoneEvents)
+	}
+
+	var events []*ZoneEvent
+	for _, w := range []int32{1, 4, 7, 10} {
+		resp, err = runInference(&InferenceRequest{Id: id, SessionId: "cam", ImgData: img(w)})
+		if !assert.NoError(t, err) {
+			return
+		}
+		events = append(events, resp.ZoneEvents...)
+	}
+	assert.Equal(t, int32(0), resp.ZoneCounts[0].Count)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, &ZoneEvent{Type: "enter", Zone: "door", TrackId: 1, Name: "person"}, events[0])
+		assert.Equal(t, &ZoneEvent{Type: "cross", Zone: "gate", TrackId: 1, Name: "person", Direction: "forward"}, events[1])
+		assert.Equal(t, "exit", events[2].Type)
+	}
+
+	_, err = runInference(&InferenceRequest{Id: id, Zones: cfg, ImgData: img(1)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = runInference(&InferenceRequest{Id: id, SessionId: "bad", Zones: &ZoneConfig{Lines: []*LineRule{{Name: "gate"}}}, ImgData: img(1)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// 流上的区域规则优先于引擎规则，停留时长按帧的采集时间计算
+	DSequences[id].zoneRules.Store(nil)
+	t0 := time.Now().Add(-time.Minute)
+	h := startStream(context.Background())
+	for i, w := range []int32{1, 4, 7, 10} {
+		h.frames <- &StreamInferenceRequest{Id: id, Zones: cfg, Seq: int64(i), CaptureUnixMs: t0.Add(time.Duration(i) * time.Second).UnixMilli(), ImgData: img(w)}
+	}
+	close(h.frames)
+	assert.NoError(t, <-h.done)
+	close(h.out)
+	events = nil
+	for resp := range h.out {
+		if assert.NotNil(t, resp.Result) {
+			events = append(events, resp.Result.ZoneEvents...)
+		}
+	}
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, &ZoneEvent{Type: "exit", Zone: "door", TrackId: 1, Name: "person", DwellMs: 3000}, events[2])
+	}
+}
+
+func TestWebhookFrame(t *testing.T) {
+	resp := &InferenceResponse{
+		EngineId: "e1",
+		Alias:    "cam",
+		Results:  []*SingleResult{{Name: "person"}, {Name: "person"}, {Name: "car"}},
+		ZoneCounts: []*ZoneCount{
+			{Zone: "door", Count: 2},
+		},
+		ZoneEvents: []*ZoneEvent{{Type: "exit", Zone: "door", TrackId: 3, Name: "person", DwellMs: 1200}},
+	}
+	f := webhookFrame(&InferenceRequest{SessionId: "s1"}, resp, "")
+	assert.Equal(t, webhook.Frame{
+		EngineID:   "e1",
+		Alias:      "cam",
+		SessionID:  "s1",
+		Counts:     map[string]int{"person": 2, "car": 1},
+		ZoneCounts: map[string]int{"door": 2},
+		ZoneEvents: []webhook.ZoneEvent{{Type: "exit", Zone: "door", TrackID: 3, Class: "person", DwellMs: 1200}},
+	}, f)
+}
@@ -0,0 +1,195 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"OnnxDetServer/ncnn"
+	"OnnxDetServer/onnx"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func isONNX(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".onnx")
+}
+
+func isNcnnParam(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".param")
+}
+
+func toTensorInfos(tensors []onnx.Tensor) []*TensorInfo {
+	infos := make([]*TensorInfo, len(tensors))
+	for i, t := range tensors {
+		info := &TensorInfo{Name: t.Name, ElemType: t.ElemType}
+		for _, d := range t.Shape {
+			info.Shape = append(info.Shape, d.Value)
+			info.DimParams = append(info.DimParams, d.Param)
+		}
+		infos[i] = info
+	}
+	return infos
+}
+
+// inspectModel 解析 ONNX 文件的 protobuf 或 ncnn 的 .param 文本，不加载原生实例
+func inspectModel(path string) (*InspectModelResponse, error) {
+	if path == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "model path cannot be empty")
+	}
+	path = resolveModelPath(path)
+	if isNcnnParam(path) {
+		p, err := ncnn.ParseFile(path)
+		if err != nil {
+			return nil, inspectError(path, err)
+		}
+		return inspectParam(path, p), nil
+	}
+	if !isONNX(path) {
+		return nil, status.Errorf(codes.InvalidArgument, "only ONNX and ncnn .param models can be inspected, got %s", path)
+	}
+	m, err := onnx.ReadFile(path)
+	if err != nil {
+		return nil, inspectError(path, err)
+	}
+	resp := &InspectModelResponse{
+		ModelPath:       path,
+		IrVersion:       m.IRVersion,
+		ProducerName:    m.ProducerName,
+		ProducerVersion: m.ProducerVersion,
+		Inputs:          toTensorInfos(m.Inputs),
+		Outputs:         toTensorInfos(m.Outputs),
+		Metadata:        m.Metadata,
+		InputSize:       int32(m.InputSize()),
+		Stride:          int32(m.Stride()),
+	}
+	for _, op := range m.Opsets {
+		resp.Opsets = append(resp.Opsets, &OpsetInfo{Domain: op.Domain, Version: op.Version})
+	}
+	if resp.Names, err = m.Names(); err != nil {
+		resp.NamesError = err.Error()
+	}
+	return resp, nil
+}
+
+func inspectError(path string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return status.Errorf(codes.NotFound, "model %s not found", path)
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func inspectParam(path string, p *ncnn.Param) *InspectModelResponse {
+	resp := &InspectModelResponse{ModelPath: path, Blobs: p.Blobs}
+	for _, l := range p.Layers {
+		resp.Layers = append(resp.Layers, &NcnnLayer{Type: l.Type, Name: l.Name, Bottoms: l.Bottoms, Tops: l.Tops})
+	}
+	for _, name := range p.Inputs() {
+		info := &TensorInfo{Name: name, ElemType: "FLOAT"}
+		if w, h, c := p.InputShape(name); w > 0 || h > 0 || c > 0 {
+			// ncnn 的 Mat 按 CHW 排列，未声明的维度为 -1
+			for _, d := range []int{c, h, w} {
+				info.Shape = append(info.Shape, int64(cmp.Or(d, -1)))
+			}
+		}
+		resp.Inputs = append(resp.Inputs, info)
+	}
+	for _, name := range p.Outputs() {
+		resp.Outputs = append(resp.Outputs, &TensorInfo{Name: name, ElemType: "FLOAT"})
+	}
+	return resp
+}
+
+// checkBlobs 加载 ncnn 模型前校验请求的 blob 名是否存在于 .param 中；未指定 blob 名且网络只有
+// 唯一的输入或输出时自动填写，否则在日志中给出候选
+func checkBlobs(spec engineSpec) (engineSpec, error) {
+	if !isNcnnParam(spec.ModelPath) {
+		return spec, nil
+	}
+	p, err := ncnn.ParseFile(spec.ModelPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		// 文件不存在交给原生加载报错
+		return spec, nil
+	}
+	if err != nil {
+		return spec, err
+	}
+	inputs, outputs := p.Inputs(), p.Outputs()
+	if spec.InputBlob != "" && !p.HasBlob(spec.InputBlob) {
+		return spec, fmt.Errorf("input blob %q not found in %s, likely input blobs: %s", spec.InputBlob, spec.ModelPath, strings.Join(inputs, ", "))
+	}
+	if spec.OutputBlob != "" && !p.HasBlob(spec.OutputBlob) {
+		return spec, fmt.Errorf("output blob %q not found in %s, likely output blobs: %s", spec.OutputBlob, spec.ModelPath, strings.Join(outputs, ", "))
+	}
+	if spec.InputBlob == "" && len(inputs) == 1 {
+		spec.InputBlob = inputs[0]
+	}
+	if spec.OutputBlob == "" && len(outputs) == 1 {
+		spec.OutputBlob = outputs[0]
+	}
+	if spec.InputBlob == "" || spec.OutputBlob == "" {
+		logger.Log().Warn("Cannot determine ncnn blob names, set inputBlob and outputBlob explicitly",
+			zap.String("ModelPath", spec.ModelPath), zap.Strings("inputs", inputs), zap.Strings("outputs", outputs))
+	}
+	if w, h, _ := p.InputShape(spec.InputBlob); spec.InputSize > 0 && max(w, h) > 0 && !slices.Contains([]int{w, h}, int(spec.InputSize)) {
+		logger.Log().Warn("Input size differs from the shape declared by the ncnn Input layer",
+			zap.String("ModelPath", spec.ModelPath), zap.Int32("inputSize", spec.InputSize), zap.Int("w", w), zap.Int("h", h))
+	}
+	return spec, nil
+}
+
+// outputClassCounts 按 YOLO 输出形状推测可能的类别数：[1, 4+nc, anchors]（v8）或 [1, anchors, 5+nc]（v5）
+func outputClassCounts(m *onnx.Model) []int {
+	if len(m.Outputs) != 1 || len(m.Outputs[0].Shape) != 3 {
+		return nil
+	}
+	a, b := m.Outputs[0].Shape[1].Value, m.Outputs[0].Shape[2].Value
+	if a <= 0 || b <= 0 {
+		return nil
+	}
+	ch := int(min(a, b))
+	return []int{ch - 4, ch - 5}
+}
+
+// withModelMetadata 用 ONNX metadata_props 补全请求和清单都未提供的类别名与输入尺寸，
+// 并检查类别名数量：与元数据中的类别数或输出形状推测的类别数不一致时返回 InvalidArgument。
+// 文件解析失败时只记录警告，交给原生加载判断模型是否可用
+func withModelMetadata(spec engineSpec) (engineSpec, error) {
+	if !isONNX(spec.ModelPath) {
+		return spec, nil
+	}
+	m, err := onnx.ReadFile(spec.ModelPath)
+	if err != nil {
+		logger.Log().Warn("Failed to read ONNX metadata", zap.String("ModelPath", spec.ModelPath), zap.Error(err))
+		return spec, nil
+	}
+	names, err := m.Names()
+	if err != nil {
+		logger.Log().Warn("Failed to parse class names from ONNX metadata", zap.String("ModelPath", spec.ModelPath), zap.Error(err))
+	}
+	if len(spec.Names) == 0 {
+		spec.Names = names
+	} else if len(names) > 0 && len(names) != len(spec.Names) {
+		return spec, status.Errorf(codes.InvalidArgument, "%d class names given but model metadata declares %d classes", len(spec.Names), len(names))
+	}
+	if counts := outputClassCounts(m); len(names) == 0 && len(spec.Names) > 0 && len(counts) > 0 && !slices.Contains(counts, len(spec.Names)) {
+		return spec, status.Errorf(codes.InvalidArgument, "%d class names given but the model output shape implies %d (YOLOv8) or %d (YOLOv5) classes", len(spec.Names), counts[0], counts[1])
+	}
+	if spec.InputSize == 0 {
+		spec.InputSize = int32(m.InputSize())
+	}
+	return spec, nil
+}
+
+func (s *Server) InspectModel(ctx context.Context, req *InspectModelRequest) (*InspectModelResponse, error) {
+	monitor.GRPCTotal.Inc()
+	return inspectModel(req.ModelPath)
+}
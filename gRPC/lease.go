@@ -0,0 +1,74 @@
+package proto
+
+import (
+	"OnnxDetServer/monitor"
+	"context"
+	"fmt"
+	"time"
+)
+
+// renewLease 把租约到期时间顺延一个租约时长，未持有租约的引擎返回 false
+func (d *WorkerID) renewLease(now time.Time, lease time.Duration) (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if lease > 0 {
+		d.lease = lease
+	}
+	if d.lease <= 0 {
+		return time.Time{}, false
+	}
+	expires := now.Add(d.lease)
+	d.leaseExpiry.Store(expires.UnixMilli())
+	return expires, true
+}
+
+func (d *WorkerID) leaseDuration() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lease
+}
+
+func (d *WorkerID) leaseExpired(now time.Time) bool {
+	expiry := d.leaseExpiry.Load()
+	return expiry > 0 && now.UnixMilli() > expiry
+}
+
+func (s *Server) RenewLease(ctx context.Context, req *RenewLeaseRequest) (*RenewLeaseResponse, error) {
+	monitor.GRPCTotal.Inc()
+	if req.LeaseSeconds < 0 {
+		return nil, fmt.Errorf("lease cannot be negative, got %d", req.LeaseSeconds)
+	}
+	now := time.Now()
+	lease := time.Duration(req.LeaseSeconds) * time.Second
+	statuses := make([]*LeaseStatus, 0, len(req.Ids))
+	renewed := 0
+	for _, id := range req.Ids {
+		mapMu.RLock()
+		detector, exists := DSequences[id]
+		var notFound error
+		if !exists {
+			notFound = engineNotFound(id)
+		}
+		mapMu.RUnlock()
+		if !exists {
+			statuses = append(statuses, &LeaseStatus{Id: id, Renewed: false, Message: notFound.Error()})
+			continue
+		}
+		if detector.getState() != EngineState_ENGINE_STATE_ACTIVE {
+			statuses = append(statuses, &LeaseStatus{Id: id, Renewed: false, Message: fmt.Sprintf("detector is %s", stateName(detector.getState()))})
+			continue
+		}
+		expires, ok := detector.renewLease(now, lease)
+		if !ok {
+			statuses = append(statuses, &LeaseStatus{Id: id, Renewed: false, Message: "detector has no lease"})
+			continue
+		}
+		renewed++
+		statuses = append(statuses, &LeaseStatus{Id: id, Renewed: true, ExpiresUnixMs: expires.UnixMilli()})
+	}
+	return &RenewLeaseResponse{
+		Success: renewed == len(req.Ids),
+		Leases:  statuses,
+		Message: fmt.Sprintf("Renewed %d of %d leases", renewed, len(req.Ids)),
+	}, nil
+}
@@ -0,0 +1,275 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/webhook"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDrainTimeout = 30 * time.Second
+	maxTombstones       = 1024
+)
+
+// 引擎被移除的原因，作为 NotFound 错误中 ErrorInfo.Reason 返回给客户端
+const (
+	errorDomain     = "onnxdetserver"
+	reasonDestroyed = "ENGINE_DESTROYED"
+	reasonEvicted   = "ENGINE_EVICTED"
+	reasonLease     = "ENGINE_LEASE_EXPIRED"
+	reasonShutdown  = "SERVER_SHUTDOWN"
+)
+
+// inflight 统计引用同一对象的在途任务数，并支持在关闭后等待其归零
+type inflight struct {
+	mu     sync.Mutex
+	n      int
+	closed bool
+	zero   chan struct{}
+}
+
+func (t *inflight) acquire() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	t.n++
+	return true
+}
+
+// release 返回在途任务是否已归零且不再接受新任务
+func (t *inflight) release() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n--
+	if t.n == 0 && t.zero != nil {
+		close(t.zero)
+		t.zero = nil
+	}
+	return t.n == 0 && t.closed
+}
+
+func (t *inflight) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.n
+}
+
+func (t *inflight) close() {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+}
+
+func (t *inflight) reopen() {
+	t.mu.Lock()
+	t.closed = false
+	t.mu.Unlock()
+}
+
+// wait 等待在途任务归零，超时返回 false
+func (t *inflight) wait(timeout time.Duration) bool {
+	t.mu.Lock()
+	if t.n == 0 {
+		t.mu.Unlock()
+		return true
+	}
+	if t.zero == nil {
+		t.zero = make(chan struct{})
+	}
+	zero := t.zero
+	t.mu.Unlock()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-zero:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+type tombstone struct {
+	reason string
+	detail string
+	at     time.Time
+}
+
+var (
+	tombstones     = make(map[string]tombstone)
+	tombstoneOrder []string
+)
+
+// addTombstone 记录已销毁的引擎 ID 及原因，调用方需持有 mapMu
+func addTombstone(id, reason, detail string) {
+	if _, ok := tombstones[id]; !ok {
+		tombstoneOrder = append(tombstoneOrder, id)
+	}
+	tombstones[id] = tombstone{reason: reason, detail: detail, at: time.Now()}
+	for len(tombstoneOrder) > maxTombstones {
+		delete(tombstones, tombstoneOrder[0])
+		tombstoneOrder = tombstoneOrder[1:]
+	}
+}
+
+// engineNotFound 返回 NotFound 错误，引擎曾被销毁或回收时在 ErrorInfo 中附带原因，
+// 调用方需持有 mapMu（读锁即可）
+func engineNotFound(id string) error {
+	t, ok := tombstones[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "detector with ID %s not found", id)
+	}
+	st := status.Newf(codes.NotFound, "detector with ID %s not found: %s at %s", id, t.detail, t.at.Format(time.RFC3339))
+	withInfo, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   t.reason,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"id": id},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
+
+// stateName 返回 "active"、"draining" 这样的简短状态名
+func stateName(s EngineState) string {
+	return strings.ToLower(strings.TrimPrefix(s.String(), "ENGINE_STATE_"))
+}
+
+func (d *WorkerID) getState() EngineState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
+func (d *WorkerID) acquire() error {
+	if !d.jobs.acquire() {
+		return fmt.Errorf("detector is %s", stateName(d.getState()))
+	}
+	d.touch()
+	return nil
+}
+
+func (d *WorkerID) release() {
+	d.touch()
+	if d.jobs.release() {
+		d.mu.Lock()
+		free := d.freeOnIdle
+		d.mu.Unlock()
+		if free {
+			d.free()
+		}
+	}
+}
+
+// free 释放原生实例（共享实例则释放一个引用），只执行一次
+func (d *WorkerID) free() {
+	d.freeOnce.Do(func() {
+		if d.shared != nil {
+			d.shared.detach()
+			return
+		}
+		d.detector.Destroy()
+	})
+}
+
+// acquireEngine 查找引擎并登记一个在途任务，调用方需在任务结束后调用 release
+func acquireEngine(id string) (*WorkerID, error) {
+	for {
+		mapMu.RLock()
+		detector, exists := DSequences[id]
+		if !exists {
+			err := engineNotFound(id)
+			mapMu.RUnlock()
+			return nil, err
+		}
+		mapMu.RUnlock()
+		err := detector.acquire()
+		if err == nil {
+			return detector, nil
+		}
+		// 查找与登记之间引擎被热替换时，改用替换后的实例
+		mapMu.RLock()
+		current := DSequences[id]
+		mapMu.RUnlock()
+		if current == nil || current == detector {
+			return nil, fmt.Errorf("detector with ID %s unavailable: %v", id, err)
+		}
+	}
+}
+
+// drainEngine 两阶段销毁引擎：先拒绝新请求，再等待在途任务结束后释放。
+// force 为 true 时不等待，引擎立即从注册表移除，原生实例在最后一个在途任务结束后释放。
+// 等待超时且未 force 时引擎恢复为 active 并返回错误。
+func drainEngine(id string, timeout time.Duration, force bool, reason, detail string) error {
+	mapMu.RLock()
+	detector, exists := DSequences[id]
+	if !exists {
+		err := engineNotFound(id)
+		mapMu.RUnlock()
+		return err
+	}
+	mapMu.RUnlock()
+
+	detector.mu.Lock()
+	if detector.state != EngineState_ENGINE_STATE_ACTIVE {
+		state := detector.state
+		detector.mu.Unlock()
+		return fmt.Errorf("detector with ID %s is already %s", id, stateName(state))
+	}
+	detector.state = EngineState_ENGINE_STATE_DRAINING
+	detector.mu.Unlock()
+	detector.jobs.close()
+
+	if !force {
+		if timeout <= 0 {
+			timeout = defaultDrainTimeout
+		}
+		if !detector.jobs.wait(timeout) {
+			pending := detector.jobs.count()
+			detector.mu.Lock()
+			detector.state = EngineState_ENGINE_STATE_ACTIVE
+			detector.mu.Unlock()
+			detector.jobs.reopen()
+			logger.Log().Warn("Timed out draining engine", zap.String("ID", id), zap.Int("inFlight", pending))
+			return fmt.Errorf("timed out waiting for %d in-flight jobs on detector %s, retry with force", pending, id)
+		}
+	}
+
+	// 等待期间同一 ID 可能已被重载换成新引擎，此时只释放旧实例，不能移除新引擎
+	mapMu.Lock()
+	current := DSequences[id] == detector
+	if current {
+		delete(DSequences, id)
+		addTombstone(id, reason, detail)
+	}
+	mapMu.Unlock()
+	if current {
+		forgetIdempotencyKey(detector.idempotencyKey, id)
+		webhook.ForgetEngine(id)
+		// 关闭服务时保留状态文件，重启后恢复这些引擎
+		if reason != reasonShutdown {
+			saveRegistry()
+		}
+	}
+
+	detector.mu.Lock()
+	detector.state = EngineState_ENGINE_STATE_DESTROYED
+	detector.freeOnIdle = true
+	detector.mu.Unlock()
+	if detector.jobs.count() == 0 {
+		detector.free()
+	} else {
+		logger.Log().Warn("Engine removed with in-flight jobs, native instance freed when they finish", zap.String("ID", id), zap.Int("inFlight", detector.jobs.count()))
+	}
+	return nil
+}
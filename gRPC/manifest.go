@@ -0,0 +1,122 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const manifestSuffix = ".manifest.yaml"
+
+// 清单中可用的任务类型
+var manifestTasks = []string{"detect", "classify", "segment", "pose", "obb"}
+
+// manifestFile 是模型旁的 YAML 清单，InitEngine 用它补全请求中未填写的参数
+type manifestFile struct {
+	Names      []string `yaml:"names"`
+	InputSize  int32    `yaml:"inputSize"`
+	InputBlob  string   `yaml:"inputBlob"`
+	OutputBlob string   `yaml:"outputBlob"`
+	Confidence float32  `yaml:"confidence"`
+	Iou        float32  `yaml:"iou"`
+	Task       string   `yaml:"task"`
+	Preprocess struct {
+		Mean      []float32 `yaml:"mean"`
+		Norm      []float32 `yaml:"norm"`
+		SwapRB    bool      `yaml:"swapRB"`
+		Letterbox bool      `yaml:"letterbox"`
+	} `yaml:"preprocess"`
+}
+
+// manifestPath 返回模型对应的清单路径，如 models/yolov8s.onnx 对应 models/yolov8s.manifest.yaml
+func manifestPath(modelPath string) string {
+	return strings.TrimSuffix(modelPath, filepath.Ext(modelPath)) + manifestSuffix
+}
+
+func parseManifest(data []byte) (*manifestFile, error) {
+	var m manifestFile
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.Confidence < 0 || m.Confidence > 1 {
+		return nil, fmt.Errorf("manifest confidence must be between 0.0 and 1.0, got %f", m.Confidence)
+	}
+	if m.Iou < 0 || m.Iou > 1 {
+		return nil, fmt.Errorf("manifest IoU must be between 0.0 and 1.0, got %f", m.Iou)
+	}
+	if m.InputSize < 0 {
+		return nil, fmt.Errorf("manifest input size cannot be negative, got %d", m.InputSize)
+	}
+	if m.Task != "" && !slices.Contains(manifestTasks, m.Task) {
+		return nil, fmt.Errorf("unknown manifest task %q, use one of %s", m.Task, strings.Join(manifestTasks, ", "))
+	}
+	return &m, nil
+}
+
+// loadManifest 读取模型旁的清单，没有清单时返回 nil
+func loadManifest(modelPath string) (*manifestFile, error) {
+	path := manifestPath(modelPath)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// withManifest 用模型清单补全未填写的参数，请求中已填写的值优先
+func withManifest(spec engineSpec) (engineSpec, error) {
+	m, err := loadManifest(spec.ModelPath)
+	if err != nil || m == nil {
+		return spec, err
+	}
+	if len(spec.Names) == 0 {
+		spec.Names = slices.Clone(m.Names)
+	}
+	if spec.InputSize == 0 {
+		spec.InputSize = m.InputSize
+	}
+	if spec.InputBlob == "" {
+		spec.InputBlob = m.InputBlob
+	}
+	if spec.OutputBlob == "" {
+		spec.OutputBlob = m.OutputBlob
+	}
+	if spec.Confidence == 0 {
+		spec.Confidence = m.Confidence
+	}
+	if spec.Iou == 0 {
+		spec.Iou = m.Iou
+	}
+	return spec, nil
+}
+
+func (m *manifestFile) proto() *ModelManifest {
+	return &ModelManifest{
+		Names:      m.Names,
+		InputSize:  m.InputSize,
+		InputBlob:  m.InputBlob,
+		OutputBlob: m.OutputBlob,
+		Confidence: m.Confidence,
+		Iou:        m.Iou,
+		Task:       m.Task,
+		Preprocess: &Preprocess{
+			Mean:      m.Preprocess.Mean,
+			Norm:      m.Preprocess.Norm,
+			SwapRb:    m.Preprocess.SwapRB,
+			Letterbox: m.Preprocess.Letterbox,
+		},
+	}
+}
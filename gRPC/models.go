@@ -0,0 +1,239 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// modelDir 是模型仓库目录，UploadModel 上传的文件保存在这里
+var modelDir = "models"
+
+// hashEntry 缓存文件的 SHA-256，文件大小或修改时间变化后失效
+type hashEntry struct {
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+var (
+	hashCache = make(map[string]hashEntry)
+	hashMu    sync.Mutex
+)
+
+// checkModelName 校验仓库模型名只是一个文件名，不能包含路径，也不能以 . 开头（上传中的临时文件等隐藏文件）
+func checkModelName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\:`) {
+		return status.Errorf(codes.InvalidArgument, "invalid model name %q", name)
+	}
+	return nil
+}
+
+// resolveModelPath 把仓库中存在的模型名解析为仓库内路径，其余按原始路径处理
+func resolveModelPath(path string) string {
+	if checkModelName(path) != nil {
+		return path
+	}
+	repoPath := filepath.Join(modelDir, path)
+	if info, err := os.Stat(repoPath); err == nil && info.Mode().IsRegular() {
+		return repoPath
+	}
+	return path
+}
+
+// modelFormat 按扩展名判断模型文件格式
+func modelFormat(name string) string {
+	if strings.HasSuffix(strings.ToLower(name), manifestSuffix) {
+		return "manifest"
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".onnx":
+		return "onnx"
+	case ".param":
+		return "ncnn-param"
+	case ".bin":
+		return "ncnn-bin"
+	case ".names", ".txt":
+		return "names"
+	case ".yaml", ".yml", ".json":
+		return "config"
+	}
+	return "unknown"
+}
+
+// modelEngines 返回正在使用该模型文件的引擎 ID；ncnn 的 .bin 随同名 .param 一起被使用
+func modelEngines(path string) []string {
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	if strings.EqualFold(filepath.Ext(target), ".bin") {
+		target = strings.TrimSuffix(target, filepath.Ext(target)) + ".param"
+	}
+	ids := make([]string, 0)
+	mapMu.RLock()
+	for id, d := range DSequences {
+		if d.native.ModelPath == "" {
+			continue
+		}
+		if p, err := filepath.Abs(d.native.ModelPath); err == nil && p == target {
+			ids = append(ids, id)
+		}
+	}
+	mapMu.RUnlock()
+	slices.Sort(ids)
+	return ids
+}
+
+// fileSHA256 计算文件的 SHA-256，按文件大小和修改时间缓存结果
+func fileSHA256(path string, info fs.FileInfo) (string, error) {
+	hashMu.Lock()
+	e, ok := hashCache[path]
+	hashMu.Unlock()
+	if ok && e.size == info.Size() && e.modTime.Equal(info.ModTime()) {
+		return e.sum, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	hashMu.Lock()
+	hashCache[path] = hashEntry{size: info.Size(), modTime: info.ModTime(), sum: sum}
+	hashMu.Unlock()
+	return sum, nil
+}
+
+// modelInfo 返回仓库中一个模型文件的信息，withHash 为 false 时不计算 SHA-256
+func modelInfo(name string, withHash bool) (*ModelInfo, error) {
+	if err := checkModelName(name); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(modelDir, name)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return nil, status.Errorf(codes.NotFound, "model %s not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &ModelInfo{
+		Name:           name,
+		Path:           path,
+		Size:           info.Size(),
+		UploadedUnixMs: info.ModTime().UnixMilli(),
+		Format:         modelFormat(name),
+		EngineIds:      modelEngines(path),
+	}
+	if !strings.HasSuffix(strings.ToLower(name), manifestSuffix) {
+		if manifest, err := loadManifest(path); err != nil {
+			m.ManifestError = err.Error()
+		} else if manifest != nil {
+			m.Manifest = manifest.proto()
+		}
+	}
+	if withHash {
+		if m.Sha256, err = fileSHA256(path, info); err != nil {
+			return nil, fmt.Errorf("failed to hash model %s: %w", name, err)
+		}
+	}
+	return m, nil
+}
+
+func listModels() ([]*ModelInfo, error) {
+	entries, err := os.ReadDir(modelDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make([]*ModelInfo, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	models := make([]*ModelInfo, 0, len(entries))
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		m, err := modelInfo(e.Name(), false)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, m)
+	}
+	slices.SortFunc(models, func(a, b *ModelInfo) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return models, nil
+}
+
+// deleteModel 删除仓库中的模型文件及其清单，仍有引擎使用时拒绝删除
+func deleteModel(name string) error {
+	m, err := modelInfo(name, false)
+	if err != nil {
+		return err
+	}
+	if len(m.EngineIds) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "model %s is in use by engines %s", name, strings.Join(m.EngineIds, ", "))
+	}
+	if err := os.Remove(m.Path); err != nil {
+		return err
+	}
+	// 模型的清单随模型一起删除
+	if m.Format != "manifest" {
+		if err := os.Remove(manifestPath(m.Path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Log().Warn("Failed to delete model manifest", zap.String("name", name), zap.Error(err))
+		}
+	}
+	hashMu.Lock()
+	delete(hashCache, m.Path)
+	hashMu.Unlock()
+	return nil
+}
+
+func (s *Server) ListModels(ctx context.Context, req *emptypb.Empty) (*ListModelsResponse, error) {
+	monitor.GRPCTotal.Inc()
+	models, err := listModels()
+	if err != nil {
+		return nil, err
+	}
+	return &ListModelsResponse{Models: models}, nil
+}
+
+func (s *Server) GetModelInfo(ctx context.Context, req *ModelRequest) (*ModelInfo, error) {
+	monitor.GRPCTotal.Inc()
+	return modelInfo(req.Name, true)
+}
+
+func (s *Server) DeleteModel(ctx context.Context, req *ModelRequest) (*DeleteModelResponse, error) {
+	monitor.GRPCTotal.Inc()
+	if err := deleteModel(req.Name); err != nil {
+		return nil, err
+	}
+	logger.Log().Info("Deleted model", zap.String("name", req.Name))
+	return &DeleteModelResponse{Success: true, Message: "Successfully deleted model"}, nil
+}
@@ -0,0 +1,56 @@
+package proto
+
+import (
+	"OnnxDetServer/monitor"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MultiInference 对同一张图片并发调用多个引擎，图片只需传输一次，每个引擎的结果与错误分别返回
+func (s *Server) MultiInference(ctx context.Context, req *MultiInferenceRequest) (*MultiInferenceResponse, error) {
+	monitor.GRPCTotal.Inc()
+	if len(req.Ids) == 0 {
+		return nil, fmt.Errorf("at least one engine ID is required")
+	}
+	if req.ImgData == nil || req.ImgData.Data == nil || len(req.ImgData.Data) == 0 || req.ImgData.Width == 0 || req.ImgData.Height == 0 || req.ImgData.Channels == 0 {
+		return nil, fmt.Errorf("image data is invalid")
+	}
+	results := make([]*EngineResult, len(req.Ids))
+	var wg sync.WaitGroup
+	for i, id := range req.Ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = inferOne(id, req.ImgData)
+		}()
+	}
+	wg.Wait()
+	success := true
+	for _, r := range results {
+		success = success && r.Success
+	}
+	return &MultiInferenceResponse{
+		Success: success,
+		Results: results,
+	}, nil
+}
+
+func inferOne(id string, img *ImageData) *EngineResult {
+	resp, err := runInference(&InferenceRequest{Id: id, ImgData: img})
+	if err != nil {
+		return &EngineResult{Id: id, Success: false, Error: err.Error()}
+	}
+	result := &EngineResult{
+		Id:       id,
+		Success:  resp.Success,
+		Results:  resp.Results,
+		EngineId: resp.EngineId,
+		Alias:    resp.Alias,
+		Version:  resp.Version,
+	}
+	if !resp.Success {
+		result.Error = "detector returned failure"
+	}
+	return result
+}
@@ -0,0 +1,306 @@
+package proto
+
+import (
+	"OnnxDetServer/engine"
+	"OnnxDetServer/logger"
+	"OnnxDetServer/pipeline"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const registryStateVersion = 1
+
+// persistedEngine 是状态文件中的一条引擎定义，Request 为 protojson 编码的 InitEngineRequest，
+// 集成引擎则为 protojson 编码的 CreateEnsembleRequest
+type persistedEngine struct {
+	ID       string          `json:"id"`
+	Backend  string          `json:"backend"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Ensemble json.RawMessage `json:"ensemble,omitempty"`
+}
+
+type registryState struct {
+	Version int               `json:"version"`
+	Engines []persistedEngine `json:"engines"`
+	// Aliases 为 protojson 编码的别名定义
+	Aliases []json.RawMessage `json:"aliases,omitempty"`
+	// Pipelines 为流水线的 YAML 定义
+	Pipelines []string `json:"pipelines,omitempty"`
+}
+
+var (
+	statePath string
+	persistMu sync.Mutex
+	// restoreFailures 记录恢复失败的引擎定义，它们会继续保留在状态文件中，直到被 DestroyEngine 丢弃
+	restoreFailures = make(map[string]*RestoreFailure)
+	failedRecords   = make(map[string]persistedEngine)
+	// failedPipelines 记录恢复失败的流水线定义，以名称为键（无法解析时以原文为键），
+	// 原样写回状态文件，直到同名流水线重新注册或被删除
+	failedPipelines = make(map[string]string)
+)
+
+// EnablePersistence 开启引擎注册表持久化，path 为空时不持久化
+func EnablePersistence(path string) {
+	persistMu.Lock()
+	defer persistMu.Unlock()
+	statePath = path
+}
+
+// writeFileAtomic 先写入同目录下的临时文件并落盘，再重命名覆盖目标文件
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// saveRegistry 把当前引擎定义和恢复失败的定义写入状态文件
+func saveRegistry() {
+	persistMu.Lock()
+	defer persistMu.Unlock()
+	if statePath == "" {
+		return
+	}
+	state := registryState{Version: registryStateVersion, Engines: []persistedEngine{}}
+	mapMu.RLock()
+	for id, d := range DSequences {
+		if e, ok := d.detector.(*ensembleBackend); ok {
+			req, err := protojson.Marshal(e.req)
+			if err != nil {
+				logger.Log().Error("Failed to encode ensemble definition", zap.String("ID", id), zap.Error(err))
+				continue
+			}
+			state.Engines = append(state.Engines, persistedEngine{ID: id, Backend: engine.BackendName(), Ensemble: req})
+			continue
+		}
+		cfg := d.config()
+		if cfg == nil || d.preloaded {
+			continue
+		}
+		req, err := protojson.Marshal(cfg)
+		if err != nil {
+			logger.Log().Error("Failed to encode engine definition", zap.String("ID", id), zap.Error(err))
+			continue
+		}
+		state.Engines = append(state.Engines, persistedEngine{ID: id, Backend: engine.BackendName(), Request: req})
+	}
+	mapMu.RUnlock()
+	for _, rec := range failedRecords {
+		state.Engines = append(state.Engines, rec)
+	}
+	slices.SortFunc(state.Engines, func(a, b persistedEngine) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for _, a := range aliasList() {
+		b, err := protojson.Marshal(a)
+		if err != nil {
+			logger.Log().Error("Failed to encode alias", zap.String("alias", a.Name), zap.Error(err))
+			continue
+		}
+		state.Aliases = append(state.Aliases, b)
+	}
+	for _, src := range pipelineSources() {
+		state.Pipelines = append(state.Pipelines, string(src))
+	}
+	for _, key := range slices.Sorted(maps.Keys(failedPipelines)) {
+		state.Pipelines = append(state.Pipelines, failedPipelines[key])
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		logger.Log().Error("Failed to encode engine registry", zap.Error(err))
+		return
+	}
+	if err := writeFileAtomic(statePath, data); err != nil {
+		logger.Log().Error("Failed to save engine registry", zap.String("path", statePath), zap.Error(err))
+	}
+}
+
+// RestoreEngines 从状态文件以原 ID 重新创建引擎，失败的定义通过 CheckAllEngine 报告
+func RestoreEngines() error {
+	persistMu.Lock()
+	path := statePath
+	persistMu.Unlock()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read engine registry: %w", err)
+	}
+	var state registryState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse engine registry %s: %w", path, err)
+	}
+	if state.Version != registryStateVersion {
+		return fmt.Errorf("unsupported engine registry version %d", state.Version)
+	}
+	// 先恢复普通引擎，再逐轮恢复集成引擎，直到没有新的集成引擎可以恢复（成员可能也是集成引擎）
+	var pending []persistedEngine
+	errs := make(map[string]error)
+	restored := 0
+	for _, rec := range state.Engines {
+		if len(rec.Ensemble) > 0 {
+			pending = append(pending, rec)
+			continue
+		}
+		if errs[rec.ID] = restoreRecord(rec); errs[rec.ID] == nil {
+			restored++
+		}
+	}
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+		remaining := pending[:0]
+		for _, rec := range pending {
+			if errs[rec.ID] = restoreRecord(rec); errs[rec.ID] == nil {
+				restored++
+				progress = true
+			} else {
+				remaining = append(remaining, rec)
+			}
+		}
+		pending = remaining
+	}
+	for _, rec := range state.Engines {
+		err := errs[rec.ID]
+		if err == nil {
+			continue
+		}
+		logger.Log().Error("Failed to restore engine", zap.String("ID", rec.ID), zap.Error(err))
+		failure := &RestoreFailure{Id: rec.ID, Error: err.Error()}
+		req := &InitEngineRequest{}
+		if protojson.Unmarshal(rec.Request, req) == nil {
+			failure.Description, failure.ModelPath = req.Description, req.ModelPath
+		}
+		persistMu.Lock()
+		failedRecords[rec.ID] = rec
+		restoreFailures[rec.ID] = failure
+		persistMu.Unlock()
+	}
+	// 别名原样恢复，目标引擎恢复失败时路由会跳过它；与引擎 ID（如预加载的引擎）冲突的别名不恢复
+	mapMu.RLock()
+	aliasMu.Lock()
+	for _, raw := range state.Aliases {
+		a := &Alias{}
+		if err := protojson.Unmarshal(raw, a); err != nil {
+			logger.Log().Error("Failed to restore alias", zap.Error(err))
+			continue
+		}
+		if _, exists := DSequences[a.Name]; exists {
+			logger.Log().Error("Failed to restore alias", zap.String("alias", a.Name), zap.Error(fmt.Errorf("alias name %s conflicts with an engine ID", a.Name)))
+			continue
+		}
+		aliases[a.Name] = a
+	}
+	aliasMu.Unlock()
+	mapMu.RUnlock()
+	for _, src := range state.Pipelines {
+		if _, err := registerPipeline([]byte(src), true); err != nil {
+			logger.Log().Error("Failed to restore pipeline", zap.Error(err))
+			key := src
+			if p, err := pipeline.Parse([]byte(src)); err == nil {
+				key = p.Name
+			}
+			persistMu.Lock()
+			failedPipelines[key] = src
+			persistMu.Unlock()
+		}
+	}
+	logger.Log().Info("Restored engines from registry", zap.String("path", path), zap.Int("restored", restored), zap.Int("failed", len(state.Engines)-restored))
+	saveRegistry()
+	return nil
+}
+
+// restoreRecord 以原 ID 重新创建一条引擎定义
+func restoreRecord(rec persistedEngine) error {
+	if len(rec.Ensemble) > 0 {
+		req := &CreateEnsembleRequest{}
+		if err := protojson.Unmarshal(rec.Ensemble, req); err != nil {
+			return err
+		}
+		_, err := createEnsemble(rec.ID, req)
+		return err
+	}
+	req := &InitEngineRequest{}
+	if err := protojson.Unmarshal(rec.Request, req); err != nil {
+		return err
+	}
+	if rec.Backend != engine.BackendName() {
+		return fmt.Errorf("engine was created with backend %q, current backend is %q", rec.Backend, engine.BackendName())
+	}
+	if _, _, err := createEngine(rec.ID, req); err != nil {
+		return err
+	}
+	if req.IdempotencyKey != "" {
+		idemMu.Lock()
+		idempotencyKeys[req.IdempotencyKey] = idempotencyEntry{id: rec.ID, fingerprint: requestFingerprint(req)}
+		idemMu.Unlock()
+	}
+	return nil
+}
+
+// restoreFailureList 返回恢复失败的引擎定义
+func restoreFailureList() []*RestoreFailure {
+	persistMu.Lock()
+	defer persistMu.Unlock()
+	out := make([]*RestoreFailure, 0, len(restoreFailures))
+	for _, f := range restoreFailures {
+		out = append(out, f)
+	}
+	slices.SortFunc(out, func(a, b *RestoreFailure) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return out
+}
+
+// discardFailedPipeline 丢弃一个恢复失败的流水线定义，返回该名称是否存在
+func discardFailedPipeline(name string) bool {
+	persistMu.Lock()
+	_, ok := failedPipelines[name]
+	delete(failedPipelines, name)
+	persistMu.Unlock()
+	return ok
+}
+
+// discardFailedRestore 丢弃一个恢复失败的引擎定义，返回该 ID 是否存在
+func discardFailedRestore(id string) bool {
+	persistMu.Lock()
+	_, ok := failedRecords[id]
+	delete(failedRecords, id)
+	delete(restoreFailures, id)
+	persistMu.Unlock()
+	if ok {
+		saveRegistry()
+	}
+	return ok
+}
@@ -0,0 +1,177 @@
+package proto
+
+import (
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"OnnxDetServer/pipeline"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	pipelines = make(map[string]*pipeline.Pipeline)
+	pipeMu    sync.RWMutex
+)
+
+// engineExists 判断引擎 ID 或别名当前是否存在
+func engineExists(id string) bool {
+	mapMu.RLock()
+	_, exists := DSequences[id]
+	mapMu.RUnlock()
+	if exists {
+		return true
+	}
+	aliasMu.RLock()
+	defer aliasMu.RUnlock()
+	_, exists = aliases[id]
+	return exists
+}
+
+// registerPipeline 解析并注册流水线，引用的引擎必须已存在
+func registerPipeline(source []byte, replace bool) (*pipeline.Pipeline, error) {
+	p, err := pipeline.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range p.Engines() {
+		if !engineExists(e) {
+			return nil, fmt.Errorf("pipeline %s references unknown engine or alias %s", p.Name, e)
+		}
+	}
+	pipeMu.Lock()
+	defer pipeMu.Unlock()
+	if _, exists := pipelines[p.Name]; exists && !replace {
+		return nil, status.Errorf(codes.AlreadyExists, "pipeline %s already exists", p.Name)
+	}
+	pipelines[p.Name] = p
+	return p, nil
+}
+
+func pipelineSources() [][]byte {
+	pipeMu.RLock()
+	defer pipeMu.RUnlock()
+	names := slices.Sorted(maps.Keys(pipelines))
+	sources := make([][]byte, len(names))
+	for i, name := range names {
+		sources[i] = pipelines[name].Source
+	}
+	return sources
+}
+
+// toPipelineDetections 把流水线结果树转换为 proto 消息
+func toPipelineDetections(dets []*pipeline.Detection) []*PipelineDetection {
+	out := make([]*PipelineDetection, 0, len(dets))
+	for _, d := range dets {
+		labels := make([]*PipelineLabel, len(d.Labels))
+		for i, l := range d.Labels {
+			labels[i] = &PipelineLabel{Stage: l.Stage, Name: l.Class, Confidence: l.Conf}
+		}
+		out = append(out, &PipelineDetection{
+			Stage:      d.Stage,
+			Name:       d.Class,
+			Confidence: d.Conf,
+			Box: []*Position{
+				{X: int32(d.X1), Y: int32(d.Y1)},
+				{X: int32(d.X2), Y: int32(d.Y1)},
+				{X: int32(d.X2), Y: int32(d.Y2)},
+				{X: int32(d.X1), Y: int32(d.Y2)},
+			},
+			Center:   &Position{X: int32((d.X1 + d.X2) / 2), Y: int32((d.Y1 + d.Y2) / 2)},
+			Labels:   labels,
+			Children: toPipelineDetections(d.Children),
+		})
+	}
+	return out
+}
+
+func (s *Server) RegisterPipeline(ctx context.Context, req *RegisterPipelineRequest) (*PipelineResponse, error) {
+	monitor.GRPCTotal.Inc()
+	p, err := registerPipeline([]byte(req.Yaml), req.Replace)
+	if err != nil {
+		return nil, err
+	}
+	// 同名的恢复失败定义被新定义取代
+	discardFailedPipeline(p.Name)
+	saveRegistry()
+	logger.Log().Info("Registered pipeline", zap.String("name", p.Name), zap.Strings("stages", p.StageNames()), zap.Strings("engines", p.Engines()))
+	return &PipelineResponse{Success: true, Message: "Successfully registered pipeline", Name: p.Name}, nil
+}
+
+func (s *Server) DeletePipeline(ctx context.Context, req *DeletePipelineRequest) (*PipelineResponse, error) {
+	monitor.GRPCTotal.Inc()
+	pipeMu.Lock()
+	_, exists := pipelines[req.Name]
+	delete(pipelines, req.Name)
+	pipeMu.Unlock()
+	// 恢复失败的定义同样可以删除
+	if !discardFailedPipeline(req.Name) && !exists {
+		return nil, status.Errorf(codes.NotFound, "pipeline %s not found", req.Name)
+	}
+	saveRegistry()
+	logger.Log().Info("Deleted pipeline", zap.String("name", req.Name))
+	return &PipelineResponse{Success: true, Message: "Successfully deleted pipeline", Name: req.Name}, nil
+}
+
+func (s *Server) ListPipelines(ctx context.Context, req *emptypb.Empty) (*ListPipelinesResponse, error) {
+	monitor.GRPCTotal.Inc()
+	pipeMu.RLock()
+	infos := make([]*PipelineInfo, 0, len(pipelines))
+	for _, p := range pipelines {
+		infos = append(infos, &PipelineInfo{
+			Name:    p.Name,
+			Stages:  p.StageNames(),
+			Engines: p.Engines(),
+			Yaml:    string(p.Source),
+		})
+	}
+	pipeMu.RUnlock()
+	slices.SortFunc(infos, func(a, b *PipelineInfo) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return &ListPipelinesResponse{Pipelines: infos}, nil
+}
+
+func (s *Server) RunPipeline(ctx context.Context, req *RunPipelineRequest) (*RunPipelineResponse, error) {
+	monitor.GRPCTotal.Inc()
+	pipeMu.RLock()
+	p, exists := pipelines[req.Name]
+	pipeMu.RUnlock()
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "pipeline %s not found", req.Name)
+	}
+	if req.ImgData == nil {
+		return nil, fmt.Errorf("image data is invalid")
+	}
+	start := time.Now()
+	dets, err := p.Run(detectWith, iface.ImageData{
+		Data:     req.ImgData.Data,
+		Width:    req.ImgData.Width,
+		Height:   req.ImgData.Height,
+		Channels: req.ImgData.Channels,
+	})
+	if err != nil {
+		logger.Log().Error("Pipeline failed", zap.String("name", req.Name), zap.Error(err))
+		return &RunPipelineResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Pipeline %s failed: %v", req.Name, err),
+			Detections: make([]*PipelineDetection, 0),
+		}, nil
+	}
+	logger.Log().Debug("Pipeline finished", zap.String("name", req.Name), zap.Duration("elapsed", time.Since(start)))
+	return &RunPipelineResponse{
+		Success:    true,
+		Message:    "Pipeline finished",
+		Detections: toPipelineDetections(dets),
+	}, nil
+}
@@ -0,0 +1,116 @@
+package proto
+
+import (
+	"OnnxDetServer/engine"
+	"OnnxDetServer/logger"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// PreloadEngine 是 config.yaml 中 engines 列表的一项，服务启动时以固定 ID 加载
+type PreloadEngine struct {
+	ID          string   `yaml:"id"`
+	Description string   `yaml:"description"`
+	ModelPath   string   `yaml:"modelPath"`
+	Names       []string `yaml:"names"`
+	NamesFile   string   `yaml:"namesFile"`
+	Confidence  float32  `yaml:"confidence"`
+	Iou         float32  `yaml:"iou"`
+	InputSize   int32    `yaml:"inputSize"`
+	UseGPU      bool     `yaml:"useGpu"`
+	InputBlob   string   `yaml:"inputBlob"`
+	OutputBlob  string   `yaml:"outputBlob"`
+}
+
+var (
+	preloadMu sync.Mutex
+	// preloadFailures 记录降级启动时加载失败的预加载引擎，通过 CheckAllEngine 报告
+	preloadFailures []*RestoreFailure
+)
+
+// request 把配置项转换为 InitEngineRequest，namesFile 在这里读取为类别名列表，启动时即可发现格式错误
+func (p PreloadEngine) request() (*InitEngineRequest, error) {
+	if p.ID == "" {
+		return nil, fmt.Errorf("preloaded engine %q must have an id", p.ModelPath)
+	}
+	if len(p.Names) > 0 && p.NamesFile != "" {
+		return nil, fmt.Errorf("names and namesFile cannot both be set")
+	}
+	if _, err := os.Stat(resolveModelPath(p.ModelPath)); err != nil {
+		return nil, fmt.Errorf("model file: %w", err)
+	}
+	names := p.Names
+	if p.NamesFile != "" {
+		lines, err := engine.ReadNamesFile(resolveModelPath(p.NamesFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read names file: %w", err)
+		}
+		names = lines
+	}
+	req := &InitEngineRequest{
+		EngineType:  engine.SingleThread,
+		ModelPath:   p.ModelPath,
+		Names:       names,
+		InputSize:   p.InputSize,
+		Confidence:  p.Confidence,
+		Iou:         p.Iou,
+		UseGpu:      p.UseGPU,
+		Description: p.Description,
+		InputBlob:   p.InputBlob,
+		OutputBlob:  p.OutputBlob,
+	}
+	if err := validateInitRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// PreloadEngines 在 gRPC 服务启动前加载配置中的引擎。strict 为 true 时遇到第一个失败即返回错误，
+// 否则跳过失败的引擎继续启动，失败信息通过 CheckAllEngine 的 preload_failures 报告
+func PreloadEngines(engines []PreloadEngine, strict bool) error {
+	var errs []error
+	seen := make(map[string]bool, len(engines))
+	for _, p := range engines {
+		req, err := p.request()
+		if err == nil && seen[p.ID] {
+			err = fmt.Errorf("duplicate engine id")
+		}
+		seen[p.ID] = true
+		if err == nil {
+			var d *WorkerID
+			d, _, err = createEngine(p.ID, req)
+			if err == nil {
+				d.preloaded = true
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("preload engine %s (%s): %w", p.ID, p.ModelPath, err)
+			if strict {
+				return err
+			}
+			logger.Log().Error("Failed to preload engine", zap.String("ID", p.ID), zap.String("ModelPath", p.ModelPath), zap.Error(err))
+			preloadMu.Lock()
+			preloadFailures = append(preloadFailures, &RestoreFailure{
+				Id:          p.ID,
+				Description: p.Description,
+				ModelPath:   p.ModelPath,
+				Error:       err.Error(),
+			})
+			preloadMu.Unlock()
+			errs = append(errs, err)
+			continue
+		}
+		logger.Log().Info("Preloaded engine", zap.String("ID", p.ID), zap.String("ModelPath", p.ModelPath), zap.String("description", p.Description))
+	}
+	return errors.Join(errs...)
+}
+
+func preloadFailureList() []*RestoreFailure {
+	preloadMu.Lock()
+	defer preloadMu.Unlock()
+	return append([]*RestoreFailure(nil), preloadFailures...)
+}
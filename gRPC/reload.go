@@ -0,0 +1,148 @@
+package proto
+
+import (
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// swapMu 串行化对引擎的热替换，避免同一引擎的多次替换交错
+var swapMu sync.Mutex
+
+// swapEngine 按 req 加载新实例，可选地用 warmup 图片校验后，在同一 ID 下原子替换旧实例。
+// 新请求立即进入新实例，旧实例在在途任务结束（或超时）后释放；任何一步失败都保留旧实例不变。
+// 调用方需持有 swapMu
+func swapEngine(id string, req *InitEngineRequest, warmup *ImageData, timeout time.Duration) (*WorkerID, error) {
+	mapMu.RLock()
+	old, exists := DSequences[id]
+	if !exists {
+		err := engineNotFound(id)
+		mapMu.RUnlock()
+		return nil, err
+	}
+	mapMu.RUnlock()
+	if state := old.getState(); state != EngineState_ENGINE_STATE_ACTIVE {
+		return nil, fmt.Errorf("detector with ID %s is %s", id, stateName(state))
+	}
+
+	next, _, err := newWorker(req)
+	if err != nil {
+		return nil, err
+	}
+	if warmup != nil {
+		if err := warmupEngine(next.detector, warmup); err != nil {
+			next.free()
+			return nil, fmt.Errorf("warmup failed: %w", err)
+		}
+	}
+	// 替换后保留引擎的运行时属性，租约到期时间不因替换而顺延
+	next.Description = old.Description
+	next.EngineType = old.EngineType
+	next.idleTTL = old.idleTTL
+	next.lease = old.leaseDuration()
+	next.leaseExpiry.Store(old.leaseExpiry.Load())
+	next.idempotencyKey = old.idempotencyKey
+	next.preloaded = old.preloaded
+	next.revision = old.configRevision() + 1
+	next.shadow.Store(old.shadow.Load())
+	next.zoneRules.Store(old.zoneRules.Load())
+	next.id = id
+	next.touch()
+
+	mapMu.Lock()
+	if DSequences[id] != old || old.getState() != EngineState_ENGINE_STATE_ACTIVE {
+		mapMu.Unlock()
+		next.free()
+		return nil, fmt.Errorf("detector with ID %s was removed during reload", id)
+	}
+	old.mu.Lock()
+	old.state = EngineState_ENGINE_STATE_DRAINING
+	old.mu.Unlock()
+	old.jobs.close()
+	DSequences[id] = next
+	mapMu.Unlock()
+	saveRegistry()
+
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+	drained := old.jobs.wait(timeout)
+	old.mu.Lock()
+	old.state = EngineState_ENGINE_STATE_DESTROYED
+	old.freeOnIdle = true
+	old.mu.Unlock()
+	if old.jobs.count() == 0 {
+		old.free()
+	} else if !drained {
+		logger.Log().Warn("Old engine instance still has in-flight jobs after reload, freed when they finish", zap.String("ID", id), zap.Int("inFlight", old.jobs.count()))
+	}
+	return next, nil
+}
+
+// warmupEngine 用预热图片对尚未注册的新实例推理一次，确认模型可用
+func warmupEngine(detector iface.Backend, img *ImageData) error {
+	if img.Data == nil || len(img.Data) == 0 || img.Width == 0 || img.Height == 0 || img.Channels == 0 {
+		return fmt.Errorf("warmup image is invalid")
+	}
+	result := submitJob(detector, iface.ImageData{
+		Data:     img.Data,
+		Width:    img.Width,
+		Height:   img.Height,
+		Channels: img.Channels,
+	})
+	if !result.Data.Success {
+		return fmt.Errorf("detector returned failure")
+	}
+	if _, ok := result.Data.Data.(map[string][]iface.Result); !ok {
+		return fmt.Errorf("unexpected data type in results: %T", result.Data.Data)
+	}
+	return nil
+}
+
+func (s *Server) ReloadEngine(ctx context.Context, req *ReloadEngineRequest) (*ReloadEngineResponse, error) {
+	monitor.GRPCTotal.Inc()
+	swapMu.Lock()
+	defer swapMu.Unlock()
+	mapMu.RLock()
+	old, exists := DSequences[req.Id]
+	if !exists {
+		err := engineNotFound(req.Id)
+		mapMu.RUnlock()
+		return nil, err
+	}
+	mapMu.RUnlock()
+	cfg := old.config()
+	if cfg == nil {
+		return nil, fmt.Errorf("detector with ID %s has no recorded configuration to reload", req.Id)
+	}
+	next := protobuf.Clone(cfg).(*InitEngineRequest)
+	if req.ModelPath != "" {
+		next.ModelPath = req.ModelPath
+	}
+	if err := validateInitRequest(next); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	_, err := swapEngine(req.Id, next, req.WarmupImage, time.Duration(req.TimeoutMs)*time.Millisecond)
+	if err != nil {
+		logger.Log().Error("Failed to reload engine, keeping old instance", zap.String("ID", req.Id), zap.String("ModelPath", next.ModelPath), zap.Error(err))
+		return &ReloadEngineResponse{
+			Success: false,
+			Id:      req.Id,
+			Message: fmt.Sprintf("Failed to reload engine, old instance kept: %v", err),
+		}, nil
+	}
+	logger.Log().Info("Reloaded engine", zap.String("ID", req.Id), zap.String("ModelPath", next.ModelPath), zap.Duration("elapsed", time.Since(start)))
+	return &ReloadEngineResponse{
+		Success: true,
+		Id:      req.Id,
+		Message: "Successfully reloaded engine",
+	}, nil
+}
@@ -0,0 +1,258 @@
+package proto
+
+import (
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultShadowIoU = 0.5
+
+// shadowSlots 限制同时进行的影子推理数，超出的采样直接丢弃，不影响主请求。
+// 影子推理与主请求共用 JobQueue 的 worker，上限为 worker 数的一半，至少为 1
+var shadowSlots = make(chan struct{}, 1)
+
+// setShadowLimit 按 worker 数设置影子推理的并发上限，在启动 worker 时调用
+func setShadowLimit(workers int) {
+	shadowSlots = make(chan struct{}, max(1, workers/2))
+}
+
+// classAgreement 是单个类别的累计对比结果
+type classAgreement struct {
+	matched, extra, missing int64
+	confDeltaSum            float64
+	absConfDeltaSum         float64
+	iouSum                  float64
+}
+
+// shadowConfig 是引擎的影子推理设置及其累计统计
+type shadowConfig struct {
+	engineID   string
+	shadowID   string
+	sampleRate float64
+	iou        float32
+	since      time.Time
+
+	mu       sync.Mutex
+	compared int64
+	failed   int64
+	dropped  int64
+	classes  map[string]*classAgreement
+}
+
+// matchClass 按 IoU 从高到低贪心匹配同一类别的生产结果与影子结果
+func matchClass(prod, shadow []iface.Result, threshold float32) (pairs [][2]int, matchedIoU []float32) {
+	type candidate struct {
+		p, s int
+		iou  float32
+	}
+	candidates := make([]candidate, 0)
+	for i, p := range prod {
+		for j, s := range shadow {
+			if iou := p.Box.IoU(s.Box); iou >= threshold {
+				candidates = append(candidates, candidate{i, j, iou})
+			}
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.iou, a.iou)
+	})
+	usedP := make([]bool, len(prod))
+	usedS := make([]bool, len(shadow))
+	for _, c := range candidates {
+		if usedP[c.p] || usedS[c.s] {
+			continue
+		}
+		usedP[c.p], usedS[c.s] = true, true
+		pairs = append(pairs, [2]int{c.p, c.s})
+		matchedIoU = append(matchedIoU, c.iou)
+	}
+	return pairs, matchedIoU
+}
+
+// record 对比一次生产结果与影子结果并累计到统计中
+func (c *shadowConfig) record(prod, shadow map[string][]iface.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.compared++
+	classes := make(map[string]bool, len(prod)+len(shadow))
+	for name := range prod {
+		classes[name] = true
+	}
+	for name := range shadow {
+		classes[name] = true
+	}
+	for name := range classes {
+		p, s := prod[name], shadow[name]
+		pairs, ious := matchClass(p, s, c.iou)
+		stat, ok := c.classes[name]
+		if !ok {
+			stat = &classAgreement{}
+			c.classes[name] = stat
+		}
+		extra := int64(len(s) - len(pairs))
+		missing := int64(len(p) - len(pairs))
+		stat.matched += int64(len(pairs))
+		stat.extra += extra
+		stat.missing += missing
+		for i, pair := range pairs {
+			delta := float64(s[pair[1]].Conf - p[pair[0]].Conf)
+			stat.confDeltaSum += delta
+			stat.absConfDeltaSum += max(delta, -delta)
+			stat.iouSum += float64(ious[i])
+			monitor.ShadowConfidenceDelta.WithLabelValues(c.engineID, c.shadowID, name).Observe(delta)
+		}
+		monitor.ShadowDetections.WithLabelValues(c.engineID, c.shadowID, name, "matched").Add(float64(len(pairs)))
+		monitor.ShadowDetections.WithLabelValues(c.engineID, c.shadowID, name, "extra").Add(float64(extra))
+		monitor.ShadowDetections.WithLabelValues(c.engineID, c.shadowID, name, "missing").Add(float64(missing))
+	}
+}
+
+// maybeShadow 按采样比例把请求异步发送给影子引擎，不阻塞也不影响主请求
+func (d *WorkerID) maybeShadow(image iface.ImageData, prod map[string][]iface.Result) {
+	c := d.shadow.Load()
+	if c == nil || rand.Float64() >= c.sampleRate {
+		return
+	}
+	slots := shadowSlots
+	select {
+	case slots <- struct{}{}:
+	default:
+		c.mu.Lock()
+		c.dropped++
+		c.mu.Unlock()
+		return
+	}
+	go func() {
+		defer func() { <-slots }()
+		if err := c.run(image, prod); err != nil {
+			c.mu.Lock()
+			c.failed++
+			c.mu.Unlock()
+			logger.Log().Debug("Shadow inference failed", zap.String("ID", c.engineID), zap.String("shadow", c.shadowID), zap.Error(err))
+		}
+	}()
+}
+
+func (c *shadowConfig) run(image iface.ImageData, prod map[string][]iface.Result) error {
+	shadow, err := acquireEngine(c.shadowID)
+	if err != nil {
+		return err
+	}
+	defer shadow.release()
+	result := submitJob(shadow.detector, image)
+	if !result.Data.Success {
+		return fmt.Errorf("shadow detector returned failure")
+	}
+	detResults, ok := result.Data.Data.(map[string][]iface.Result)
+	if !ok {
+		return fmt.Errorf("unexpected data type in shadow results: %T", result.Data.Data)
+	}
+	c.record(prod, shadow.filterResults(detResults))
+	return nil
+}
+
+func (c *shadowConfig) stats() *ShadowStatsResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	classes := make([]*ClassAgreement, 0, len(c.classes))
+	for name, stat := range c.classes {
+		agreement := &ClassAgreement{
+			Name:    name,
+			Matched: stat.matched,
+			Extra:   stat.extra,
+			Missing: stat.missing,
+		}
+		if stat.matched > 0 {
+			agreement.MeanConfDelta = float32(stat.confDeltaSum / float64(stat.matched))
+			agreement.MeanAbsConfDelta = float32(stat.absConfDeltaSum / float64(stat.matched))
+			agreement.MeanIou = float32(stat.iouSum / float64(stat.matched))
+		}
+		classes = append(classes, agreement)
+	}
+	slices.SortFunc(classes, func(a, b *ClassAgreement) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return &ShadowStatsResponse{
+		Id:           c.engineID,
+		ShadowId:     c.shadowID,
+		SampleRate:   float32(c.sampleRate),
+		IouThreshold: c.iou,
+		Compared:     c.compared,
+		Failed:       c.failed,
+		Dropped:      c.dropped,
+		Classes:      classes,
+		SinceUnixMs:  c.since.UnixMilli(),
+	}
+}
+
+func (s *Server) SetShadow(ctx context.Context, req *SetShadowRequest) (*SetShadowResponse, error) {
+	monitor.GRPCTotal.Inc()
+	mapMu.RLock()
+	detector, exists := DSequences[req.Id]
+	var err error
+	if !exists {
+		err = engineNotFound(req.Id)
+	} else if _, ok := DSequences[req.ShadowId]; req.ShadowId != "" && !ok {
+		err = fmt.Errorf("shadow engine: %w", engineNotFound(req.ShadowId))
+	}
+	mapMu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if req.ShadowId == "" {
+		detector.shadow.Store(nil)
+		logger.Log().Info("Cleared shadow engine", zap.String("ID", req.Id))
+		return &SetShadowResponse{Success: true, Message: "Shadow inference disabled"}, nil
+	}
+	if req.ShadowId == req.Id {
+		return nil, fmt.Errorf("an engine cannot shadow itself")
+	}
+	if req.SampleRate <= 0 || req.SampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be in (0, 1], got %f", req.SampleRate)
+	}
+	if req.IouThreshold < 0 || req.IouThreshold > 1 {
+		return nil, fmt.Errorf("IoU threshold must be between 0.0 and 1.0, got %f", req.IouThreshold)
+	}
+	iou := req.IouThreshold
+	if iou == 0 {
+		iou = defaultShadowIoU
+	}
+	// 重新设置影子引擎会清空之前的统计
+	detector.shadow.Store(&shadowConfig{
+		engineID:   req.Id,
+		shadowID:   req.ShadowId,
+		sampleRate: float64(req.SampleRate),
+		iou:        iou,
+		since:      time.Now(),
+		classes:    make(map[string]*classAgreement),
+	})
+	logger.Log().Info("Set shadow engine", zap.String("ID", req.Id), zap.String("shadow", req.ShadowId), zap.Float32("sampleRate", req.SampleRate), zap.Float32("iou", iou))
+	return &SetShadowResponse{Success: true, Message: "Shadow inference enabled"}, nil
+}
+
+func (s *Server) GetShadowStats(ctx context.Context, req *ShadowStatsRequest) (*ShadowStatsResponse, error) {
+	monitor.GRPCTotal.Inc()
+	mapMu.RLock()
+	detector, exists := DSequences[req.Id]
+	if !exists {
+		err := engineNotFound(req.Id)
+		mapMu.RUnlock()
+		return nil, err
+	}
+	mapMu.RUnlock()
+	c := detector.shadow.Load()
+	if c == nil {
+		return nil, fmt.Errorf("detector with ID %s has no shadow engine", req.Id)
+	}
+	return c.stats(), nil
+}
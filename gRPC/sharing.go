@@ -0,0 +1,290 @@
+package proto
+
+import (
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// engineSpec 描述创建一个原生实例所需的全部参数
+type engineSpec struct {
+	ModelPath      string   `json:"modelPath"`
+	Names          []string `json:"names"`
+	InputSize      int32    `json:"inputSize"`
+	Confidence     float32  `json:"confidence"`
+	Iou            float32  `json:"iou"`
+	UseGPU         bool     `json:"useGpu"`
+	IsolationGroup string   `json:"isolationGroup"`
+	InputBlob      string   `json:"inputBlob"`
+	OutputBlob     string   `json:"outputBlob"`
+}
+
+func specFromRequest(req *InitEngineRequest) engineSpec {
+	return engineSpec{
+		ModelPath:      resolveModelPath(req.ModelPath),
+		Names:          req.Names,
+		InputSize:      req.InputSize,
+		Confidence:     req.Confidence,
+		Iou:            req.Iou,
+		UseGPU:         req.UseGpu,
+		IsolationGroup: req.IsolationGroup,
+		InputBlob:      req.InputBlob,
+		OutputBlob:     req.OutputBlob,
+	}
+}
+
+// fingerprint 对影响原生实例行为的参数求哈希，指纹相同的引擎可以共享同一个实例
+func (s engineSpec) fingerprint() string {
+	canonical := s
+	if abs, err := filepath.Abs(s.ModelPath); err == nil {
+		canonical.ModelPath = abs
+	}
+	if canonical.Names == nil {
+		canonical.Names = []string{}
+	}
+	b, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadBackend 创建原生实例并加载模型，返回串行化推理调用的实例及其内存估算
+func loadBackend(spec engineSpec) (iface.Backend, uint64, error) {
+	detector, err := newBackend(spec.IsolationGroup)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create engine: %w", err)
+	}
+	names := iface.NamesConf{}
+	names.IsFile = false
+	names.Data = spec.Names
+	seqMu.Lock()
+	defer seqMu.Unlock()
+	rssBefore := monitor.SampleRSSMegabytes()
+	ok, err := detector.LoadModel(spec.ModelPath, names, spec.Confidence, spec.Iou, spec.UseGPU)
+	if err != nil {
+		detector.Destroy()
+		return nil, 0, fmt.Errorf("failed to load model: %w", err)
+	}
+	if !ok {
+		detector.Destroy()
+		return nil, 0, fmt.Errorf("failed to load model %s", spec.ModelPath)
+	}
+	detector.SetInputSize(int(spec.InputSize))
+	if spec.InputBlob != "" || spec.OutputBlob != "" {
+		detector.SetBlobName(spec.InputBlob, spec.OutputBlob)
+	}
+	// 以加载前后 RSS 的增量估算引擎内存，隔离模式下父进程无增量时退化为模型文件大小
+	memEstimate := modelFileSizeMB(spec.ModelPath)
+	if rssAfter := monitor.SampleRSSMegabytes(); rssAfter > rssBefore {
+		memEstimate = max(memEstimate, rssAfter-rssBefore)
+	}
+	return &lockedBackend{Backend: detector}, memEstimate, nil
+}
+
+// lockedBackend 串行化对原生实例的推理调用，原生 Detector 不支持并发 Detect，
+// 多个工作协程可能同时取到同一个引擎（或共享同一实例的多个引擎）的任务
+type lockedBackend struct {
+	iface.Backend
+	mu sync.Mutex
+}
+
+func (b *lockedBackend) Detect(image iface.ImageData) iface.RetData {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Backend.Detect(image)
+}
+
+// sharedInstance 是被多个引擎句柄引用计数共享的原生实例
+type sharedInstance struct {
+	fingerprint   string
+	backend       iface.Backend
+	memEstimateMB uint64
+	refs          int
+	// loading 在首次加载期间不为 nil，加载结束（成功或失败）时关闭，err 为加载错误
+	loading chan struct{}
+	err     error
+}
+
+var (
+	sharedPool = make(map[string]*sharedInstance)
+	poolMu     sync.Mutex
+)
+
+// attachShared 返回指纹相同的已有实例并增加引用，不存在时加载一个新实例。
+// 加载在 poolMu 之外进行，期间相同指纹的请求等待这次加载的结果，其他指纹不受影响
+func attachShared(spec engineSpec) (*sharedInstance, bool, error) {
+	fp := spec.fingerprint()
+	poolMu.Lock()
+	if inst, ok := sharedPool[fp]; ok {
+		inst.refs++
+		loading := inst.loading
+		poolMu.Unlock()
+		if loading != nil {
+			<-loading
+			if inst.err != nil {
+				return nil, false, inst.err
+			}
+		}
+		return inst, true, nil
+	}
+	inst := &sharedInstance{fingerprint: fp, refs: 1, loading: make(chan struct{})}
+	sharedPool[fp] = inst
+	poolMu.Unlock()
+
+	backend, memEstimate, err := loadBackend(spec)
+	poolMu.Lock()
+	if err != nil {
+		inst.err = err
+		delete(sharedPool, fp)
+	} else {
+		inst.backend = backend
+		inst.memEstimateMB = memEstimate
+	}
+	close(inst.loading)
+	inst.loading = nil
+	poolMu.Unlock()
+	if err != nil {
+		return nil, false, err
+	}
+	return inst, false, nil
+}
+
+// detach 释放一个引用，最后一个引用释放时销毁原生实例
+func (inst *sharedInstance) detach() {
+	poolMu.Lock()
+	inst.refs--
+	last := inst.refs == 0
+	if last {
+		delete(sharedPool, inst.fingerprint)
+	}
+	poolMu.Unlock()
+	if last {
+		inst.backend.Destroy()
+		logger.Log().Info("Destroyed shared engine instance", zap.String("fingerprint", inst.fingerprint))
+	}
+}
+
+func (inst *sharedInstance) refCount() int {
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	return inst.refs
+}
+
+// memoryEstimateMB 返回销毁该句柄能释放的内存估算；共享实例仍被其他句柄引用时为 0
+func (d *WorkerID) memoryEstimateMB() uint64 {
+	if d.shared == nil {
+		return d.memEstimateMB
+	}
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	if d.shared.refs > 1 {
+		return 0
+	}
+	return d.shared.memEstimateMB
+}
+
+// totalMemoryEstimateMB 汇总所有原生实例的内存估算，共享实例只计算一次
+func totalMemoryEstimateMB(all map[string]*WorkerID) uint64 {
+	var total uint64
+	for _, d := range all {
+		if d.shared == nil {
+			total += d.memEstimateMB
+		}
+	}
+	poolMu.Lock()
+	for _, inst := range sharedPool {
+		total += inst.memEstimateMB
+	}
+	poolMu.Unlock()
+	return total
+}
+
+type idempotencyEntry struct {
+	id          string
+	fingerprint string
+	// pending 非空时该键的引擎正在创建，创建结束后关闭
+	pending chan struct{}
+}
+
+var (
+	idempotencyKeys = make(map[string]idempotencyEntry)
+	idemMu          sync.Mutex
+)
+
+// requestFingerprint 对除幂等键以外的全部请求字段求哈希，用于识别同一个键被用于不同请求
+func requestFingerprint(req *InitEngineRequest) string {
+	clone := protobuf.Clone(req).(*InitEngineRequest)
+	clone.IdempotencyKey = ""
+	b, _ := protobuf.MarshalOptions{Deterministic: true}.Marshal(clone)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// claimIdempotencyKey 返回该键已创建且仍存活的引擎 ID。键未被使用时登记为创建中，返回空 ID 和 release，
+// 调用方创建结束后以新引擎 ID（失败时为空）调用 release；同一个键正在创建时等待其结束，不阻塞其他键
+func claimIdempotencyKey(ctx context.Context, key, fingerprint string) (string, func(id string), error) {
+	for {
+		idemMu.Lock()
+		e, ok := idempotencyKeys[key]
+		if ok && e.pending != nil {
+			idemMu.Unlock()
+			select {
+			case <-e.pending:
+				continue
+			case <-ctx.Done():
+				return "", nil, status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		if ok {
+			mapMu.RLock()
+			_, alive := DSequences[e.id]
+			mapMu.RUnlock()
+			if alive {
+				idemMu.Unlock()
+				if e.fingerprint != fingerprint {
+					return "", nil, status.Errorf(codes.FailedPrecondition, "idempotency key %q was already used with a different request", key)
+				}
+				return e.id, nil, nil
+			}
+		}
+		done := make(chan struct{})
+		idempotencyKeys[key] = idempotencyEntry{fingerprint: fingerprint, pending: done}
+		idemMu.Unlock()
+		release := func(id string) {
+			idemMu.Lock()
+			defer idemMu.Unlock()
+			if e, ok := idempotencyKeys[key]; ok && e.pending == done {
+				if id == "" {
+					delete(idempotencyKeys, key)
+				} else {
+					idempotencyKeys[key] = idempotencyEntry{id: id, fingerprint: fingerprint}
+				}
+			}
+			close(done)
+		}
+		return "", release, nil
+	}
+}
+
+// forgetIdempotencyKey 在引擎销毁后删除其幂等键，之后使用同一个键会创建新引擎
+func forgetIdempotencyKey(key, id string) {
+	if key == "" {
+		return
+	}
+	idemMu.Lock()
+	defer idemMu.Unlock()
+	if e, ok := idempotencyKeys[key]; ok && e.id == id {
+		delete(idempotencyKeys, key)
+	}
+}
@@ -0,0 +1,192 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"OnnxDetServer/webhook"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxStreamInFlight = 32
+
+// streamSession 是一个 StreamInference 流的推理窗口。window 中的每个令牌对应一个正在推理的帧；
+// keepLatest 时窗口已满的新帧放入 pending 替换更早的帧，由推理完成的工作协程接着处理
+type streamSession struct {
+	id string
+	// key 唯一标识这条流，webhook 以它区分不同的流
+	key        string
+	keepLatest bool
+	maxAge     time.Duration
+	window     chan struct{}
+	// tracker 非空时按帧顺序为检测分配轨迹 ID 并统计区域，因此窗口只能为 1
+	tracker *trackingSession
+
+	sendMu sync.Mutex
+	send   func(*StreamInferenceResponse) error
+	// sendErr 记录第一次发送失败，之后的结果不再发送
+	sendErr error
+
+	mu      sync.Mutex
+	pending *StreamInferenceRequest
+	wg      sync.WaitGroup
+}
+
+func newStreamSession(first *StreamInferenceRequest, send func(*StreamInferenceResponse) error) (*streamSession, error) {
+	if first.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "the first stream message must set the engine id")
+	}
+	if first.MaxInFlight < 0 || first.MaxInFlight > maxStreamInFlight {
+		return nil, status.Errorf(codes.InvalidArgument, "max in flight must be between 0 and %d, got %d", maxStreamInFlight, first.MaxInFlight)
+	}
+	if first.MaxFrameAgeMs < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "max frame age cannot be negative, got %d", first.MaxFrameAgeMs)
+	}
+	var tracker *trackingSession
+	if first.Tracker != nil || first.Zones != nil {
+		if first.MaxInFlight > 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "tracking and zones require max in flight of 1, got %d", first.MaxInFlight)
+		}
+		var err error
+		if tracker, err = newTrackingSession(first.Tracker, first.Zones); err != nil {
+			return nil, err
+		}
+	}
+	return &streamSession{
+		id:         first.Id,
+		key:        uuid.NewString(),
+		keepLatest: first.KeepLatest,
+		maxAge:     time.Duration(first.MaxFrameAgeMs) * time.Millisecond,
+		window:     make(chan struct{}, max(first.MaxInFlight, 1)),
+		tracker:    tracker,
+		send:       send,
+	}, nil
+}
+
+// reply 串行地发送结果，gRPC 流的 Send 不能并发调用
+func (s *streamSession) reply(frame *StreamInferenceRequest, resp *StreamInferenceResponse) {
+	resp.Seq = frame.Seq
+	resp.CaptureUnixMs = frame.CaptureUnixMs
+	if frame.CaptureUnixMs > 0 {
+		latency := time.Since(time.UnixMilli(frame.CaptureUnixMs))
+		resp.LatencyMs = latency.Milliseconds()
+		if !resp.Dropped {
+			monitor.StreamLatency.Observe(latency.Seconds())
+		}
+	}
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if s.sendErr == nil {
+		s.sendErr = s.send(resp)
+	}
+}
+
+func (s *streamSession) drop(frame *StreamInferenceRequest, reason string) {
+	monitor.StreamFrames.WithLabelValues("dropped").Inc()
+	s.reply(frame, &StreamInferenceResponse{Dropped: true, Message: reason})
+}
+
+// submit 把一帧放入窗口；窗口已满时按策略阻塞等待或替换尚未推理的帧
+func (s *streamSession) submit(ctx context.Context, frame *StreamInferenceRequest) {
+	if !s.keepLatest {
+		select {
+		case s.window <- struct{}{}:
+			s.dispatch(frame)
+		case <-ctx.Done():
+		}
+		return
+	}
+	s.mu.Lock()
+	select {
+	case s.window <- struct{}{}:
+		s.mu.Unlock()
+		s.dispatch(frame)
+		return
+	default:
+	}
+	stale := s.pending
+	s.pending = frame
+	s.mu.Unlock()
+	if stale != nil {
+		s.drop(stale, "replaced by a newer frame")
+	}
+}
+
+// dispatch 启动一个工作协程推理该帧，完成后继续处理等待中的最新帧，没有等待的帧时归还窗口令牌
+func (s *streamSession) dispatch(frame *StreamInferenceRequest) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for frame != nil {
+			s.process(frame)
+			s.mu.Lock()
+			frame, s.pending = s.pending, nil
+			if frame == nil {
+				<-s.window
+			}
+			s.mu.Unlock()
+		}
+	}()
+}
+
+func (s *streamSession) process(frame *StreamInferenceRequest) {
+	if s.maxAge > 0 && frame.CaptureUnixMs > 0 && time.Since(time.UnixMilli(frame.CaptureUnixMs)) > s.maxAge {
+		s.drop(frame, fmt.Sprintf("frame older than %s", s.maxAge))
+		return
+	}
+	at := time.Now()
+	if frame.CaptureUnixMs > 0 {
+		at = time.UnixMilli(frame.CaptureUnixMs)
+	}
+	result, err := inferFrame(&InferenceRequest{Id: s.id, ImgData: frame.ImgData}, s.tracker, s.key, at)
+	if err != nil {
+		monitor.StreamFrames.WithLabelValues("failed").Inc()
+		s.reply(frame, &StreamInferenceResponse{Message: err.Error()})
+		return
+	}
+	monitor.StreamFrames.WithLabelValues("processed").Inc()
+	s.reply(frame, &StreamInferenceResponse{Message: "ok", Result: result})
+}
+
+// serveStream 读取客户端的帧直到流结束，等待在途帧全部返回后结束
+func serveStream(ctx context.Context, recv func() (*StreamInferenceRequest, error), send func(*StreamInferenceResponse) error) error {
+	frame, err := recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s, err := newStreamSession(frame, send)
+	if err != nil {
+		return err
+	}
+	logger.Log().Debug("Stream inference started", zap.String("ID", s.id), zap.Int("window", cap(s.window)), zap.Bool("keepLatest", s.keepLatest))
+	for {
+		s.submit(ctx, frame)
+		if frame, err = recv(); err != nil {
+			break
+		}
+	}
+	s.wg.Wait()
+	webhook.ForgetStream(s.key)
+	if err != io.EOF {
+		return err
+	}
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.sendErr
+}
+
+func (s *Server) StreamInference(stream DetectService_StreamInferenceServer) error {
+	monitor.GRPCTotal.Inc()
+	return serveStream(stream.Context(), stream.Recv, stream.Send)
+}
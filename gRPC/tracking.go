@@ -0,0 +1,188 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"OnnxDetServer/tracking"
+	"OnnxDetServer/webhook"
+	"OnnxDetServer/zones"
+	"cmp"
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultTrackingSessionTTL 是未配置时跟踪会话的空闲过期时间
+	defaultTrackingSessionTTL = 10 * time.Minute
+	trackingSweepInterval     = 30 * time.Second
+)
+
+// trackingSession 是一路视频的跟踪状态，mu 保证同一会话的帧串行更新
+type trackingSession struct {
+	mu      sync.Mutex
+	tracker *tracking.Tracker
+	// zoneRules 是会话自己的区域规则，为 nil 时使用引擎的区域规则；
+	// analyzer 保存 analyzed 这份规则下每条轨迹的区域状态，规则变化时重建
+	zoneRules *zones.Config
+	analyzer  *zones.Analyzer
+	analyzed  *zones.Config
+	lastUsed  time.Time
+	// trackerCfg 与 zoneCfg 是创建会话时请求中的配置，之后的请求只能省略或发送相同的配置
+	trackerCfg *TrackerConfig
+	zoneCfg    *ZoneConfig
+}
+
+var (
+	trackingSessions = make(map[string]*trackingSession)
+	trackingMu       sync.Mutex
+)
+
+func trackerConfig(c *TrackerConfig) tracking.Config {
+	if c == nil {
+		return tracking.Config{}
+	}
+	return tracking.Config{
+		Method:     c.Method,
+		HighThresh: float64(c.HighThresh),
+		LowThresh:  float64(c.LowThresh),
+		MatchIoU:   float64(c.MatchIou),
+		MaxLost:    int(c.MaxLost),
+		CrossClass: c.CrossClass,
+	}
+}
+
+func newTrackingSession(c *TrackerConfig, z *ZoneConfig) (*trackingSession, error) {
+	cfg := trackerConfig(c)
+	if err := cfg.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	rules, err := zoneConfig(z)
+	if err != nil {
+		return nil, err
+	}
+	return &trackingSession{
+		tracker:    tracking.New(cfg),
+		zoneRules:  rules,
+		lastUsed:   time.Now(),
+		trackerCfg: protobuf.Clone(c).(*TrackerConfig),
+		zoneCfg:    protobuf.Clone(z).(*ZoneConfig),
+	}, nil
+}
+
+// trackingSessionFor 返回会话 ID 对应的跟踪会话，不存在时按 c 与 z 创建；
+// 会话已存在而请求带了与创建时不同的配置时返回 InvalidArgument
+func trackingSessionFor(id string, c *TrackerConfig, z *ZoneConfig) (*trackingSession, error) {
+	trackingMu.Lock()
+	defer trackingMu.Unlock()
+	if ts, ok := trackingSessions[id]; ok {
+		if c != nil && !protobuf.Equal(c, ts.trackerCfg) {
+			return nil, status.Errorf(codes.InvalidArgument, "tracking session %s was created with a different tracker config", id)
+		}
+		if z != nil && !protobuf.Equal(z, ts.zoneCfg) {
+			return nil, status.Errorf(codes.InvalidArgument, "tracking session %s was created with a different zone config", id)
+		}
+		ts.lastUsed = time.Now()
+		return ts, nil
+	}
+	ts, err := newTrackingSession(c, z)
+	if err != nil {
+		return nil, err
+	}
+	trackingSessions[id] = ts
+	logger.Log().Debug("Tracking session created", zap.String("session", id))
+	return ts, nil
+}
+
+// StartTrackingSweeper 周期性清理空闲超过 ttl 的跟踪会话，ttl 不大于 0 时使用默认的 10 分钟
+func StartTrackingSweeper(ctx context.Context, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultTrackingSessionTTL
+	}
+	ticker := time.NewTicker(min(trackingSweepInterval, ttl))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sweepTrackingSessions(now, ttl)
+		}
+	}
+}
+
+func sweepTrackingSessions(now time.Time, ttl time.Duration) {
+	var expired []string
+	trackingMu.Lock()
+	for sid, ts := range trackingSessions {
+		if now.Sub(ts.lastUsed) > ttl {
+			delete(trackingSessions, sid)
+			expired = append(expired, sid)
+		}
+	}
+	trackingMu.Unlock()
+	for _, sid := range expired {
+		webhook.ForgetSession(sid)
+		logger.Log().Info("Tracking session expired", zap.String("session", sid))
+	}
+}
+
+// apply 用一帧的检测结果更新轨迹，把轨迹 ID、轨迹帧数和轨迹事件写回响应；
+// 会话或引擎设置了区域规则时同时写入区域计数与区域事件，at 为帧时间
+func (ts *trackingSession) apply(resp *InferenceResponse, engineRules *zones.Config, at time.Time) {
+	dets := make([]tracking.Detection, len(resp.Results))
+	for i, r := range resp.Results {
+		dets[i] = tracking.Detection{Class: r.Name, Conf: float64(r.Confidence)}
+		if len(r.Box) == 4 {
+			dets[i].Box = tracking.Box{
+				X1: float64(r.Box[0].X), Y1: float64(r.Box[0].Y),
+				X2: float64(r.Box[2].X), Y2: float64(r.Box[2].Y),
+			}
+		}
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	assigned, events := ts.tracker.Update(dets)
+	for i, a := range assigned {
+		resp.Results[i].TrackId = a.TrackID
+		resp.Results[i].TrackAge = int32(a.Age)
+	}
+	var lost []int64
+	for _, e := range events {
+		monitor.TrackEvents.WithLabelValues(e.Type).Inc()
+		resp.TrackEvents = append(resp.TrackEvents, &TrackEvent{Type: e.Type, TrackId: e.TrackID, Name: e.Class, Age: int32(e.Age)})
+		if e.Type == tracking.EventLost {
+			lost = append(lost, e.TrackID)
+		}
+	}
+
+	rules := cmp.Or(ts.zoneRules, engineRules)
+	if rules == nil {
+		return
+	}
+	if ts.analyzed != rules {
+		ts.analyzer, ts.analyzed = zones.NewAnalyzer(*rules), rules
+	}
+	objs := zoneObjects(resp.Results)
+	resp.ZoneCounts = zoneCounts(rules.Count(objs))
+	resp.ZoneEvents = zoneEvents(ts.analyzer.Update(at, objs, lost))
+}
+
+func (s *Server) DeleteTrackingSession(ctx context.Context, req *DeleteTrackingSessionRequest) (*DeleteTrackingSessionResponse, error) {
+	monitor.GRPCTotal.Inc()
+	trackingMu.Lock()
+	_, exists := trackingSessions[req.SessionId]
+	delete(trackingSessions, req.SessionId)
+	trackingMu.Unlock()
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "tracking session %s not found", req.SessionId)
+	}
+	webhook.ForgetSession(req.SessionId)
+	logger.Log().Info("Deleted tracking session", zap.String("session", req.SessionId))
+	return &DeleteTrackingSessionResponse{Success: true, Message: "Successfully deleted tracking session"}, nil
+}
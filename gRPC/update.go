@@ -0,0 +1,160 @@
+package proto
+
+import (
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"context"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// outputFilter 是就地更新后在 Go 侧对推理结果追加的过滤：
+// 丢弃置信度低于 minConf 的结果，并按原生类别名重命名
+type outputFilter struct {
+	minConf float32
+	rename  map[string]string
+	names   []string
+}
+
+// config 返回引擎当前生效的配置
+func (d *WorkerID) config() *InitEngineRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.request
+}
+
+func (d *WorkerID) configRevision() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.revision
+}
+
+// filterResults 对推理结果应用就地更新的过滤条件，未更新过的引擎原样返回
+func (d *WorkerID) filterResults(results map[string][]iface.Result) map[string][]iface.Result {
+	f := d.filter.Load()
+	if f == nil {
+		return results
+	}
+	filtered := make(map[string][]iface.Result, len(results))
+	for class, resList := range results {
+		if name, ok := f.rename[class]; ok {
+			class = name
+		}
+		for _, res := range resList {
+			if res.Conf < f.minConf {
+				continue
+			}
+			filtered[class] = append(filtered[class], res)
+		}
+	}
+	return filtered
+}
+
+// inPlaceFilter 判断新配置能否只在 Go 侧生效：IoU 与输入尺寸不变、置信度不低于原生阈值、
+// 类别数不变。比较的是按类别名文件、模型清单与元数据解析后的参数。
+// 可以时返回对应的过滤条件（与原生配置一致时为 nil）
+func (d *WorkerID) inPlaceFilter(next *InitEngineRequest) (*outputFilter, bool) {
+	spec, err := resolveSpec(next)
+	if err != nil {
+		// 交给重新加载时报告错误
+		return nil, false
+	}
+	native := d.native
+	if spec.Iou != native.Iou || spec.InputSize != native.InputSize || spec.Confidence < native.Confidence {
+		return nil, false
+	}
+	if len(spec.Names) != len(native.Names) {
+		return nil, false
+	}
+	if spec.Confidence == native.Confidence && slices.Equal(spec.Names, native.Names) {
+		return nil, true
+	}
+	f := &outputFilter{minConf: spec.Confidence, names: slices.Clone(spec.Names)}
+	for i, name := range native.Names {
+		if spec.Names[i] != name {
+			if f.rename == nil {
+				f.rename = make(map[string]string)
+			}
+			f.rename[name] = spec.Names[i]
+		}
+	}
+	return f, true
+}
+
+func (s *Server) UpdateEngine(ctx context.Context, req *UpdateEngineRequest) (*UpdateEngineResponse, error) {
+	monitor.GRPCTotal.Inc()
+	swapMu.Lock()
+	defer swapMu.Unlock()
+	mapMu.RLock()
+	detector, exists := DSequences[req.Id]
+	if !exists {
+		err := engineNotFound(req.Id)
+		mapMu.RUnlock()
+		return nil, err
+	}
+	mapMu.RUnlock()
+	cfg := detector.config()
+	if cfg == nil {
+		return nil, fmt.Errorf("detector with ID %s has no recorded configuration to update", req.Id)
+	}
+	next := protobuf.Clone(cfg).(*InitEngineRequest)
+	if req.Confidence != nil {
+		next.Confidence = *req.Confidence
+	}
+	if req.Iou != nil {
+		next.Iou = *req.Iou
+	}
+	if req.InputSize != nil {
+		if *req.InputSize < 0 {
+			return nil, fmt.Errorf("input size cannot be negative, got %d", *req.InputSize)
+		}
+		next.InputSize = *req.InputSize
+	}
+	if len(req.Names) > 0 {
+		// 显式给出的类别名取代原来的类别名文件
+		next.Names = req.Names
+		next.NamesFile = ""
+	}
+	if err := validateInitRequest(next); err != nil {
+		return nil, err
+	}
+
+	reinitialized := false
+	if protobuf.Equal(next, cfg) {
+		// 配置没有变化
+	} else if f, ok := detector.inPlaceFilter(next); ok {
+		detector.filter.Store(f)
+		detector.mu.Lock()
+		detector.request = next
+		detector.revision++
+		detector.mu.Unlock()
+		saveRegistry()
+	} else {
+		swapped, err := swapEngine(req.Id, next, nil, defaultDrainTimeout)
+		if err != nil {
+			logger.Log().Error("Failed to update engine, keeping old configuration", zap.String("ID", req.Id), zap.Error(err))
+			return &UpdateEngineResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to update engine, old configuration kept: %v", err),
+			}, nil
+		}
+		detector = swapped
+		reinitialized = true
+	}
+	info, err := engineInfo(req.Id, detector)
+	if err != nil {
+		return nil, err
+	}
+	logger.Log().Info("Updated engine", zap.String("ID", req.Id), zap.Float32("Confidence", next.Confidence), zap.Float32("IoU", next.Iou),
+		zap.Int32("InputSize", next.InputSize), zap.Bool("reinitialized", reinitialized), zap.Int64("revision", info.ConfigRevision))
+	return &UpdateEngineResponse{
+		Success:       true,
+		Message:       "Successfully updated engine",
+		Reinitialized: reinitialized,
+		EngineInfo:    info,
+	}, nil
+}
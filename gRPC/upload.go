@@ -0,0 +1,243 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// partPrefix 是未完成上传的临时文件前缀，临时文件保留在仓库目录中以便续传
+const partPrefix = ".upload-"
+
+var (
+	uploading = make(map[string]bool)
+	uploadMu  sync.Mutex
+)
+
+// uploadReceiver 是 UploadModel 流中服务端用到的部分
+type uploadReceiver interface {
+	Recv() (*UploadFileRequest, error)
+}
+
+func partPath(name string) string {
+	return filepath.Join(modelDir, partPrefix+name+".part")
+}
+
+// uploadOffset 返回未完成上传已写入的字节数，没有未完成上传时为 0
+func uploadOffset(name string) (int64, error) {
+	if err := checkModelName(name); err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(partPath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// openPart 打开临时文件并定位到续传位置；offset 为 0 时重新开始上传
+func openPart(info *FileInfo) (*os.File, error) {
+	if err := os.MkdirAll(modelDir, 0o755); err != nil {
+		return nil, err
+	}
+	path := partPath(info.Name)
+	if info.Offset == 0 {
+		return os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	}
+	have, err := uploadOffset(info.Name)
+	if err != nil {
+		return nil, err
+	}
+	if have < info.Offset {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot resume %s from offset %d, server has %d bytes", info.Name, info.Offset, have)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// 丢弃 offset 之后可能只写了一部分的数据
+	if err := f.Truncate(info.Offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(info.Offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// receiveModel 把上传流写入临时文件，校验大小和 SHA-256 后原子地重命名到仓库中。
+// 传输中断时保留临时文件，客户端可以用 GetUploadOffset 查询进度后续传
+func receiveModel(stream uploadReceiver) (*UploadFileResponse, error) {
+	req, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := req.Data.(*UploadFileRequest_FileInfo)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "file not opened, please send file info first")
+	}
+	info := payload.FileInfo
+	if err := checkModelName(info.Name); err != nil {
+		return nil, err
+	}
+	if info.Size < 0 || info.Offset < 0 || (info.Size > 0 && info.Offset > info.Size) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid size %d or offset %d", info.Size, info.Offset)
+	}
+	if info.Sha256 != "" {
+		if b, err := hex.DecodeString(info.Sha256); err != nil || len(b) != sha256.Size {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid SHA-256 %q", info.Sha256)
+		}
+	}
+	if info.Manifest != "" {
+		if _, err := parseManifest([]byte(info.Manifest)); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	finalPath := filepath.Join(modelDir, info.Name)
+	if ids := modelEngines(finalPath); len(ids) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "model %s is in use by engines %s", info.Name, strings.Join(ids, ", "))
+	}
+
+	uploadMu.Lock()
+	if uploading[info.Name] {
+		uploadMu.Unlock()
+		return nil, status.Errorf(codes.Aborted, "model %s is already being uploaded", info.Name)
+	}
+	uploading[info.Name] = true
+	uploadMu.Unlock()
+	defer func() {
+		uploadMu.Lock()
+		delete(uploading, info.Name)
+		uploadMu.Unlock()
+	}()
+
+	outFile, err := openPart(info)
+	if err != nil {
+		return nil, err
+	}
+	written := info.Offset
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			outFile.Close()
+			logger.Log().Warn("Model upload interrupted", zap.String("name", info.Name), zap.Int64("received", written), zap.Error(err))
+			return nil, err
+		}
+		chunk, ok := req.Data.(*UploadFileRequest_ChunkData)
+		if !ok {
+			outFile.Close()
+			return nil, status.Errorf(codes.InvalidArgument, "file info can only be sent once")
+		}
+		if info.Size > 0 && written+int64(len(chunk.ChunkData)) > info.Size {
+			outFile.Close()
+			return nil, status.Errorf(codes.InvalidArgument, "upload exceeds declared size %d", info.Size)
+		}
+		n, writeErr := outFile.Write(chunk.ChunkData)
+		written += int64(n)
+		if writeErr != nil {
+			outFile.Close()
+			return nil, fmt.Errorf("failed to write chunk data: %v", writeErr)
+		}
+	}
+	if err := outFile.Sync(); err != nil {
+		outFile.Close()
+		return nil, err
+	}
+	if err := outFile.Close(); err != nil {
+		return nil, err
+	}
+	if info.Size > 0 && written != info.Size {
+		return nil, status.Errorf(codes.FailedPrecondition, "upload incomplete: received %d of %d bytes, resume from offset %d", written, info.Size, written)
+	}
+
+	path := partPath(info.Name)
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := fileSHA256(path, stat)
+	if err != nil {
+		return nil, err
+	}
+	if info.Sha256 != "" && !strings.EqualFold(sum, info.Sha256) {
+		// 校验失败说明已上传的数据有误，续传无意义，直接丢弃
+		os.Remove(path)
+		return nil, status.Errorf(codes.DataLoss, "SHA-256 mismatch for %s: expected %s, got %s", info.Name, info.Sha256, sum)
+	}
+	if err := os.Rename(path, finalPath); err != nil {
+		return nil, err
+	}
+	if info.Manifest != "" {
+		if err := writeManifest(finalPath, []byte(info.Manifest)); err != nil {
+			return nil, fmt.Errorf("model uploaded but failed to save manifest: %w", err)
+		}
+	}
+	hashMu.Lock()
+	if e, ok := hashCache[path]; ok {
+		hashCache[finalPath] = e
+		delete(hashCache, path)
+	}
+	hashMu.Unlock()
+	logger.Log().Info("Uploaded model", zap.String("name", info.Name), zap.Int64("size", written), zap.String("sha256", sum), zap.Int64("resumedFrom", info.Offset), zap.Bool("manifest", info.Manifest != ""))
+	return &UploadFileResponse{
+		Success:  true,
+		Message:  "File uploaded successfully",
+		FilePath: finalPath,
+		Size:     written,
+		Sha256:   sum,
+	}, nil
+}
+
+// writeManifest 先写临时文件再重命名，保存模型旁的清单
+func writeManifest(modelPath string, data []byte) error {
+	path := manifestPath(modelPath)
+	tmp := filepath.Join(filepath.Dir(path), partPrefix+filepath.Base(path)+".part")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (s *Server) UploadModel(stream DetectService_UploadModelServer) error {
+	monitor.GRPCTotal.Inc()
+	resp, err := receiveModel(stream)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+func (s *Server) GetUploadOffset(ctx context.Context, req *ModelRequest) (*UploadOffsetResponse, error) {
+	monitor.GRPCTotal.Inc()
+	offset, err := uploadOffset(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadOffsetResponse{Name: req.Name, Offset: offset}, nil
+}
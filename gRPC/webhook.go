@@ -0,0 +1,34 @@
+package proto
+
+import "OnnxDetServer/webhook"
+
+// publishFrame 把一次推理的结果交给 webhook 接收端求值
+func publishFrame(req *InferenceRequest, resp *InferenceResponse, streamID string) {
+	if !webhook.Enabled() {
+		return
+	}
+	webhook.Publish(webhookFrame(req, resp, streamID))
+}
+
+func webhookFrame(req *InferenceRequest, resp *InferenceResponse, streamID string) webhook.Frame {
+	f := webhook.Frame{
+		EngineID:  resp.EngineId,
+		Alias:     resp.Alias,
+		SessionID: req.SessionId,
+		StreamID:  streamID,
+		Counts:    make(map[string]int),
+	}
+	for _, r := range resp.Results {
+		f.Counts[r.Name]++
+	}
+	if len(resp.ZoneCounts) > 0 {
+		f.ZoneCounts = make(map[string]int, len(resp.ZoneCounts))
+		for _, c := range resp.ZoneCounts {
+			f.ZoneCounts[c.Zone] = int(c.Count)
+		}
+	}
+	for _, e := range resp.ZoneEvents {
+		f.ZoneEvents = append(f.ZoneEvents, webhook.ZoneEvent{Type: e.Type, Zone: e.Zone, TrackID: e.TrackId, Class: e.Name, Direction: e.Direction, DwellMs: e.DwellMs})
+	}
+	return f
+}
@@ -0,0 +1,100 @@
+package proto
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"OnnxDetServer/zones"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func zonePoint(p *Position) zones.Point {
+	return zones.Point{X: float64(p.GetX()), Y: float64(p.GetY())}
+}
+
+// zoneConfig 把请求中的区域规则转换为 zones.Config 并校验，没有规则时返回 nil
+func zoneConfig(c *ZoneConfig) (*zones.Config, error) {
+	if len(c.GetZones()) == 0 && len(c.GetLines()) == 0 {
+		return nil, nil
+	}
+	cfg := &zones.Config{}
+	for _, z := range c.Zones {
+		zone := zones.Zone{Name: z.Name, Classes: z.Classes, DwellAlert: time.Duration(z.DwellAlertMs) * time.Millisecond}
+		for _, p := range z.Polygon {
+			zone.Polygon = append(zone.Polygon, zonePoint(p))
+		}
+		cfg.Zones = append(cfg.Zones, zone)
+	}
+	for _, l := range c.Lines {
+		cfg.Lines = append(cfg.Lines, zones.Line{Name: l.Name, A: zonePoint(l.From), B: zonePoint(l.To), Classes: l.Classes})
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return cfg, nil
+}
+
+// zoneObjects 以检测框中心作为区域判断的参考点
+func zoneObjects(results []*SingleResult) []zones.Object {
+	objs := make([]zones.Object, len(results))
+	for i, r := range results {
+		objs[i] = zones.Object{TrackID: r.TrackId, Class: r.Name, Point: zonePoint(r.Center)}
+	}
+	return objs
+}
+
+func zoneCounts(counts []zones.Count) []*ZoneCount {
+	out := make([]*ZoneCount, len(counts))
+	for i, c := range counts {
+		classes := make(map[string]int32, len(c.Classes))
+		for name, n := range c.Classes {
+			classes[name] = int32(n)
+		}
+		out[i] = &ZoneCount{Zone: c.Zone, Count: int32(c.Total), Classes: classes}
+	}
+	return out
+}
+
+// zoneEvents 转换区域事件并累计到 Prometheus 指标
+func zoneEvents(events []zones.Event) []*ZoneEvent {
+	out := make([]*ZoneEvent, len(events))
+	for i, e := range events {
+		switch e.Type {
+		case zones.EventCross:
+			monitor.LineCrossings.WithLabelValues(e.Name, e.Class, e.Direction).Inc()
+		case zones.EventExit:
+			monitor.ZoneDwell.WithLabelValues(e.Name).Observe(e.Dwell.Seconds())
+			fallthrough
+		default:
+			monitor.ZoneEvents.WithLabelValues(e.Name, e.Class, e.Type).Inc()
+		}
+		out[i] = &ZoneEvent{Type: e.Type, Zone: e.Name, TrackId: e.TrackID, Name: e.Class, Direction: e.Direction, DwellMs: e.Dwell.Milliseconds()}
+	}
+	return out
+}
+
+// SetZones 设置或清除引擎的区域规则，热更新与重载后保留
+func (s *Server) SetZones(ctx context.Context, req *SetZonesRequest) (*SetZonesResponse, error) {
+	monitor.GRPCTotal.Inc()
+	mapMu.RLock()
+	detector, exists := DSequences[req.Id]
+	mapMu.RUnlock()
+	if !exists {
+		return nil, engineNotFound(req.Id)
+	}
+	rules, err := zoneConfig(req.Zones)
+	if err != nil {
+		return nil, err
+	}
+	detector.zoneRules.Store(rules)
+	if rules == nil {
+		logger.Log().Info("Cleared engine zones", zap.String("ID", req.Id))
+		return &SetZonesResponse{Success: true, Message: "Zones cleared"}, nil
+	}
+	logger.Log().Info("Set engine zones", zap.String("ID", req.Id), zap.Int("zones", len(rules.Zones)), zap.Int("lines", len(rules.Lines)))
+	return &SetZonesResponse{Success: true, Message: "Zones updated"}, nil
+}
@@ -50,3 +50,23 @@ type ImageData struct {
 	Height   int32
 	Channels int32
 }
+
+// Area 返回轴对齐检测框的面积
+func (b Box) Area() float32 {
+	return max(b.RB.X-b.LT.X, 0) * max(b.RB.Y-b.LT.Y, 0)
+}
+
+// IoU 返回两个轴对齐检测框的交并比
+func (b Box) IoU(o Box) float32 {
+	w := min(b.RB.X, o.RB.X) - max(b.LT.X, o.LT.X)
+	h := min(b.RB.Y, o.RB.Y) - max(b.LT.Y, o.LT.Y)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	inter := w * h
+	union := b.Area() + o.Area() - inter
+	if union <= 0 {
+		return 0
+	}
+	return inter / union
+}
@@ -0,0 +1,276 @@
+package isolation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+
+	iface "OnnxDetServer/interface"
+)
+
+// ChildFlag 是以引擎宿主子进程模式启动同一可执行文件时使用的命令行参数
+const ChildFlag = "--engine-host"
+
+// Factory 在子进程中创建一个新的 Backend 实例
+type Factory func() (iface.Backend, error)
+
+// childBackend 的所有原生调用（包括创建与销毁）都在同一个锁定 OS 线程的协程中依次执行，
+// 与父进程 worker 的线程模型一致
+type childBackend struct {
+	mu      sync.Mutex
+	closed  bool
+	backend iface.Backend
+	calls   chan func()
+}
+
+// newChildBackend 启动实例的处理协程，并在其中调用 factory 创建实例
+func newChildBackend(factory Factory) (*childBackend, error) {
+	b := &childBackend{calls: make(chan func())}
+	created := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		backend, err := func() (backend iface.Backend, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("engine host panic: %v", r)
+				}
+			}()
+			return factory()
+		}()
+		b.backend = backend
+		created <- err
+		if err != nil {
+			return
+		}
+		for fn := range b.calls {
+			fn()
+		}
+	}()
+	if err := <-created; err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// do 在处理协程中执行 fn 并等待完成，fn 中的 panic 转为错误返回
+func (b *childBackend) do(fn func(iface.Backend) ([]byte, error)) (payload []byte, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, fmt.Errorf("backend destroyed")
+	}
+	done := make(chan struct{})
+	b.calls <- func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("engine host panic: %v", r)
+			}
+		}()
+		payload, err = fn(b.backend)
+	}
+	<-done
+	return payload, err
+}
+
+// destroy 销毁实例并结束处理协程
+func (b *childBackend) destroy() {
+	_, _ = b.do(func(backend iface.Backend) ([]byte, error) {
+		backend.Destroy()
+		return nil, nil
+	})
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.calls)
+	}
+}
+
+type childServer struct {
+	factory Factory
+	writeMu sync.Mutex
+	w       io.Writer
+	mu      sync.Mutex
+	handles map[uint32]*childBackend
+}
+
+// ServeChild 在子进程中运行，从 r 读取父进程的请求帧，调用本地 Backend，
+// 并把应答写回 w。r 读到 EOF（父进程关闭管道）时销毁所有实例并返回 nil。
+func ServeChild(r io.Reader, w io.Writer, factory Factory) error {
+	s := &childServer{
+		factory: factory,
+		w:       w,
+		handles: make(map[uint32]*childBackend),
+	}
+	br := bufio.NewReaderSize(r, 64<<10)
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		s.destroyAll()
+	}()
+	for {
+		f, err := readFrame(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(f)
+		}()
+	}
+}
+
+// DialParent 在子进程中连接父进程准备的 Unix 域套接字，返回的连接交给 ServeChild
+func DialParent() (net.Conn, error) {
+	addr := os.Getenv(envHostAddr)
+	if addr == "" {
+		return nil, fmt.Errorf("engine host address is not set, the process must be started by the supervisor")
+	}
+	conn, err := net.DialTimeout("unix", addr, connectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to parent process: %w", err)
+	}
+	return conn, nil
+}
+
+func (s *childServer) reply(f frame, payload []byte, err error) {
+	out := frame{op: opReply, seq: f.seq, handle: f.handle, payload: payload}
+	if err != nil {
+		out.op = opError
+		out.payload = []byte(err.Error())
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = writeFrame(s.w, out)
+}
+
+func (s *childServer) lookup(handle uint32) (*childBackend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.handles[handle]
+	if !ok {
+		return nil, fmt.Errorf("backend handle %d not found", handle)
+	}
+	return b, nil
+}
+
+func (s *childServer) handle(f frame) {
+	if f.op == opCreate {
+		b, err := newChildBackend(s.factory)
+		if err != nil {
+			s.reply(f, nil, err)
+			return
+		}
+		s.mu.Lock()
+		s.handles[f.handle] = b
+		s.mu.Unlock()
+		s.reply(f, nil, nil)
+		return
+	}
+
+	b, err := s.lookup(f.handle)
+	if err != nil {
+		s.reply(f, nil, err)
+		return
+	}
+	if f.op == opDestroy {
+		b.destroy()
+		s.mu.Lock()
+		delete(s.handles, f.handle)
+		s.mu.Unlock()
+		s.reply(f, nil, nil)
+		return
+	}
+	payload, err := b.do(func(backend iface.Backend) ([]byte, error) {
+		return call(backend, f)
+	})
+	s.reply(f, payload, err)
+}
+
+// call 在实例的处理协程中执行一个请求帧，返回应答内容
+func call(backend iface.Backend, f frame) ([]byte, error) {
+	switch f.op {
+	case opLoad:
+		var req loadRequest
+		if err := json.Unmarshal(f.payload, &req); err != nil {
+			return nil, err
+		}
+		ok, loadErr := backend.LoadModel(req.ModelPath, req.namesConf(), req.Conf, req.Iou, req.UseGPU)
+		rep := loadReply{OK: ok}
+		if loadErr != nil {
+			rep.Error = loadErr.Error()
+		}
+		return mustJSON(rep), nil
+	case opSetInputSize:
+		size, err := decodeInt(f.payload)
+		if err != nil {
+			return nil, err
+		}
+		backend.SetInputSize(size)
+		return nil, nil
+	case opSetBlobName:
+		var req blobRequest
+		if err := json.Unmarshal(f.payload, &req); err != nil {
+			return nil, err
+		}
+		backend.SetBlobName(req.Input, req.Output)
+		return nil, nil
+	case opDetect:
+		img, err := decodeImage(f.payload)
+		if err != nil {
+			return nil, err
+		}
+		ret := backend.Detect(img)
+		rep := detectReply{Success: ret.Success}
+		switch v := ret.Data.(type) {
+		case map[string][]iface.Result:
+			rep.HasResults = true
+			rep.Results = v
+		case string:
+			rep.Message = v
+		case nil:
+		default:
+			rep.Message = fmt.Sprintf("unexpected data type in results: %T", v)
+		}
+		return mustJSON(rep), nil
+	case opConfig:
+		cfg := backend.CheckConfig()
+		rep := configReply{
+			ModelPath:   cfg.ModelPath,
+			NamesIsFile: cfg.Names.IsFile,
+			Conf:        cfg.Conf,
+			Iou:         cfg.Iou,
+			UseGPU:      cfg.UseGPU,
+		}
+		switch v := cfg.Names.Data.(type) {
+		case []string:
+			rep.Names = v
+		case string:
+			rep.NamesFile = v
+		}
+		return mustJSON(rep), nil
+	default:
+		return nil, fmt.Errorf("unknown op %d", f.op)
+	}
+}
+
+func (s *childServer) destroyAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for h, b := range s.handles {
+		b.destroy()
+		delete(s.handles, h)
+	}
+}
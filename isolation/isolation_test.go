@@ -0,0 +1,273 @@
+package isolation
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	iface "OnnxDetServer/interface"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMain 在被 ExecSpawner 启动时作为引擎宿主子进程运行
+func TestMain(m *testing.M) {
+	if os.Getenv(envHostAddr) != "" {
+		conn, err := DialParent()
+		if err != nil {
+			os.Exit(1)
+		}
+		// 模拟原生库向标准输出打印
+		fmt.Println("native library noise")
+		var loads atomic.Int32
+		_ = ServeChild(conn, conn, func() (iface.Backend, error) {
+			fmt.Println("more noise")
+			return &fakeBackend{loads: &loads}, nil
+		})
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type fakeBackend struct {
+	block     <-chan struct{}
+	loads     *atomic.Int32
+	modelPath string
+	names     []string
+	conf, iou float32
+	inputSize int
+}
+
+func (f *fakeBackend) LoadModel(modelPath string, names iface.NamesConf, conf float32, iou float32, useGPU bool) (bool, error) {
+	f.loads.Add(1)
+	f.modelPath = modelPath
+	f.names = names.Data.([]string)
+	f.conf, f.iou = conf, iou
+	return true, nil
+}
+
+func (f *fakeBackend) Detect(image iface.ImageData) iface.RetData {
+	if f.block != nil {
+		<-f.block
+	}
+	if f.modelPath == "" {
+		return iface.RetData{Success: false, Data: "Model not loaded"}
+	}
+	res := map[string][]iface.Result{}
+	res[f.names[0]] = []iface.Result{{
+		Conf:   0.9,
+		Box:    iface.Box{LT: iface.Position{X: 1, Y: 1}, RB: iface.Position{X: float32(image.Width), Y: float32(f.inputSize)}},
+		Center: iface.Position{X: 2, Y: 2},
+	}}
+	return iface.RetData{Success: true, Data: res}
+}
+
+func (f *fakeBackend) Destroy() { f.modelPath = "" }
+
+func (f *fakeBackend) CheckConfig() iface.EngineConfig {
+	return iface.EngineConfig{ModelPath: f.modelPath, Names: iface.NamesConf{Data: f.names}, Conf: f.conf, Iou: f.iou}
+}
+
+func (f *fakeBackend) SetInputSize(size int)                    { f.inputSize = size }
+func (f *fakeBackend) SetBlobName(inputName, outputName string) {}
+
+// pipeSpawner 在 goroutine 中运行 ServeChild 来模拟子进程，kill 模拟进程崩溃
+type pipeSpawner struct {
+	block  <-chan struct{}
+	mu     sync.Mutex
+	loads  atomic.Int32
+	spawns int
+	kills  []func() error
+}
+
+func (p *pipeSpawner) spawn() (*Process, error) {
+	parent, child := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeChild(child, child, func() (iface.Backend, error) {
+			return &fakeBackend{loads: &p.loads, block: p.block}, nil
+		})
+		_ = child.Close()
+	}()
+	kill := func() error { return child.Close() }
+	p.mu.Lock()
+	p.spawns++
+	p.kills = append(p.kills, kill)
+	p.mu.Unlock()
+	return &Process{Conn: parent, Kill: kill, Wait: func() error { return <-done }}, nil
+}
+
+func (p *pipeSpawner) spawnCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.spawns
+}
+
+func loadFake(t *testing.T, b iface.Backend) {
+	ok, err := b.LoadModel("models/fake.onnx", iface.NamesConf{Data: []string{"person"}}, 0.5, 0.4, false)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	b.SetInputSize(640)
+}
+
+func TestRemoteBackend_Detect(t *testing.T) {
+	sp := &pipeSpawner{}
+	sup := NewSupervisor(sp.spawn)
+	defer sup.Close()
+
+	b, err := sup.NewBackend("")
+	if !assert.NoError(t, err) {
+		return
+	}
+	loadFake(t, b)
+
+	ret := b.Detect(iface.ImageData{Data: []byte{1, 2, 3}, Width: 320, Height: 240, Channels: 3})
+	assert.True(t, ret.Success)
+	res, ok := ret.Data.(map[string][]iface.Result)
+	if assert.True(t, ok) && assert.Len(t, res["person"], 1) {
+		assert.Equal(t, float32(320), res["person"][0].Box.RB.X)
+		assert.Equal(t, float32(640), res["person"][0].Box.RB.Y)
+	}
+
+	cfg := b.CheckConfig()
+	assert.Equal(t, "models/fake.onnx", cfg.ModelPath)
+	assert.Equal(t, []string{"person"}, cfg.Names.Data)
+	assert.Equal(t, float32(0.5), cfg.Conf)
+}
+
+func TestSupervisor_RestartsCrashedHost(t *testing.T) {
+	sp := &pipeSpawner{}
+	sup := NewSupervisor(sp.spawn)
+	sup.RestartDelay = 10 * time.Millisecond
+	defer sup.Close()
+
+	b, err := sup.NewBackend("")
+	if !assert.NoError(t, err) {
+		return
+	}
+	loadFake(t, b)
+
+	sp.mu.Lock()
+	kill := sp.kills[0]
+	sp.mu.Unlock()
+	_ = kill()
+
+	img := iface.ImageData{Data: []byte{1}, Width: 10, Height: 10, Channels: 1}
+	assert.Eventually(t, func() bool {
+		ret := b.Detect(img)
+		if !ret.Success {
+			return false
+		}
+		res := ret.Data.(map[string][]iface.Result)
+		// 输入尺寸在重启后被重新设置
+		return len(res["person"]) == 1 && res["person"][0].Box.RB.Y == 640
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, sp.spawnCount())
+	assert.Equal(t, int32(2), sp.loads.Load())
+}
+
+func TestSupervisor_Groups(t *testing.T) {
+	sp := &pipeSpawner{}
+	sup := NewSupervisor(sp.spawn)
+	defer sup.Close()
+
+	a, err := sup.NewBackend("shared")
+	assert.NoError(t, err)
+	b, err := sup.NewBackend("shared")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sp.spawnCount())
+
+	_, err = sup.NewBackend("")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sp.spawnCount())
+
+	loadFake(t, a)
+	loadFake(t, b)
+	a.Destroy()
+	ret := b.Detect(iface.ImageData{Data: []byte{1}, Width: 1, Height: 1, Channels: 1})
+	assert.True(t, ret.Success)
+}
+
+func TestSupervisor_CallTimeoutKillsHost(t *testing.T) {
+	block := make(chan struct{})
+	sp := &pipeSpawner{block: block}
+	sup := NewSupervisor(sp.spawn)
+	sup.RestartDelay = 10 * time.Millisecond
+	sup.CallTimeout = 50 * time.Millisecond
+	defer sup.Close()
+
+	b, err := sup.NewBackend("")
+	if !assert.NoError(t, err) {
+		return
+	}
+	loadFake(t, b)
+	ret := b.Detect(iface.ImageData{Data: []byte{1}, Width: 1, Height: 1, Channels: 1})
+	assert.False(t, ret.Success)
+	assert.Contains(t, ret.Data, "did not reply")
+
+	// 卡住的子进程被结束并重启，模型重新加载后恢复推理
+	close(block)
+	assert.Eventually(t, func() bool {
+		return b.Detect(iface.ImageData{Data: []byte{1}, Width: 1, Height: 1, Channels: 1}).Success
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, sp.spawnCount())
+}
+
+func TestExecSpawner(t *testing.T) {
+	sup := NewSupervisor(ExecSpawner(os.Args[0]))
+	defer sup.Close()
+
+	// 子进程向标准输出打印的内容不影响通信
+	b, err := sup.NewBackend("")
+	if !assert.NoError(t, err) {
+		return
+	}
+	loadFake(t, b)
+	ret := b.Detect(iface.ImageData{Data: []byte{1}, Width: 8, Height: 8, Channels: 1})
+	if assert.True(t, ret.Success) {
+		assert.Len(t, ret.Data.(map[string][]iface.Result)["person"], 1)
+	}
+	b.Destroy()
+}
+
+func TestSupervisor_SlowStartDoesNotBlock(t *testing.T) {
+	sp := &pipeSpawner{}
+	gate := make(chan struct{})
+	var slow atomic.Bool
+	sup := NewSupervisor(func() (*Process, error) {
+		if slow.CompareAndSwap(false, true) {
+			<-gate
+		}
+		return sp.spawn()
+	})
+
+	// 第一个子进程启动卡住时，其他组的引擎仍可创建
+	first := make(chan error, 1)
+	go func() {
+		_, err := sup.NewBackend("slow")
+		first <- err
+	}()
+	assert.Eventually(t, slow.Load, time.Second, 5*time.Millisecond)
+	other, err := sup.NewBackend("other")
+	if assert.NoError(t, err) {
+		loadFake(t, other)
+	}
+
+	// Close 不等待启动中的子进程，启动完成后该引擎创建失败
+	closed := make(chan struct{})
+	go func() {
+		sup.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked behind a starting host")
+	}
+	close(gate)
+	assert.Error(t, <-first)
+}
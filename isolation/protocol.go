@@ -0,0 +1,183 @@
+package isolation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	iface "OnnxDetServer/interface"
+)
+
+// 帧格式（大端）：
+//
+//	| length uint32 | op uint8 | seq uint32 | handle uint32 | payload ... |
+//
+// length 为 length 字段之后的字节数。每个请求帧都会收到一个相同 seq 的
+// opReply 或 opError 帧；handle 标识子进程中的 Backend 实例。
+const (
+	opCreate byte = iota + 1
+	opLoad
+	opSetInputSize
+	opSetBlobName
+	opDetect
+	opConfig
+	opDestroy
+	opReply
+	opError
+)
+
+const (
+	headerSize   = 1 + 4 + 4
+	maxFrameSize = 256 << 20
+)
+
+type frame struct {
+	op      byte
+	seq     uint32
+	handle  uint32
+	payload []byte
+}
+
+func writeFrame(w io.Writer, f frame) error {
+	buf := make([]byte, 4+headerSize+len(f.payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(headerSize+len(f.payload)))
+	buf[4] = f.op
+	binary.BigEndian.PutUint32(buf[5:9], f.seq)
+	binary.BigEndian.PutUint32(buf[9:13], f.handle)
+	copy(buf[13:], f.payload)
+	_, err := w.Write(buf)
+	return err
+}
+
+func readFrame(r *bufio.Reader) (frame, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return frame{}, err
+	}
+	n := binary.BigEndian.Uint32(lenBuf[:])
+	if n < headerSize || n > maxFrameSize {
+		return frame{}, fmt.Errorf("invalid frame length %d", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return frame{}, err
+	}
+	return frame{
+		op:      buf[0],
+		seq:     binary.BigEndian.Uint32(buf[1:5]),
+		handle:  binary.BigEndian.Uint32(buf[5:9]),
+		payload: buf[9:],
+	}, nil
+}
+
+type loadRequest struct {
+	ModelPath   string   `json:"modelPath"`
+	NamesIsFile bool     `json:"namesIsFile"`
+	NamesFile   string   `json:"namesFile,omitempty"`
+	Names       []string `json:"names,omitempty"`
+	Conf        float32  `json:"conf"`
+	Iou         float32  `json:"iou"`
+	UseGPU      bool     `json:"useGpu"`
+}
+
+type loadReply struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type blobRequest struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+type detectReply struct {
+	Success    bool                      `json:"success"`
+	Message    string                    `json:"message,omitempty"`
+	HasResults bool                      `json:"hasResults"`
+	Results    map[string][]iface.Result `json:"results,omitempty"`
+}
+
+type configReply struct {
+	ModelPath   string   `json:"modelPath"`
+	NamesIsFile bool     `json:"namesIsFile"`
+	NamesFile   string   `json:"namesFile,omitempty"`
+	Names       []string `json:"names,omitempty"`
+	Conf        float32  `json:"conf"`
+	Iou         float32  `json:"iou"`
+	UseGPU      bool     `json:"useGpu"`
+}
+
+func newLoadRequest(modelPath string, names iface.NamesConf, conf, iou float32, useGPU bool) (loadRequest, error) {
+	req := loadRequest{ModelPath: modelPath, NamesIsFile: names.IsFile, Conf: conf, Iou: iou, UseGPU: useGPU}
+	switch v := names.Data.(type) {
+	case string:
+		if !names.IsFile {
+			return req, fmt.Errorf("names must be a slice or a file path")
+		}
+		req.NamesFile = v
+	case []string:
+		if names.IsFile {
+			return req, fmt.Errorf("names file must be a path")
+		}
+		req.Names = v
+	case nil:
+	default:
+		return req, fmt.Errorf("unsupported names type: %T", names.Data)
+	}
+	return req, nil
+}
+
+func (r loadRequest) namesConf() iface.NamesConf {
+	if r.NamesIsFile {
+		return iface.NamesConf{IsFile: true, Data: r.NamesFile}
+	}
+	names := r.Names
+	if names == nil {
+		names = []string{}
+	}
+	return iface.NamesConf{IsFile: false, Data: names}
+}
+
+func encodeImage(img iface.ImageData) []byte {
+	buf := make([]byte, 12+len(img.Data))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(img.Width))
+	binary.BigEndian.PutUint32(buf[4:8], uint32(img.Height))
+	binary.BigEndian.PutUint32(buf[8:12], uint32(img.Channels))
+	copy(buf[12:], img.Data)
+	return buf
+}
+
+func decodeImage(b []byte) (iface.ImageData, error) {
+	if len(b) < 12 {
+		return iface.ImageData{}, fmt.Errorf("image payload too short")
+	}
+	return iface.ImageData{
+		Width:    int32(binary.BigEndian.Uint32(b[0:4])),
+		Height:   int32(binary.BigEndian.Uint32(b[4:8])),
+		Channels: int32(binary.BigEndian.Uint32(b[8:12])),
+		Data:     b[12:],
+	}, nil
+}
+
+func encodeInt(v int) []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, uint32(int32(v)))
+	return buf
+}
+
+func decodeInt(b []byte) (int, error) {
+	if len(b) != 4 {
+		return 0, fmt.Errorf("invalid int payload")
+	}
+	return int(int32(binary.BigEndian.Uint32(b))), nil
+}
+
+func mustJSON(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,533 @@
+package isolation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	iface "OnnxDetServer/interface"
+	"OnnxDetServer/logger"
+
+	"go.uber.org/zap"
+)
+
+// Process 是父进程视角下的一个引擎宿主子进程
+type Process struct {
+	Conn io.ReadWriteCloser
+	Kill func() error
+	Wait func() error
+}
+
+// Spawner 启动一个新的引擎宿主进程
+type Spawner func() (*Process, error)
+
+// envHostAddr 是父进程为子进程准备的 Unix 域套接字路径，通过环境变量传给子进程
+const envHostAddr = "ONNXDET_ENGINE_HOST_ADDR"
+
+// connectTimeout 是等待子进程连回父进程的最长时间
+const connectTimeout = 30 * time.Second
+
+// ExecSpawner 以 ChildFlag 参数启动 path 指向的可执行文件。父子进程通过 Unix 域套接字通信，
+// 套接字位于只有当前用户可访问的临时目录中，子进程连上后即删除；通信不占用子进程的标准输出，
+// 原生库打印到标准输出的内容转到标准错误，不会破坏帧协议
+func ExecSpawner(path string, args ...string) Spawner {
+	return func() (*Process, error) {
+		dir, err := os.MkdirTemp("", "onnxdet-host-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create engine host socket directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return nil, err
+		}
+		sock := filepath.Join(dir, "host.sock")
+		ln, err := net.Listen("unix", sock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen for engine host: %w", err)
+		}
+		defer ln.Close()
+		cmd := exec.Command(path, append([]string{ChildFlag}, args...)...)
+		cmd.Env = append(os.Environ(), envHostAddr+"="+sock)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		exited := make(chan struct{})
+		var waitErr error
+		go func() {
+			waitErr = cmd.Wait()
+			close(exited)
+		}()
+		wait := func() error {
+			<-exited
+			return waitErr
+		}
+		conn, err := acceptChild(ln.(*net.UnixListener), exited)
+		if err != nil {
+			_ = cmd.Process.Kill()
+			_ = wait()
+			return nil, err
+		}
+		return &Process{Conn: conn, Kill: cmd.Process.Kill, Wait: wait}, nil
+	}
+}
+
+// acceptChild 等待子进程连回，子进程先退出或超时时返回错误
+func acceptChild(ln *net.UnixListener, exited <-chan struct{}) (net.Conn, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-exited:
+			_ = ln.Close()
+		case <-done:
+		}
+	}()
+	if err := ln.SetDeadline(time.Now().Add(connectTimeout)); err != nil {
+		return nil, err
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("engine host exited before connecting")
+		default:
+			return nil, fmt.Errorf("engine host did not connect: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+var errHostDown = errors.New("engine host process is not running")
+
+const shutdownGrace = 5 * time.Second
+
+// Supervisor 管理引擎宿主子进程：按组复用进程，进程崩溃后自动重启并重新加载模型
+type Supervisor struct {
+	spawn Spawner
+	// RestartDelay 是子进程退出后到重启之间的初始等待时间，失败时逐次翻倍
+	RestartDelay time.Duration
+	// MaxRestartDelay 是重启等待时间的上限
+	MaxRestartDelay time.Duration
+	// CallTimeout 是单次调用等待子进程应答的最长时间，超时视为子进程卡死，强制结束后按崩溃重启
+	CallTimeout time.Duration
+
+	mu     sync.Mutex
+	groups map[string]*host
+	hosts  map[*host]struct{}
+	closed bool
+}
+
+func NewSupervisor(spawn Spawner) *Supervisor {
+	return &Supervisor{
+		spawn:           spawn,
+		RestartDelay:    1 * time.Second,
+		MaxRestartDelay: 30 * time.Second,
+		CallTimeout:     2 * time.Minute,
+		groups:          make(map[string]*host),
+		hosts:           make(map[*host]struct{}),
+	}
+}
+
+// NewBackend 在子进程中创建一个 Backend。group 为空时引擎独占一个进程，
+// 否则同组引擎共享同一个进程。
+func (s *Supervisor) NewBackend(group string) (iface.Backend, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("supervisor is closed")
+	}
+	// 新进程先登记为启动中，在锁外启动，避免一个慢启动的子进程阻塞其他引擎的创建与 Close；
+	// 同组的后续请求等待这次启动的结果
+	h, ok := s.groups[group]
+	starting := group == "" || !ok
+	if starting {
+		h = &host{sup: s, group: group, backends: make(map[uint32]*RemoteBackend), ready: make(chan struct{})}
+		s.hosts[h] = struct{}{}
+		if group != "" {
+			s.groups[group] = h
+		}
+	}
+	s.mu.Unlock()
+	if starting {
+		if h.startErr = h.start(); h.startErr != nil {
+			s.forget(h)
+		}
+		close(h.ready)
+	}
+	<-h.ready
+	if h.startErr != nil {
+		return nil, h.startErr
+	}
+
+	h.mu.Lock()
+	h.nextHandle++
+	rb := &RemoteBackend{host: h, handle: h.nextHandle}
+	h.backends[rb.handle] = rb
+	h.mu.Unlock()
+	if _, err := h.call(opCreate, rb.handle, nil); err != nil {
+		h.remove(rb)
+		return nil, err
+	}
+	return rb, nil
+}
+
+// Close 关闭所有子进程
+func (s *Supervisor) Close() {
+	s.mu.Lock()
+	s.closed = true
+	hosts := make([]*host, 0, len(s.hosts))
+	for h := range s.hosts {
+		hosts = append(hosts, h)
+	}
+	s.mu.Unlock()
+	for _, h := range hosts {
+		h.shutdown()
+	}
+}
+
+func (s *Supervisor) forget(h *host) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.hosts, h)
+	if h.group != "" && s.groups[h.group] == h {
+		delete(s.groups, h.group)
+	}
+}
+
+type host struct {
+	sup   *Supervisor
+	group string
+	// ready 在首次启动结束时关闭，startErr 为启动错误
+	ready    chan struct{}
+	startErr error
+
+	mu         sync.Mutex
+	proc       *Process
+	alive      bool
+	closed     bool
+	seq        uint32
+	pending    map[uint32]chan frame
+	nextHandle uint32
+	backends   map[uint32]*RemoteBackend
+}
+
+func (h *host) start() error {
+	proc, err := h.sup.spawn()
+	if err != nil {
+		return fmt.Errorf("failed to start engine host: %w", err)
+	}
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		// 启动期间被关闭：子进程读到 EOF 后自行退出
+		_ = proc.Conn.Close()
+		go proc.Wait()
+		return fmt.Errorf("engine host is closed")
+	}
+	h.proc = proc
+	h.alive = true
+	h.pending = make(map[uint32]chan frame)
+	h.mu.Unlock()
+	go h.readLoop(proc)
+	return nil
+}
+
+func (h *host) readLoop(proc *Process) {
+	r := bufio.NewReaderSize(proc.Conn, 64<<10)
+	for {
+		f, err := readFrame(r)
+		if err != nil {
+			h.onExit(proc, err)
+			return
+		}
+		h.mu.Lock()
+		ch, ok := h.pending[f.seq]
+		delete(h.pending, f.seq)
+		h.mu.Unlock()
+		if ok {
+			ch <- f
+		}
+	}
+}
+
+func (h *host) onExit(proc *Process, readErr error) {
+	h.mu.Lock()
+	if h.proc != proc {
+		h.mu.Unlock()
+		return
+	}
+	h.alive = false
+	for seq, ch := range h.pending {
+		ch <- frame{op: opError, payload: []byte(errHostDown.Error())}
+		delete(h.pending, seq)
+	}
+	closed := h.closed
+	h.mu.Unlock()
+
+	_ = proc.Conn.Close()
+	waitErr := proc.Wait()
+	if closed {
+		return
+	}
+	logger.Log().Error("Engine host process exited unexpectedly",
+		zap.String("group", h.group), zap.NamedError("readError", readErr), zap.NamedError("exitError", waitErr))
+	go h.restart()
+}
+
+func (h *host) restart() {
+	delay := h.sup.RestartDelay
+	for {
+		time.Sleep(delay)
+		h.mu.Lock()
+		closed := h.closed
+		h.mu.Unlock()
+		if closed {
+			return
+		}
+		if err := h.start(); err != nil {
+			logger.Log().Error("Failed to restart engine host", zap.String("group", h.group), zap.Error(err))
+			delay *= 2
+			if delay > h.sup.MaxRestartDelay {
+				delay = h.sup.MaxRestartDelay
+			}
+			continue
+		}
+		break
+	}
+	h.mu.Lock()
+	backends := make([]*RemoteBackend, 0, len(h.backends))
+	for _, rb := range h.backends {
+		backends = append(backends, rb)
+	}
+	h.mu.Unlock()
+	for _, rb := range backends {
+		if err := rb.replay(); err != nil {
+			logger.Log().Error("Failed to reload engine after host restart",
+				zap.String("group", h.group), zap.Uint32("handle", rb.handle), zap.Error(err))
+			continue
+		}
+	}
+	logger.Log().Info("Engine host restarted", zap.String("group", h.group), zap.Int("engines", len(backends)))
+}
+
+func (h *host) call(op byte, handle uint32, payload []byte) ([]byte, error) {
+	h.mu.Lock()
+	if !h.alive {
+		h.mu.Unlock()
+		return nil, errHostDown
+	}
+	h.seq++
+	seq := h.seq
+	ch := make(chan frame, 1)
+	h.pending[seq] = ch
+	err := writeFrame(h.proc.Conn, frame{op: op, seq: seq, handle: handle, payload: payload})
+	if err != nil {
+		delete(h.pending, seq)
+		h.mu.Unlock()
+		return nil, fmt.Errorf("failed to write to engine host: %w", err)
+	}
+	proc := h.proc
+	h.mu.Unlock()
+	timer := time.NewTimer(h.sup.CallTimeout)
+	defer timer.Stop()
+	var f frame
+	select {
+	case f = <-ch:
+	case <-timer.C:
+		h.mu.Lock()
+		delete(h.pending, seq)
+		h.mu.Unlock()
+		logger.Log().Error("Engine host did not reply in time, killing it",
+			zap.String("group", h.group), zap.Uint32("handle", handle), zap.Duration("timeout", h.sup.CallTimeout))
+		if proc.Kill != nil {
+			_ = proc.Kill()
+		}
+		return nil, fmt.Errorf("engine host did not reply within %s", h.sup.CallTimeout)
+	}
+	if f.op == opError {
+		return nil, errors.New(string(f.payload))
+	}
+	return f.payload, nil
+}
+
+func (h *host) remove(rb *RemoteBackend) {
+	h.mu.Lock()
+	delete(h.backends, rb.handle)
+	empty := len(h.backends) == 0
+	h.mu.Unlock()
+	// 独占进程的引擎销毁后，进程随之退出
+	if empty && h.group == "" {
+		h.shutdown()
+	}
+}
+
+func (h *host) shutdown() {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	h.closed = true
+	proc := h.proc
+	alive := h.alive
+	h.mu.Unlock()
+	h.sup.forget(h)
+	if proc == nil || !alive {
+		return
+	}
+	// 关闭管道后子进程读到 EOF，销毁全部实例后自行退出；超时未退出则强制结束
+	_ = proc.Conn.Close()
+	go func() {
+		time.Sleep(shutdownGrace)
+		h.mu.Lock()
+		running := h.proc == proc && h.alive
+		h.mu.Unlock()
+		if running && proc.Kill != nil {
+			_ = proc.Kill()
+		}
+	}()
+}
+
+// RemoteBackend 是运行在子进程中的 Backend 在父进程中的代理，实现 iface.Backend
+type RemoteBackend struct {
+	host   *host
+	handle uint32
+
+	mu    sync.Mutex
+	setup []frame
+	load  loadRequest
+}
+
+func (rb *RemoteBackend) record(op byte, payload []byte) {
+	rb.mu.Lock()
+	rb.setup = append(rb.setup, frame{op: op, payload: payload})
+	rb.mu.Unlock()
+}
+
+// replay 在重启后的子进程中按原顺序重新创建实例并加载模型
+func (rb *RemoteBackend) replay() error {
+	if _, err := rb.host.call(opCreate, rb.handle, nil); err != nil {
+		return err
+	}
+	rb.mu.Lock()
+	setup := append([]frame(nil), rb.setup...)
+	rb.mu.Unlock()
+	for _, f := range setup {
+		payload, err := rb.host.call(f.op, rb.handle, f.payload)
+		if err != nil {
+			return err
+		}
+		if f.op == opLoad {
+			var rep loadReply
+			if err := json.Unmarshal(payload, &rep); err != nil {
+				return err
+			}
+			if rep.Error != "" {
+				return errors.New(rep.Error)
+			}
+		}
+	}
+	return nil
+}
+
+func (rb *RemoteBackend) LoadModel(modelPath string, names iface.NamesConf, conf float32, iou float32, useGPU bool) (bool, error) {
+	req, err := newLoadRequest(modelPath, names, conf, iou, useGPU)
+	if err != nil {
+		return false, err
+	}
+	payload := mustJSON(req)
+	out, err := rb.host.call(opLoad, rb.handle, payload)
+	if err != nil {
+		return false, err
+	}
+	var rep loadReply
+	if err := json.Unmarshal(out, &rep); err != nil {
+		return false, err
+	}
+	if rep.Error != "" {
+		return rep.OK, errors.New(rep.Error)
+	}
+	rb.mu.Lock()
+	rb.load = req
+	rb.mu.Unlock()
+	rb.record(opLoad, payload)
+	return rep.OK, nil
+}
+
+func (rb *RemoteBackend) Detect(image iface.ImageData) iface.RetData {
+	out, err := rb.host.call(opDetect, rb.handle, encodeImage(image))
+	if err != nil {
+		return iface.RetData{Success: false, Data: err.Error()}
+	}
+	var rep detectReply
+	if err := json.Unmarshal(out, &rep); err != nil {
+		return iface.RetData{Success: false, Data: err.Error()}
+	}
+	if rep.HasResults {
+		if rep.Results == nil {
+			rep.Results = map[string][]iface.Result{}
+		}
+		return iface.RetData{Success: rep.Success, Data: rep.Results}
+	}
+	return iface.RetData{Success: rep.Success, Data: rep.Message}
+}
+
+func (rb *RemoteBackend) Destroy() {
+	if _, err := rb.host.call(opDestroy, rb.handle, nil); err != nil && !errors.Is(err, errHostDown) {
+		logger.Log().Warn("Failed to destroy remote engine", zap.Uint32("handle", rb.handle), zap.Error(err))
+	}
+	rb.host.remove(rb)
+}
+
+// CheckConfig 优先向子进程查询；子进程不可用时返回父进程记录的加载参数
+func (rb *RemoteBackend) CheckConfig() iface.EngineConfig {
+	out, err := rb.host.call(opConfig, rb.handle, nil)
+	if err == nil {
+		var rep configReply
+		if json.Unmarshal(out, &rep) == nil {
+			names := iface.NamesConf{IsFile: rep.NamesIsFile, Data: rep.Names}
+			if rep.Names == nil {
+				names.Data = []string{}
+			}
+			if rep.NamesIsFile && rep.NamesFile != "" {
+				names.Data = rep.NamesFile
+			}
+			return iface.EngineConfig{UseGPU: rep.UseGPU, ModelPath: rep.ModelPath, Names: names, Conf: rep.Conf, Iou: rep.Iou}
+		}
+	}
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return iface.EngineConfig{
+		UseGPU:    rb.load.UseGPU,
+		ModelPath: rb.load.ModelPath,
+		Names:     rb.load.namesConf(),
+		Conf:      rb.load.Conf,
+		Iou:       rb.load.Iou,
+	}
+}
+
+func (rb *RemoteBackend) SetInputSize(size int) {
+	payload := encodeInt(size)
+	if _, err := rb.host.call(opSetInputSize, rb.handle, payload); err != nil {
+		logger.Log().Warn("Failed to set input size on remote engine", zap.Uint32("handle", rb.handle), zap.Error(err))
+	}
+	rb.record(opSetInputSize, payload)
+}
+
+func (rb *RemoteBackend) SetBlobName(inputName, outputName string) {
+	payload := mustJSON(blobRequest{Input: inputName, Output: outputName})
+	if _, err := rb.host.call(opSetBlobName, rb.handle, payload); err != nil {
+		logger.Log().Warn("Failed to set blob name on remote engine", zap.Uint32("handle", rb.handle), zap.Error(err))
+	}
+	rb.record(opSetBlobName, payload)
+}
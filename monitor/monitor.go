@@ -7,6 +7,7 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,6 +20,84 @@ var (
 	memUsage  prometheus.Gauge
 	cpuUsage  prometheus.Gauge
 	GRPCTotal prometheus.Counter
+	rssMB     atomic.Uint64
+)
+
+// 引擎相关指标在包初始化时创建，保证 prom 启动前被调用也不会为 nil
+var (
+	EngineEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "engine_evictions_total",
+		Help: "Total number of engines evicted, by reason",
+	}, []string{"reason"})
+
+	EngineMemoryEstimate = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "engine_memory_estimate_Megabytes",
+		Help: "Sum of per-engine memory estimates in Megabytes",
+	})
+
+	AliasRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alias_requests_total",
+		Help: "Total number of inference requests routed through an alias, by alias and version",
+	}, []string{"alias", "version"})
+
+	ShadowDetections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shadow_detections_total",
+		Help: "Detections compared between an engine and its shadow, by outcome (matched, extra, missing)",
+	}, []string{"engine", "shadow", "class", "outcome"})
+
+	ShadowConfidenceDelta = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "shadow_confidence_delta",
+		Help:    "Confidence of the shadow minus confidence of the engine for matched detections",
+		Buckets: prometheus.LinearBuckets(-0.5, 0.1, 11),
+	}, []string{"engine", "shadow", "class"})
+
+	StreamFrames = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stream_frames_total",
+		Help: "Frames received over StreamInference, by outcome (processed, dropped, failed)",
+	}, []string{"outcome"})
+
+	StreamLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "stream_frame_latency_seconds",
+		Help:    "Time from frame capture to result for StreamInference frames that carry a capture timestamp",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 10),
+	})
+
+	TrackEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "track_events_total",
+		Help: "Tracks created or lost by the server-side tracker, by event (new, lost)",
+	}, []string{"event"})
+
+	ZoneEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zone_events_total",
+		Help: "Tracks entering, leaving or overstaying a zone, by zone, class and event (enter, exit, dwell)",
+	}, []string{"zone", "class", "event"})
+
+	LineCrossings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "line_crossings_total",
+		Help: "Tracks crossing a directed line, by line, class and direction (forward, backward)",
+	}, []string{"line", "class", "direction"})
+
+	ZoneDwell = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "zone_dwell_seconds",
+		Help:    "Time a track stayed in a zone, observed when it leaves",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"zone"})
+
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "Webhook events by sink and outcome (delivered, retried, failed, dropped; overflow counts frames skipped because the sink was busy)",
+	}, []string{"sink", "outcome"})
+
+	WebhookQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "webhook_queue_depth",
+		Help: "Webhook events waiting in the on-disk queue, by sink",
+	}, []string{"sink"})
+
+	WebhookLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "webhook_request_seconds",
+		Help:    "Duration of webhook POST attempts, by sink",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 10),
+	}, []string{"sink"})
 )
 
 var srv *http.Server
@@ -40,7 +119,9 @@ func prom(port int) {
 		Help: "Total number of gRPC requests processed",
 	})
 
-	registry.MustRegister(memUsage, cpuUsage, GRPCTotal)
+	registry.MustRegister(memUsage, cpuUsage, GRPCTotal, EngineEvictions, EngineMemoryEstimate, AliasRequests,
+		ShadowDetections, ShadowConfidenceDelta, StreamFrames, StreamLatency, TrackEvents,
+		ZoneEvents, LineCrossings, ZoneDwell, WebhookDeliveries, WebhookQueueDepth, WebhookLatency)
 	http.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry}))
 	srv = &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
@@ -55,13 +136,32 @@ func prom(port int) {
 
 func CheckProcessInfo() {
 	MemInfo, _ := PID.MemoryInfo()
+	if MemInfo == nil {
+		return
+	}
 	var MemMB = MemInfo.RSS / 1024 / 1024
+	rssMB.Store(MemMB)
 	CPUPercent, _ := PID.CPUPercent()
 	CPUPercentFloat := math.Round(CPUPercent*100) / 100
 	memUsage.Set(float64(MemMB))
 	cpuUsage.Set(CPUPercentFloat)
 }
 
+// RSSMegabytes 返回 CheckProcessInfo 最近一次采样的进程 RSS（MB），未开始采样时为 0
+func RSSMegabytes() uint64 {
+	return rssMB.Load()
+}
+
+// SampleRSSMegabytes 立即采样当前进程 RSS（MB）
+func SampleRSSMegabytes() uint64 {
+	p := process.Process{Pid: int32(os.Getpid())}
+	info, err := p.MemoryInfo()
+	if err != nil || info == nil {
+		return 0
+	}
+	return info.RSS / 1024 / 1024
+}
+
 func GotPID() {
 	pid := os.Getpid()
 	i32Pid := int32(pid)
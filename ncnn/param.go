@@ -0,0 +1,203 @@
+// Package ncnn 解析 ncnn 的 .param 文本格式，列出网络的层和 blob，用于在加载前校验 blob 名
+package ncnn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// paramMagic 是文本 .param 文件的首行
+const paramMagic = "7767517"
+
+// Layer 是网络中的一层
+type Layer struct {
+	Type    string
+	Name    string
+	Bottoms []string
+	Tops    []string
+	// Params 为层参数，键为参数序号（数组参数为负序号），值为原始文本
+	Params map[string]string
+}
+
+// Param 是解析后的网络结构
+type Param struct {
+	Layers []Layer
+	// Blobs 按首次出现的顺序列出全部 blob
+	Blobs []string
+}
+
+// ParseFile 读取并解析 .param 文件
+func ParseFile(path string) (*Param, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse 解析 .param 文本，校验层数和 blob 数与文件头一致
+func Parse(r io.Reader) (*Param, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	next := func() ([]string, bool) {
+		for scanner.Scan() {
+			lineNo++
+			if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+				return fields, true
+			}
+		}
+		return nil, false
+	}
+	header, ok := next()
+	if !ok {
+		return nil, fmt.Errorf("empty param file")
+	}
+	if len(header) != 1 || header[0] != paramMagic {
+		return nil, fmt.Errorf("not a text ncnn param file: bad magic %q", strings.Join(header, " "))
+	}
+	counts, ok := next()
+	if !ok || len(counts) != 2 {
+		return nil, fmt.Errorf("line %d: expected layer and blob counts", lineNo)
+	}
+	layerCount, err1 := strconv.Atoi(counts[0])
+	blobCount, err2 := strconv.Atoi(counts[1])
+	if err1 != nil || err2 != nil || layerCount < 0 || blobCount < 0 {
+		return nil, fmt.Errorf("line %d: invalid layer and blob counts %q", lineNo, strings.Join(counts, " "))
+	}
+
+	p := &Param{Layers: make([]Layer, 0, layerCount)}
+	seen := make(map[string]bool, blobCount)
+	for {
+		fields, ok := next()
+		if !ok {
+			break
+		}
+		layer, err := parseLayer(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		for _, b := range slices.Concat(layer.Bottoms, layer.Tops) {
+			if !seen[b] {
+				seen[b] = true
+				p.Blobs = append(p.Blobs, b)
+			}
+		}
+		p.Layers = append(p.Layers, layer)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(p.Layers) != layerCount {
+		return nil, fmt.Errorf("header declares %d layers, found %d", layerCount, len(p.Layers))
+	}
+	if len(p.Blobs) != blobCount {
+		return nil, fmt.Errorf("header declares %d blobs, found %d", blobCount, len(p.Blobs))
+	}
+	return p, nil
+}
+
+func parseLayer(fields []string) (Layer, error) {
+	if len(fields) < 4 {
+		return Layer{}, fmt.Errorf("layer needs type, name, bottom count and top count")
+	}
+	layer := Layer{Type: fields[0], Name: fields[1], Params: make(map[string]string)}
+	bottoms, err1 := strconv.Atoi(fields[2])
+	tops, err2 := strconv.Atoi(fields[3])
+	if err1 != nil || err2 != nil || bottoms < 0 || tops < 0 {
+		return Layer{}, fmt.Errorf("layer %s has invalid bottom or top count", layer.Name)
+	}
+	rest := fields[4:]
+	if len(rest) < bottoms+tops {
+		return Layer{}, fmt.Errorf("layer %s declares %d bottoms and %d tops but lists %d blobs", layer.Name, bottoms, tops, len(rest))
+	}
+	layer.Bottoms = rest[:bottoms]
+	layer.Tops = rest[bottoms : bottoms+tops]
+	for _, kv := range rest[bottoms+tops:] {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return Layer{}, fmt.Errorf("layer %s has malformed param %q", layer.Name, kv)
+		}
+		layer.Params[k] = v
+	}
+	return layer, nil
+}
+
+// HasBlob 判断网络中是否存在该 blob
+func (p *Param) HasBlob(name string) bool {
+	return slices.Contains(p.Blobs, name)
+}
+
+// Inputs 返回可能的输入 blob：Input 层的输出，没有 Input 层时为只被消费、从未被产生的 blob
+func (p *Param) Inputs() []string {
+	var inputs []string
+	for _, l := range p.Layers {
+		if l.Type == "Input" {
+			inputs = append(inputs, l.Tops...)
+		}
+	}
+	if len(inputs) > 0 {
+		return inputs
+	}
+	produced := p.produced()
+	for _, l := range p.Layers {
+		for _, b := range l.Bottoms {
+			if !produced[b] && !slices.Contains(inputs, b) {
+				inputs = append(inputs, b)
+			}
+		}
+	}
+	return inputs
+}
+
+// Outputs 返回可能的输出 blob：被产生但没有被任何层消费的 blob
+func (p *Param) Outputs() []string {
+	consumed := make(map[string]bool)
+	for _, l := range p.Layers {
+		for _, b := range l.Bottoms {
+			consumed[b] = true
+		}
+	}
+	var outputs []string
+	for _, l := range p.Layers {
+		for _, b := range l.Tops {
+			if !consumed[b] && !slices.Contains(outputs, b) {
+				outputs = append(outputs, b)
+			}
+		}
+	}
+	return outputs
+}
+
+func (p *Param) produced() map[string]bool {
+	produced := make(map[string]bool)
+	for _, l := range p.Layers {
+		for _, b := range l.Tops {
+			produced[b] = true
+		}
+	}
+	return produced
+}
+
+// InputShape 返回 Input 层声明的宽、高、通道数（参数 0、1、2），未声明的维度为 0
+func (p *Param) InputShape(blob string) (w, h, c int) {
+	for _, l := range p.Layers {
+		if l.Type == "Input" && slices.Contains(l.Tops, blob) {
+			w, _ = strconv.Atoi(l.Params["0"])
+			h, _ = strconv.Atoi(l.Params["1"])
+			c, _ = strconv.Atoi(l.Params["2"])
+			return w, h, c
+		}
+	}
+	return 0, 0, 0
+}
@@ -0,0 +1,71 @@
+package ncnn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const yoloParam = `7767517
+6 7
+Input                    in0                      0 1 in0 0=640 1=640 2=3
+Convolution              conv_0                   1 1 in0 1 0=16 1=3 3=2 4=1 5=1 6=432 9=6
+Split                    splitncnn_0              1 2 1 2 3
+Convolution              conv_1                   1 1 2 4 0=32 1=3
+Concat                   cat_0                    2 1 3 4 5 0=0
+Permute                  permute_0                1 1 5 out0 0=1
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse(strings.NewReader(yoloParam))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, p.Layers, 6)
+	assert.Equal(t, Layer{Type: "Concat", Name: "cat_0", Bottoms: []string{"3", "4"}, Tops: []string{"5"}, Params: map[string]string{"0": "0"}}, p.Layers[4])
+	assert.Equal(t, []string{"in0", "1", "2", "3", "4", "5", "out0"}, p.Blobs)
+	assert.True(t, p.HasBlob("out0"))
+	assert.False(t, p.HasBlob("output"))
+	assert.Equal(t, []string{"in0"}, p.Inputs())
+	assert.Equal(t, []string{"out0"}, p.Outputs())
+	w, h, c := p.InputShape("in0")
+	assert.Equal(t, [3]int{640, 640, 3}, [3]int{w, h, c})
+}
+
+func TestInputsWithoutInputLayer(t *testing.T) {
+	p, err := Parse(strings.NewReader("7767517\n2 4\nConvolution conv 1 1 images a 0=8\nSigmoid sig 1 2 a b c\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"images"}, p.Inputs())
+		assert.Equal(t, []string{"b", "c"}, p.Outputs())
+		w, h, c := p.InputShape("images")
+		assert.Equal(t, [3]int{0, 0, 0}, [3]int{w, h, c})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, src := range map[string]string{
+		"empty":       "",
+		"bad magic":   "123\n1 1\nInput in 0 1 in\n",
+		"layer count": "7767517\n2 1\nInput in 0 1 in\n",
+		"blob count":  "7767517\n1 2\nInput in 0 1 in\n",
+		"short layer": "7767517\n1 1\nInput in 0\n",
+		"few blobs":   "7767517\n1 1\nInput in 0 2 in\n",
+		"bad param":   "7767517\n1 1\nInput in 0 1 in 0:640\n",
+	} {
+		_, err := Parse(strings.NewReader(src))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "yolo.param")
+	assert.NoError(t, os.WriteFile(path, []byte(yoloParam), 0o644))
+	p, err := ParseFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, p.Blobs, 7)
+	_, err = ParseFile(filepath.Join(t.TempDir(), "missing.param"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,523 @@
+// Package onnx 直接解码 ONNX 模型的 protobuf，读取输入输出、opset 和 metadata_props，
+// 不依赖原生推理库
+package onnx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ModelProto / GraphProto 等消息中用到的字段号，见 onnx.proto
+const (
+	modelIRVersion       = 1
+	modelProducerName    = 2
+	modelProducerVersion = 3
+	modelGraph           = 7
+	modelOpsetImport     = 8
+	modelMetadataProps   = 14
+
+	graphName        = 2
+	graphInitializer = 5
+	graphInput       = 11
+	graphOutput      = 12
+
+	tensorName = 8
+
+	valueInfoName = 1
+	valueInfoType = 2
+
+	typeTensor      = 1
+	tensorElemType  = 1
+	tensorShape     = 2
+	shapeDim        = 1
+	dimValue        = 1
+	dimParam        = 2
+	opsetDomain     = 1
+	opsetVersion    = 2
+	stringEntryKey  = 1
+	stringEntryVal  = 2
+	defaultDomain   = "ai.onnx"
+	metadataNames   = "names"
+	metadataImgsz   = "imgsz"
+	metadataStride  = "stride"
+	unknownElemType = "UNDEFINED"
+)
+
+// elemTypes 是 TensorProto.DataType 的名称
+var elemTypes = []string{
+	unknownElemType, "FLOAT", "UINT8", "INT8", "UINT16", "INT16", "INT32", "INT64", "STRING", "BOOL",
+	"FLOAT16", "DOUBLE", "UINT32", "UINT64", "COMPLEX64", "COMPLEX128", "BFLOAT16",
+}
+
+// Dim 是张量形状的一维，动态维度 Value 为 -1，Param 为符号名（可能为空）
+type Dim struct {
+	Value int64
+	Param string
+}
+
+// Tensor 是图的一个输入或输出
+type Tensor struct {
+	Name     string
+	ElemType string
+	Shape    []Dim
+}
+
+// Opset 是模型导入的算子集
+type Opset struct {
+	Domain  string
+	Version int64
+}
+
+// Model 是从 ONNX 文件中读取的模型信息
+type Model struct {
+	IRVersion       int64
+	ProducerName    string
+	ProducerVersion string
+	GraphName       string
+	Opsets          []Opset
+	Inputs          []Tensor
+	Outputs         []Tensor
+	Metadata        map[string]string
+}
+
+// ReadFile 流式解析 ONNX 文件：只把输入输出、opset、metadata_props 等小字段读入内存，
+// 算子节点与初始化器（权重）的内容直接跳过，不会把整个模型读入内存
+func ReadFile(path string) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := decode(&stream{r: bufio.NewReaderSize(f, 64<<10)})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse 解码 ModelProto，图的输入中属于初始化器（权重）的项会被去掉
+func Parse(data []byte) (*Model, error) {
+	return decode(&stream{r: bufio.NewReader(bytes.NewReader(data))})
+}
+
+func decode(s *stream) (*Model, error) {
+	m := &Model{Metadata: make(map[string]string)}
+	hasGraph := false
+	err := s.fields(-1, func(num protowire.Number, typ protowire.Type, n uint64) error {
+		switch {
+		case num == modelIRVersion && typ == protowire.VarintType:
+			m.IRVersion = int64(n)
+		case num == modelProducerName && typ == protowire.BytesType:
+			v, err := s.read(n)
+			m.ProducerName = string(v)
+			return err
+		case num == modelProducerVersion && typ == protowire.BytesType:
+			v, err := s.read(n)
+			m.ProducerVersion = string(v)
+			return err
+		case num == modelGraph && typ == protowire.BytesType:
+			hasGraph = true
+			if err := m.decodeGraph(s, s.pos+int64(n)); err != nil {
+				return &graphError{err}
+			}
+		case num == modelOpsetImport && typ == protowire.BytesType:
+			v, err := s.read(n)
+			if err != nil {
+				return err
+			}
+			op, err := parseOpset(v)
+			if err != nil {
+				return err
+			}
+			m.Opsets = append(m.Opsets, op)
+		case num == modelMetadataProps && typ == protowire.BytesType:
+			v, err := s.read(n)
+			if err != nil {
+				return err
+			}
+			k, val, err := parseStringEntry(v)
+			if err != nil {
+				return err
+			}
+			m.Metadata[k] = val
+		}
+		return nil
+	})
+	var gerr *graphError
+	if errors.As(err, &gerr) {
+		return nil, fmt.Errorf("invalid ONNX graph: %w", gerr.err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid ONNX model: %w", err)
+	}
+	if !hasGraph {
+		return nil, fmt.Errorf("invalid ONNX model: no graph")
+	}
+	return m, nil
+}
+
+// graphError 区分图内部的解码错误
+type graphError struct{ err error }
+
+func (e *graphError) Error() string { return e.err.Error() }
+
+// maxFieldSize 限制读入内存的单个字段大小，权重等大字段只跳过不读取
+const maxFieldSize = 64 << 20
+
+// stream 按顺序读取 protobuf 编码，pos 为已读取的字节数
+type stream struct {
+	r   *bufio.Reader
+	pos int64
+}
+
+func (s *stream) ReadByte() (byte, error) {
+	b, err := s.r.ReadByte()
+	if err == nil {
+		s.pos++
+	}
+	return b, err
+}
+
+func (s *stream) varint() (uint64, error) {
+	return binary.ReadUvarint(s)
+}
+
+// read 把接下来 n 字节读入内存
+func (s *stream) read(n uint64) ([]byte, error) {
+	if n > maxFieldSize {
+		return nil, fmt.Errorf("field of %d bytes is too large", n)
+	}
+	b := make([]byte, n)
+	k, err := io.ReadFull(s.r, b)
+	s.pos += int64(k)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+func (s *stream) skip(n int64) error {
+	k, err := s.r.Discard(int(n))
+	s.pos += int64(k)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// fields 依次回调消息中的每个字段，直到读到 end（end < 0 时直到输入结束）。
+// varint 字段的值在 n 中；length-delimited 字段的长度在 n 中，回调可以用 read 读取内容
+// 或用 fields 继续解码，未读取的部分自动跳过
+func (s *stream) fields(end int64, fn func(num protowire.Number, typ protowire.Type, n uint64) error) error {
+	for end < 0 || s.pos < end {
+		tag, err := s.varint()
+		if err == io.EOF && end < 0 {
+			return nil
+		}
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		num, typ := protowire.DecodeTag(tag)
+		if num < protowire.MinValidNumber {
+			return fmt.Errorf("invalid field number %d", num)
+		}
+		switch typ {
+		case protowire.VarintType:
+			n, err := s.varint()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			if err := fn(num, typ, n); err != nil {
+				return err
+			}
+		case protowire.BytesType:
+			n, err := s.varint()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			fieldEnd := s.pos + int64(n)
+			if int64(n) < 0 || fieldEnd < s.pos || (end >= 0 && fieldEnd > end) {
+				return io.ErrUnexpectedEOF
+			}
+			if err := fn(num, typ, n); err != nil {
+				return err
+			}
+			if s.pos > fieldEnd {
+				return fmt.Errorf("field %d overruns its length", num)
+			}
+			if err := s.skip(fieldEnd - s.pos); err != nil {
+				return err
+			}
+		case protowire.Fixed32Type:
+			if err := s.skip(4); err != nil {
+				return err
+			}
+		case protowire.Fixed64Type:
+			if err := s.skip(8); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported wire type %d", typ)
+		}
+	}
+	if s.pos != end {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// walk 依次回调消息的每个字段；varint 字段的值在 n 中，length-delimited 字段的内容在 v 中
+func walk(data []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error) error {
+	for len(data) > 0 {
+		num, typ, tagLen := protowire.ConsumeTag(data)
+		if tagLen < 0 {
+			return protowire.ParseError(tagLen)
+		}
+		data = data[tagLen:]
+		var v []byte
+		var n uint64
+		var l int
+		switch typ {
+		case protowire.VarintType:
+			n, l = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			v, l = protowire.ConsumeBytes(data)
+		default:
+			l = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if l < 0 {
+			return protowire.ParseError(l)
+		}
+		data = data[l:]
+		if err := fn(num, typ, v, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseOpset(data []byte) (Opset, error) {
+	var op Opset
+	err := walk(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch {
+		case num == opsetDomain && typ == protowire.BytesType:
+			op.Domain = string(v)
+		case num == opsetVersion && typ == protowire.VarintType:
+			op.Version = int64(n)
+		}
+		return nil
+	})
+	if op.Domain == "" {
+		op.Domain = defaultDomain
+	}
+	return op, err
+}
+
+func parseStringEntry(data []byte) (key, value string, err error) {
+	err = walk(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch {
+		case num == stringEntryKey && typ == protowire.BytesType:
+			key = string(v)
+		case num == stringEntryVal && typ == protowire.BytesType:
+			value = string(v)
+		}
+		return nil
+	})
+	return key, value, err
+}
+
+// decodeGraph 解码 GraphProto：初始化器只读取名称，算子节点整体跳过
+func (m *Model) decodeGraph(s *stream, end int64) error {
+	var inputs []Tensor
+	initializers := make(map[string]bool)
+	err := s.fields(end, func(num protowire.Number, typ protowire.Type, n uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case graphName:
+			v, err := s.read(n)
+			m.GraphName = string(v)
+			return err
+		case graphInitializer:
+			return s.fields(s.pos+int64(n), func(num protowire.Number, typ protowire.Type, n uint64) error {
+				if num == tensorName && typ == protowire.BytesType {
+					v, err := s.read(n)
+					initializers[string(v)] = true
+					return err
+				}
+				return nil
+			})
+		case graphInput, graphOutput:
+			v, err := s.read(n)
+			if err != nil {
+				return err
+			}
+			t, err := parseValueInfo(v)
+			if err != nil {
+				return err
+			}
+			if num == graphInput {
+				inputs = append(inputs, t)
+			} else {
+				m.Outputs = append(m.Outputs, t)
+			}
+		}
+		return nil
+	})
+	// IR 版本 4 之前的模型把权重也列为图的输入
+	for _, t := range inputs {
+		if !initializers[t.Name] {
+			m.Inputs = append(m.Inputs, t)
+		}
+	}
+	return err
+}
+
+func parseValueInfo(data []byte) (Tensor, error) {
+	t := Tensor{ElemType: unknownElemType}
+	err := walk(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case valueInfoName:
+			t.Name = string(v)
+		case valueInfoType:
+			return walk(v, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+				if num == typeTensor && typ == protowire.BytesType {
+					return t.parseTensorType(v)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return t, err
+}
+
+func (t *Tensor) parseTensorType(data []byte) error {
+	return walk(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch {
+		case num == tensorElemType && typ == protowire.VarintType:
+			t.ElemType = unknownElemType
+			if n < uint64(len(elemTypes)) {
+				t.ElemType = elemTypes[n]
+			}
+		case num == tensorShape && typ == protowire.BytesType:
+			t.Shape = make([]Dim, 0)
+			return walk(v, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+				if num != shapeDim || typ != protowire.BytesType {
+					return nil
+				}
+				d := Dim{Value: -1}
+				err := walk(v, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+					switch {
+					case num == dimValue && typ == protowire.VarintType:
+						d.Value = int64(n)
+					case num == dimParam && typ == protowire.BytesType:
+						d.Param = string(v)
+					}
+					return nil
+				})
+				t.Shape = append(t.Shape, d)
+				return err
+			})
+		}
+		return nil
+	})
+}
+
+// Opset 返回默认算子集（ai.onnx）的版本，未导入时返回 0
+func (m *Model) Opset() int64 {
+	for _, op := range m.Opsets {
+		if op.Domain == defaultDomain {
+			return op.Version
+		}
+	}
+	return 0
+}
+
+// namePattern 匹配 Ultralytics 以 Python dict 字面量写入的类别名，如 {0: 'person', 1: "people's"}
+var namePattern = regexp.MustCompile(`(\d+)\s*:\s*(?:'((?:[^'\\]|\\.)*)'|"((?:[^"\\]|\\.)*)")`)
+
+// Names 从 metadata_props 的 names 中按类别序号读取类别名，没有该项时返回 nil
+func (m *Model) Names() ([]string, error) {
+	raw, ok := m.Metadata[metadataNames]
+	if !ok {
+		return nil, nil
+	}
+	byIdx := make(map[int]string)
+	maxIdx := -1
+	for _, match := range namePattern.FindAllStringSubmatch(raw, -1) {
+		idx, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid class index %q in names metadata", match[1])
+		}
+		name := match[2]
+		if name == "" {
+			name = match[3]
+		}
+		byIdx[idx] = strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\\`, `\`).Replace(name)
+		maxIdx = max(maxIdx, idx)
+	}
+	if len(byIdx) == 0 {
+		return nil, fmt.Errorf("cannot parse names metadata %q", raw)
+	}
+	if len(byIdx) != maxIdx+1 {
+		return nil, fmt.Errorf("names metadata has %d classes but the largest index is %d", len(byIdx), maxIdx)
+	}
+	names := make([]string, maxIdx+1)
+	for idx, name := range byIdx {
+		names[idx] = name
+	}
+	return names, nil
+}
+
+// Stride 返回 metadata_props 中的 stride，没有时返回 0
+func (m *Model) Stride() int {
+	stride, _ := strconv.Atoi(strings.TrimSpace(m.Metadata[metadataStride]))
+	return stride
+}
+
+// InputSize 返回模型的输入边长：优先使用 metadata_props 的 imgsz，其次使用第一个 NCHW 输入的静态高宽，
+// 高宽不同时取较大值，无法确定时返回 0
+func (m *Model) InputSize() int {
+	var dims []int
+	for _, s := range strings.FieldsFunc(m.Metadata[metadataImgsz], func(r rune) bool {
+		return r == '[' || r == ']' || r == '(' || r == ')' || r == ',' || r == ' '
+	}) {
+		if v, err := strconv.Atoi(s); err == nil && v > 0 {
+			dims = append(dims, v)
+		}
+	}
+	if len(dims) == 0 && len(m.Inputs) > 0 && len(m.Inputs[0].Shape) == 4 {
+		for _, d := range m.Inputs[0].Shape[2:] {
+			if d.Value > 0 {
+				dims = append(dims, int(d.Value))
+			}
+		}
+	}
+	if len(dims) == 0 {
+		return 0
+	}
+	return slices.Max(dims)
+}
@@ -0,0 +1,153 @@
+package onnx
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func bytesField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func varintField(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func valueInfo(name string, elemType uint64, dims ...any) []byte {
+	var shape []byte
+	for _, d := range dims {
+		var dim []byte
+		switch d := d.(type) {
+		case int:
+			dim = varintField(nil, dimValue, uint64(d))
+		case string:
+			dim = bytesField(nil, dimParam, []byte(d))
+		}
+		shape = bytesField(shape, shapeDim, dim)
+	}
+	tensor := varintField(nil, tensorElemType, elemType)
+	tensor = bytesField(tensor, tensorShape, shape)
+	info := bytesField(nil, valueInfoName, []byte(name))
+	return bytesField(info, valueInfoType, bytesField(nil, typeTensor, tensor))
+}
+
+func entry(k, v string) []byte {
+	return bytesField(bytesField(nil, stringEntryKey, []byte(k)), stringEntryVal, []byte(v))
+}
+
+// testModel 构造一个类似 Ultralytics 导出的最小模型
+func testModel(metadata map[string]string) []byte {
+	graph := bytesField(nil, graphName, []byte("main_graph"))
+	weight := bytesField(nil, tensorName, []byte("conv.weight"))
+	weight = bytesField(weight, 9, make([]byte, 64)) // raw_data
+	graph = bytesField(graph, graphInitializer, weight)
+	graph = bytesField(graph, graphInput, valueInfo("images", 1, "batch", 3, 640, 640))
+	graph = bytesField(graph, graphInput, valueInfo("conv.weight", 1, 16, 3, 3, 3))
+	graph = bytesField(graph, graphOutput, valueInfo("output0", 1, 1, 84, 8400))
+
+	m := varintField(nil, modelIRVersion, 8)
+	m = bytesField(m, modelProducerName, []byte("pytorch"))
+	m = bytesField(m, modelProducerVersion, []byte("2.1.0"))
+	m = bytesField(m, modelGraph, graph)
+	m = bytesField(m, modelOpsetImport, varintField(nil, opsetVersion, 17))
+	m = bytesField(m, modelOpsetImport, varintField(bytesField(nil, opsetDomain, []byte("com.microsoft")), opsetVersion, 1))
+	for k, v := range metadata {
+		m = bytesField(m, modelMetadataProps, entry(k, v))
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	m, err := Parse(testModel(map[string]string{
+		"names":  `{0: 'person', 1: "people's", 2: 'traffic light'}`,
+		"imgsz":  "[640, 480]",
+		"stride": "32",
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(8), m.IRVersion)
+	assert.Equal(t, "pytorch", m.ProducerName)
+	assert.Equal(t, "main_graph", m.GraphName)
+	assert.Equal(t, []Opset{{Domain: "ai.onnx", Version: 17}, {Domain: "com.microsoft", Version: 1}}, m.Opsets)
+	assert.Equal(t, int64(17), m.Opset())
+	// 权重不作为输入返回
+	assert.Equal(t, []Tensor{{Name: "images", ElemType: "FLOAT", Shape: []Dim{{Value: -1, Param: "batch"}, {Value: 3}, {Value: 640}, {Value: 640}}}}, m.Inputs)
+	assert.Equal(t, []Tensor{{Name: "output0", ElemType: "FLOAT", Shape: []Dim{{Value: 1}, {Value: 84}, {Value: 8400}}}}, m.Outputs)
+
+	names, err := m.Names()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"person", "people's", "traffic light"}, names)
+	assert.Equal(t, 640, m.InputSize())
+	assert.Equal(t, 32, m.Stride())
+}
+
+func TestWithoutMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plain.onnx")
+	assert.NoError(t, os.WriteFile(path, testModel(nil), 0o644))
+	m, err := ReadFile(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	names, err := m.Names()
+	assert.NoError(t, err)
+	assert.Nil(t, names)
+	// 没有 imgsz 时使用输入的静态高宽
+	assert.Equal(t, 640, m.InputSize())
+	assert.Equal(t, 0, m.Stride())
+}
+
+func TestReadFileSkipsWeights(t *testing.T) {
+	// 权重远大于读取时分配的内存，说明初始化器的内容没有被读入
+	const weightSize = 32 << 20
+	graph := bytesField(nil, graphName, []byte("big"))
+	weight := bytesField(nil, tensorName, []byte("conv.weight"))
+	weight = bytesField(weight, 9, make([]byte, weightSize))
+	graph = bytesField(graph, graphInitializer, weight)
+	graph = bytesField(graph, graphInput, valueInfo("images", 1, 1, 3, 320, 320))
+	graph = bytesField(graph, graphInput, valueInfo("conv.weight", 1, 16, 3, 3, 3))
+	model := bytesField(varintField(nil, modelIRVersion, 3), modelGraph, graph)
+	path := filepath.Join(t.TempDir(), "big.onnx")
+	assert.NoError(t, os.WriteFile(path, model, 0o644))
+	model = nil
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	m, err := ReadFile(path)
+	runtime.ReadMemStats(&after)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(weightSize/4))
+	assert.Equal(t, "big", m.GraphName)
+	if assert.Len(t, m.Inputs, 1) {
+		assert.Equal(t, "images", m.Inputs[0].Name)
+	}
+	assert.Equal(t, 320, m.InputSize())
+}
+
+func TestNamesErrors(t *testing.T) {
+	m := &Model{Metadata: map[string]string{"names": "{0: 'a', 2: 'c'}"}}
+	_, err := m.Names()
+	assert.ErrorContains(t, err, "largest index is 2")
+	m.Metadata["names"] = "['a', 'b']"
+	_, err = m.Names()
+	assert.ErrorContains(t, err, "cannot parse")
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse([]byte("not a model"))
+	assert.Error(t, err)
+	_, err = Parse(varintField(nil, modelIRVersion, 8))
+	assert.ErrorContains(t, err, "no graph")
+	truncated := testModel(nil)
+	_, err = Parse(truncated[:len(truncated)-3])
+	assert.Error(t, err)
+}
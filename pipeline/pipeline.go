@@ -0,0 +1,214 @@
+// Package pipeline 解析并执行 YAML 定义的检测流水线（DAG）：检测、按类别裁剪、分类、过滤、合并，
+// 结果按原图坐标嵌套返回
+package pipeline
+
+import (
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 阶段类型
+const (
+	StageDetect   = "detect"
+	StageCrop     = "crop"
+	StageClassify = "classify"
+	StageFilter   = "filter"
+	StageMerge    = "merge"
+)
+
+// Stage 是流水线中的一个阶段
+type Stage struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Engine 为引擎 ID 或别名，detect / classify 阶段使用
+	Engine string `yaml:"engine"`
+	// Input 为上游阶段名；detect 阶段为空时对整张原图推理
+	Input string `yaml:"input"`
+	// Inputs 为 merge 阶段合并的上游阶段
+	Inputs []string `yaml:"inputs"`
+	// Classes 限定 crop / filter 阶段处理的类别，为空表示全部
+	Classes []string `yaml:"classes"`
+	// MinConfidence 为 filter 阶段保留结果的最低置信度
+	MinConfidence float32 `yaml:"minConfidence"`
+	// Padding 为 crop 阶段按检测框宽高比例向外扩展的边距
+	Padding float32 `yaml:"padding"`
+}
+
+// Definition 是 YAML 中的流水线定义
+type Definition struct {
+	Name   string  `yaml:"name"`
+	Stages []Stage `yaml:"stages"`
+	// Output 为输出阶段名，为空时使用最后一个阶段
+	Output string `yaml:"output"`
+}
+
+// Pipeline 是校验过、按拓扑顺序排列阶段的流水线
+type Pipeline struct {
+	Definition
+	Source []byte
+	order  []*Stage
+	byName map[string]*Stage
+}
+
+// Parse 解析并校验 YAML 流水线定义
+func Parse(data []byte) (*Pipeline, error) {
+	var def Definition
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline: %w", err)
+	}
+	p, err := New(def)
+	if err != nil {
+		return nil, err
+	}
+	p.Source = slices.Clone(data)
+	return p, nil
+}
+
+// New 校验流水线定义：阶段名唯一、引用的上游阶段存在且类型匹配、没有环
+func New(def Definition) (*Pipeline, error) {
+	if def.Name == "" {
+		return nil, fmt.Errorf("pipeline name cannot be empty")
+	}
+	if len(def.Stages) == 0 {
+		return nil, fmt.Errorf("pipeline %s has no stages", def.Name)
+	}
+	p := &Pipeline{Definition: def, byName: make(map[string]*Stage, len(def.Stages))}
+	for i := range p.Stages {
+		s := &p.Stages[i]
+		if s.Name == "" {
+			return nil, fmt.Errorf("stage %d has no name", i)
+		}
+		if _, dup := p.byName[s.Name]; dup {
+			return nil, fmt.Errorf("duplicate stage name %s", s.Name)
+		}
+		p.byName[s.Name] = s
+	}
+	for i := range p.Stages {
+		if err := p.check(&p.Stages[i]); err != nil {
+			return nil, fmt.Errorf("stage %s: %w", p.Stages[i].Name, err)
+		}
+	}
+	if p.Output == "" {
+		p.Output = p.Stages[len(p.Stages)-1].Name
+	}
+	out, ok := p.byName[p.Output]
+	if !ok {
+		return nil, fmt.Errorf("output stage %s not found", p.Output)
+	}
+	if out.Type == StageCrop {
+		return nil, fmt.Errorf("output stage %s must produce detections, not crops", p.Output)
+	}
+	if err := p.sort(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// upstream 返回阶段引用的上游阶段名
+func (s *Stage) upstream() []string {
+	if s.Type == StageMerge {
+		return s.Inputs
+	}
+	if s.Input == "" {
+		return nil
+	}
+	return []string{s.Input}
+}
+
+func (p *Pipeline) check(s *Stage) error {
+	needsEngine := s.Type == StageDetect || s.Type == StageClassify
+	switch s.Type {
+	case StageDetect, StageCrop, StageClassify, StageFilter:
+		if len(s.Inputs) > 0 {
+			return fmt.Errorf("only merge stages take inputs, use input")
+		}
+	case StageMerge:
+		if len(s.Inputs) == 0 {
+			return fmt.Errorf("merge stage needs inputs")
+		}
+	default:
+		return fmt.Errorf("unknown stage type %q", s.Type)
+	}
+	if needsEngine && s.Engine == "" {
+		return fmt.Errorf("%s stage needs an engine", s.Type)
+	}
+	if !needsEngine && s.Engine != "" {
+		return fmt.Errorf("%s stage does not use an engine", s.Type)
+	}
+	if s.Type != StageDetect && s.Type != StageMerge && s.Input == "" {
+		return fmt.Errorf("%s stage needs an input", s.Type)
+	}
+	if s.Padding < 0 {
+		return fmt.Errorf("padding cannot be negative")
+	}
+	for _, name := range s.upstream() {
+		up, ok := p.byName[name]
+		if !ok {
+			return fmt.Errorf("input stage %s not found", name)
+		}
+		wantCrop := s.Type == StageDetect || s.Type == StageClassify
+		if wantCrop && up.Type != StageCrop {
+			return fmt.Errorf("%s stage input %s must be a crop stage", s.Type, name)
+		}
+		if !wantCrop && up.Type == StageCrop {
+			return fmt.Errorf("%s stage input %s must produce detections, not crops", s.Type, name)
+		}
+	}
+	return nil
+}
+
+// sort 按拓扑顺序排列阶段，存在环时返回错误
+func (p *Pipeline) sort() error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(p.Stages))
+	var visit func(s *Stage) error
+	visit = func(s *Stage) error {
+		switch state[s.Name] {
+		case visiting:
+			return fmt.Errorf("pipeline %s has a cycle through stage %s", p.Name, s.Name)
+		case done:
+			return nil
+		}
+		state[s.Name] = visiting
+		for _, name := range s.upstream() {
+			if err := visit(p.byName[name]); err != nil {
+				return err
+			}
+		}
+		state[s.Name] = done
+		p.order = append(p.order, s)
+		return nil
+	}
+	for i := range p.Stages {
+		if err := visit(&p.Stages[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Engines 返回流水线引用的全部引擎 ID 或别名
+func (p *Pipeline) Engines() []string {
+	var engines []string
+	for _, s := range p.Stages {
+		if s.Engine != "" && !slices.Contains(engines, s.Engine) {
+			engines = append(engines, s.Engine)
+		}
+	}
+	return engines
+}
+
+// StageNames 按定义顺序返回阶段名
+func (p *Pipeline) StageNames() []string {
+	names := make([]string, len(p.Stages))
+	for i, s := range p.Stages {
+		names[i] = s.Name
+	}
+	return names
+}
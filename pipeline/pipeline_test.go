@@ -0,0 +1,170 @@
+package pipeline
+
+import (
+	iface "OnnxDetServer/interface"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const vehiclePlates = `
+name: vehicle-plates
+stages:
+  - name: vehicles
+    type: detect
+    engine: vehicle-detector
+  - name: cars
+    type: crop
+    input: vehicles
+    classes: [car]
+  - name: car-type
+    type: classify
+    input: cars
+    engine: type-classifier
+  - name: plates
+    type: detect
+    input: cars
+    engine: plate-detector
+  - name: confident-plates
+    type: filter
+    input: plates
+    minConfidence: 0.5
+  - name: out
+    type: merge
+    inputs: [vehicles, car-type, confident-plates]
+`
+
+func box(x1, y1, x2, y2, conf float32) iface.Result {
+	return iface.Result{Conf: conf, Box: iface.Box{LT: iface.Position{X: x1, Y: y1}, RB: iface.Position{X: x2, Y: y2}}}
+}
+
+func TestRunPipeline(t *testing.T) {
+	p, err := Parse([]byte(vehiclePlates))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.ElementsMatch(t, []string{"vehicle-detector", "type-classifier", "plate-detector"}, p.Engines())
+
+	img := iface.ImageData{Data: make([]byte, 100*80*3), Width: 100, Height: 80, Channels: 3}
+	// 标记像素 (10,10)，用于确认裁剪区域
+	img.Data[(10*100+10)*3] = 7
+	detect := func(engine string, in iface.ImageData) (map[string][]iface.Result, error) {
+		switch engine {
+		case "vehicle-detector":
+			assert.Equal(t, int32(100), in.Width)
+			return map[string][]iface.Result{
+				"car":    {box(10, 10, 50, 40, 0.9)},
+				"person": {box(60, 10, 70, 40, 0.8)},
+			}, nil
+		case "plate-detector":
+			assert.Equal(t, int32(40), in.Width)
+			assert.Equal(t, int32(30), in.Height)
+			assert.Equal(t, byte(7), in.Data[0])
+			return map[string][]iface.Result{"plate": {box(5, 20, 15, 25, 0.7), box(0, 0, 2, 2, 0.1)}}, nil
+		case "type-classifier":
+			return map[string][]iface.Result{"sedan": {box(0, 0, 40, 30, 0.6)}, "truck": {box(0, 0, 40, 30, 0.3)}}, nil
+		}
+		return nil, fmt.Errorf("unknown engine %s", engine)
+	}
+	roots, err := p.Run(detect, img)
+	if !assert.NoError(t, err) || !assert.Len(t, roots, 2) {
+		return
+	}
+	car, person := roots[0], roots[1]
+	assert.Equal(t, "car", car.Class)
+	assert.Equal(t, "person", person.Class)
+	assert.Empty(t, person.Children)
+	if assert.Len(t, car.Labels, 1) {
+		assert.Equal(t, Label{Stage: "car-type", Class: "sedan", Conf: 0.6}, car.Labels[0])
+	}
+	if assert.Len(t, car.Children, 1) {
+		plate := car.Children[0]
+		assert.Equal(t, "plates", plate.Stage)
+		assert.Equal(t, [4]float32{15, 30, 25, 35}, [4]float32{plate.X1, plate.Y1, plate.X2, plate.Y2})
+	}
+
+	_, err = p.Run(func(string, iface.ImageData) (map[string][]iface.Result, error) {
+		return nil, fmt.Errorf("engine down")
+	}, img)
+	assert.ErrorContains(t, err, "stage vehicles: engine down")
+
+	// 宽高通道的乘积超出 int32 时不能溢出成小数值而通过校验
+	_, err = p.Run(detect, iface.ImageData{Data: make([]byte, 16), Width: 65536, Height: 65536, Channels: 3})
+	assert.ErrorContains(t, err, "image data is invalid")
+}
+
+func TestClassifyDoesNotModifyUpstream(t *testing.T) {
+	const src = `
+name: labels
+stages:
+  - {name: vehicles, type: detect, engine: det}
+  - {name: cars, type: crop, input: vehicles}
+  - {name: car-type, type: classify, input: cars, engine: type}
+  - {name: car-color, type: classify, input: cars, engine: color}
+  - {name: out, type: merge, inputs: [%s]}
+`
+	img := iface.ImageData{Data: make([]byte, 100*80*3), Width: 100, Height: 80, Channels: 3}
+	detect := func(engine string, in iface.ImageData) (map[string][]iface.Result, error) {
+		switch engine {
+		case "det":
+			return map[string][]iface.Result{"car": {box(10, 10, 50, 40, 0.9)}}, nil
+		case "type":
+			return map[string][]iface.Result{"sedan": {box(0, 0, 40, 30, 0.6)}}, nil
+		case "color":
+			return map[string][]iface.Result{"red": {box(0, 0, 40, 30, 0.7)}}, nil
+		}
+		return nil, fmt.Errorf("unknown engine %s", engine)
+	}
+	run := func(inputs string) []*Detection {
+		p, err := Parse([]byte(fmt.Sprintf(src, inputs)))
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		roots, err := p.Run(detect, img)
+		assert.NoError(t, err)
+		return roots
+	}
+
+	// 每个分类阶段只给自己的副本加标签，上游检测结果和其他分类阶段的结果不受影响
+	if roots := run("vehicles"); assert.Len(t, roots, 1) {
+		assert.Empty(t, roots[0].Labels)
+	}
+	if roots := run("car-type"); assert.Len(t, roots, 1) {
+		assert.Equal(t, []Label{{Stage: "car-type", Class: "sedan", Conf: 0.6}}, roots[0].Labels)
+	}
+	// 合并时同一目标的副本合为一个结果，标签取并集
+	if roots := run("vehicles, car-type, car-color"); assert.Len(t, roots, 1) {
+		assert.Equal(t, []Label{{Stage: "car-type", Class: "sedan", Conf: 0.6}, {Stage: "car-color", Class: "red", Conf: 0.7}}, roots[0].Labels)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"cycle": `name: p
+stages:
+  - {name: a, type: merge, inputs: [b]}
+  - {name: b, type: filter, input: a}`,
+		"input stage missing not found": `name: p
+stages:
+  - {name: a, type: filter, input: missing}`,
+		"must be a crop stage": `name: p
+stages:
+  - {name: a, type: detect, engine: e}
+  - {name: b, type: detect, engine: e, input: a}`,
+		"must produce detections": `name: p
+stages:
+  - {name: a, type: detect, engine: e}
+  - {name: b, type: crop, input: a}`,
+		"needs an engine": `name: p
+stages:
+  - {name: a, type: detect}`,
+		"unknown stage type": `name: p
+stages:
+  - {name: a, type: track}`,
+	}
+	for want, def := range cases {
+		_, err := Parse([]byte(def))
+		assert.ErrorContains(t, err, want)
+	}
+}
@@ -0,0 +1,270 @@
+package pipeline
+
+import (
+	iface "OnnxDetServer/interface"
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Detector 用引擎 ID 或别名对图片推理，返回按类别分组的结果
+type Detector func(engine string, img iface.ImageData) (map[string][]iface.Result, error)
+
+// Label 是分类阶段附加到检测结果上的类别
+type Label struct {
+	Stage string
+	Class string
+	Conf  float32
+}
+
+// Detection 是流水线中的一个检测结果，坐标均为原图坐标
+type Detection struct {
+	Stage          string
+	Class          string
+	Conf           float32
+	X1, Y1, X2, Y2 float32
+	Labels         []Label
+	// Parent 为裁剪出该结果所在区域的上游检测结果，对整张原图检测时为 nil
+	Parent *Detection
+	// Children 在 Run 返回时填充，为输出中以该结果为最近祖先的结果
+	Children []*Detection
+	// origin 为复制出该结果的原始检测结果，合并与组装树时副本与原始结果视为同一个目标
+	origin *Detection
+}
+
+// key 返回标识同一个目标的原始检测结果
+func (d *Detection) key() *Detection {
+	if d.origin != nil {
+		return d.origin
+	}
+	return d
+}
+
+// clone 复制检测结果以附加标签，上游阶段的结果保持不变
+func (d *Detection) clone() *Detection {
+	c := *d
+	c.Labels = slices.Clone(d.Labels)
+	c.Children = nil
+	c.origin = d.key()
+	return &c
+}
+
+// region 是裁剪阶段输出的原图区域
+type region struct {
+	source     *Detection
+	x, y, w, h int
+}
+
+type stageOutput struct {
+	dets    []*Detection
+	regions []region
+}
+
+// Run 按拓扑顺序执行流水线，返回输出阶段结果组成的树
+func (p *Pipeline) Run(detect Detector, img iface.ImageData) ([]*Detection, error) {
+	if img.Width <= 0 || img.Height <= 0 || img.Channels <= 0 || len(img.Data) < int(img.Width)*int(img.Height)*int(img.Channels) {
+		return nil, fmt.Errorf("image data is invalid")
+	}
+	outputs := make(map[string]*stageOutput, len(p.order))
+	for _, s := range p.order {
+		out, err := p.runStage(s, outputs, detect, img)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", s.Name, err)
+		}
+		outputs[s.Name] = out
+	}
+	return nest(outputs[p.Output].dets), nil
+}
+
+func (p *Pipeline) runStage(s *Stage, outputs map[string]*stageOutput, detect Detector, img iface.ImageData) (*stageOutput, error) {
+	switch s.Type {
+	case StageDetect:
+		regions := []region{{x: 0, y: 0, w: int(img.Width), h: int(img.Height)}}
+		if s.Input != "" {
+			regions = outputs[s.Input].regions
+		}
+		dets, err := detectRegions(s, regions, detect, img)
+		return &stageOutput{dets: dets}, err
+	case StageCrop:
+		return &stageOutput{regions: cropRegions(s, outputs[s.Input].dets, img)}, nil
+	case StageClassify:
+		dets, err := classifyRegions(s, outputs[s.Input].regions, detect, img)
+		return &stageOutput{dets: dets}, err
+	case StageFilter:
+		var dets []*Detection
+		for _, d := range outputs[s.Input].dets {
+			if d.Conf >= s.MinConfidence && (len(s.Classes) == 0 || slices.Contains(s.Classes, d.Class)) {
+				dets = append(dets, d)
+			}
+		}
+		return &stageOutput{dets: dets}, nil
+	case StageMerge:
+		// 同一目标在不同输入中的副本合并为一个结果，标签取并集；需要合并标签时复制，不修改输入阶段的结果
+		var dets []*Detection
+		index := make(map[*Detection]int)
+		merged := make(map[*Detection]bool)
+		for _, name := range s.Inputs {
+			for _, d := range outputs[name].dets {
+				i, ok := index[d.key()]
+				if !ok {
+					index[d.key()] = len(dets)
+					dets = append(dets, d)
+					continue
+				}
+				for _, l := range d.Labels {
+					if slices.Contains(dets[i].Labels, l) {
+						continue
+					}
+					if !merged[dets[i]] {
+						dets[i] = dets[i].clone()
+						merged[dets[i]] = true
+					}
+					dets[i].Labels = append(dets[i].Labels, l)
+				}
+			}
+		}
+		return &stageOutput{dets: dets}, nil
+	}
+	return nil, fmt.Errorf("unknown stage type %q", s.Type)
+}
+
+// forEachRegion 并发地对每个区域的裁剪图推理
+func forEachRegion(engine string, regions []region, detect Detector, img iface.ImageData) ([]map[string][]iface.Result, error) {
+	results := make([]map[string][]iface.Result, len(regions))
+	errs := make([]error, len(regions))
+	var wg sync.WaitGroup
+	for i, r := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = detect(engine, Crop(img, r.x, r.y, r.w, r.h))
+		}()
+	}
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
+
+func detectRegions(s *Stage, regions []region, detect Detector, img iface.ImageData) ([]*Detection, error) {
+	results, err := forEachRegion(s.Engine, regions, detect, img)
+	if err != nil {
+		return nil, err
+	}
+	var dets []*Detection
+	for i, r := range regions {
+		for _, class := range sortedClasses(results[i]) {
+			for _, res := range results[i][class] {
+				dets = append(dets, &Detection{
+					Stage:  s.Name,
+					Class:  class,
+					Conf:   res.Conf,
+					X1:     res.Box.LT.X + float32(r.x),
+					Y1:     res.Box.LT.Y + float32(r.y),
+					X2:     res.Box.RB.X + float32(r.x),
+					Y2:     res.Box.RB.Y + float32(r.y),
+					Parent: r.source,
+				})
+			}
+		}
+	}
+	return dets, nil
+}
+
+// classifyRegions 对每个裁剪区域取置信度最高的类别，作为标签附加到区域对应检测结果的副本上
+func classifyRegions(s *Stage, regions []region, detect Detector, img iface.ImageData) ([]*Detection, error) {
+	results, err := forEachRegion(s.Engine, regions, detect, img)
+	if err != nil {
+		return nil, err
+	}
+	var dets []*Detection
+	labeled := make(map[*Detection]*Detection)
+	for i, r := range regions {
+		var best *Label
+		for _, class := range sortedClasses(results[i]) {
+			for _, res := range results[i][class] {
+				if best == nil || res.Conf > best.Conf {
+					best = &Label{Stage: s.Name, Class: class, Conf: res.Conf}
+				}
+			}
+		}
+		if best == nil {
+			continue
+		}
+		d, ok := labeled[r.source]
+		if !ok {
+			d = r.source.clone()
+			labeled[r.source] = d
+			dets = append(dets, d)
+		}
+		d.Labels = append(d.Labels, *best)
+	}
+	return dets, nil
+}
+
+// cropRegions 把选中类别的检测框按边距扩展并裁剪到图片范围内
+func cropRegions(s *Stage, dets []*Detection, img iface.ImageData) []region {
+	var regions []region
+	for _, d := range dets {
+		if len(s.Classes) > 0 && !slices.Contains(s.Classes, d.Class) {
+			continue
+		}
+		padX, padY := (d.X2-d.X1)*s.Padding, (d.Y2-d.Y1)*s.Padding
+		x1 := max(int(d.X1-padX), 0)
+		y1 := max(int(d.Y1-padY), 0)
+		x2 := min(int(d.X2+padX+0.5), int(img.Width))
+		y2 := min(int(d.Y2+padY+0.5), int(img.Height))
+		if x2 <= x1 || y2 <= y1 {
+			continue
+		}
+		regions = append(regions, region{source: d, x: x1, y: y1, w: x2 - x1, h: y2 - y1})
+	}
+	return regions
+}
+
+func sortedClasses(results map[string][]iface.Result) []string {
+	classes := make([]string, 0, len(results))
+	for class := range results {
+		classes = append(classes, class)
+	}
+	slices.SortFunc(classes, cmp.Compare[string])
+	return classes
+}
+
+// nest 把结果挂到输出中最近的祖先下，没有祖先在输出中的结果作为顶层返回
+func nest(dets []*Detection) []*Detection {
+	in := make(map[*Detection]*Detection, len(dets))
+	for _, d := range dets {
+		in[d.key()] = d
+		d.Children = nil
+	}
+	var roots []*Detection
+	for _, d := range dets {
+		parent := d.Parent
+		for parent != nil && in[parent.key()] == nil {
+			parent = parent.Parent
+		}
+		if parent == nil {
+			roots = append(roots, d)
+		} else {
+			p := in[parent.key()]
+			p.Children = append(p.Children, d)
+		}
+	}
+	return roots
+}
+
+// Crop 复制图片中的一个矩形区域（按行主序、通道交错的像素数据）
+func Crop(img iface.ImageData, x, y, w, h int) iface.ImageData {
+	if x == 0 && y == 0 && w == int(img.Width) && h == int(img.Height) {
+		return img
+	}
+	c := int(img.Channels)
+	stride := int(img.Width) * c
+	data := make([]byte, 0, w*h*c)
+	for row := y; row < y+h; row++ {
+		start := row*stride + x*c
+		data = append(data, img.Data[start:start+w*c]...)
+	}
+	return iface.ImageData{Data: data, Width: int32(w), Height: int32(h), Channels: img.Channels}
+}
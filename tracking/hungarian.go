@@ -0,0 +1,81 @@
+package tracking
+
+import "math"
+
+// Hungarian 求解最小代价指派，cost[i][j] 为第 i 行指派给第 j 列的代价，
+// 返回每行指派到的列，未指派的行为 -1。行数可以大于或小于列数
+func Hungarian(cost [][]float64) []int {
+	rows := len(cost)
+	if rows == 0 {
+		return nil
+	}
+	cols := len(cost[0])
+	assign := make([]int, rows)
+	for i := range assign {
+		assign[i] = -1
+	}
+	if cols == 0 {
+		return assign
+	}
+	// 补成 n×n 方阵，补齐的行列代价为 0
+	n := max(rows, cols)
+	a := func(i, j int) float64 {
+		if i < rows && j < cols {
+			return cost[i][j]
+		}
+		return 0
+	}
+	// 势函数 + 最短增广路（O(n^3)），下标从 1 开始，0 为虚拟列
+	u := make([]float64, n+1)
+	v := make([]float64, n+1)
+	p := make([]int, n+1)
+	way := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, n+1)
+		used := make([]bool, n+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		for {
+			used[j0] = true
+			i0, delta, j1 := p[j0], math.Inf(1), 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				cur := a(i0-1, j-1) - u[i0] - v[j]
+				if cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+	for j := 1; j <= n; j++ {
+		if i := p[j] - 1; i >= 0 && i < rows && j-1 < cols {
+			assign[i] = j - 1
+		}
+	}
+	return assign
+}
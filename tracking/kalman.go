@@ -0,0 +1,85 @@
+package tracking
+
+// 匀速运动模型的噪声参数，与检测框尺寸成比例
+const (
+	stdPosition = 1.0 / 20
+	stdVelocity = 1.0 / 160
+)
+
+// axis 是单个坐标分量（位置与速度）的卡尔曼滤波状态
+type axis struct {
+	x, v          float64
+	pxx, pxv, pvv float64
+}
+
+func newAxis(x, scale float64) axis {
+	return axis{
+		x:   x,
+		pxx: sq(2 * stdPosition * scale),
+		pvv: sq(10 * stdVelocity * scale),
+	}
+}
+
+// predict 按匀速模型外推一帧
+func (a *axis) predict(scale float64) {
+	a.x += a.v
+	pxx := a.pxx + 2*a.pxv + a.pvv
+	pxv := a.pxv + a.pvv
+	a.pxx = pxx + sq(stdPosition*scale)
+	a.pxv = pxv
+	a.pvv += sq(stdVelocity * scale)
+}
+
+// update 用观测值 z 校正状态
+func (a *axis) update(z, scale float64) {
+	s := a.pxx + sq(stdPosition*scale)
+	kx, kv := a.pxx/s, a.pxv/s
+	residual := z - a.x
+	a.x += kx * residual
+	a.v += kv * residual
+	pxx := (1 - kx) * a.pxx
+	pxv := (1 - kx) * a.pxv
+	a.pvv -= kv * a.pxv
+	a.pxx, a.pxv = pxx, pxv
+}
+
+// kalman 对检测框中心与宽高做匀速卡尔曼滤波。各分量的运动与噪声相互独立，
+// 因此 8 维状态（cx, cy, w, h 及其速度）的滤波可以拆成 4 个 2 维滤波
+type kalman struct {
+	cx, cy, w, h axis
+}
+
+func newKalman(b Box) kalman {
+	cx, cy, w, h := b.cxcywh()
+	return kalman{cx: newAxis(cx, h), cy: newAxis(cy, h), w: newAxis(w, h), h: newAxis(h, h)}
+}
+
+func (k *kalman) predict() {
+	scale := k.h.x
+	k.cx.predict(scale)
+	k.cy.predict(scale)
+	k.w.predict(scale)
+	k.h.predict(scale)
+	// 宽高不能为负
+	k.w.x, k.h.x = max(k.w.x, 1), max(k.h.x, 1)
+}
+
+func (k *kalman) update(b Box) {
+	cx, cy, w, h := b.cxcywh()
+	scale := k.h.x
+	k.cx.update(cx, scale)
+	k.cy.update(cy, scale)
+	k.w.update(w, scale)
+	k.h.update(h, scale)
+}
+
+func (k *kalman) box() Box {
+	return Box{
+		X1: k.cx.x - k.w.x/2,
+		Y1: k.cy.x - k.h.x/2,
+		X2: k.cx.x + k.w.x/2,
+		Y2: k.cy.x + k.h.x/2,
+	}
+}
+
+func sq(x float64) float64 { return x * x }
@@ -0,0 +1,261 @@
+// Package tracking 为逐帧检测结果分配持久的跟踪 ID，实现 SORT 与 ByteTrack 两种关联策略：
+// 卡尔曼滤波预测每条轨迹在当前帧的位置，再用匈牙利算法按 IoU 把检测框指派给轨迹
+package tracking
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	MethodByteTrack = "bytetrack"
+	MethodSORT      = "sort"
+)
+
+const (
+	EventNew  = "new"
+	EventLost = "lost"
+)
+
+// Box 是左上角 (X1, Y1) 到右下角 (X2, Y2) 的检测框
+type Box struct {
+	X1, Y1, X2, Y2 float64
+}
+
+func (b Box) cxcywh() (cx, cy, w, h float64) {
+	return (b.X1 + b.X2) / 2, (b.Y1 + b.Y2) / 2, b.X2 - b.X1, b.Y2 - b.Y1
+}
+
+// IoU 返回两个框的交并比
+func (b Box) IoU(o Box) float64 {
+	w := min(b.X2, o.X2) - max(b.X1, o.X1)
+	h := min(b.Y2, o.Y2) - max(b.Y1, o.Y1)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	inter := w * h
+	union := (b.X2-b.X1)*(b.Y2-b.Y1) + (o.X2-o.X1)*(o.Y2-o.Y1) - inter
+	if union <= 0 {
+		return 0
+	}
+	return inter / union
+}
+
+// Config 是跟踪器参数，零值字段使用默认值
+type Config struct {
+	// Method 为 bytetrack（默认）或 sort
+	Method string
+	// HighThresh 是高置信度检测的阈值，只有高置信度检测能创建新轨迹，默认 0.5
+	HighThresh float64
+	// LowThresh 以下的检测被忽略；ByteTrack 用介于两者之间的检测延续已有轨迹，默认 0.1
+	LowThresh float64
+	// MatchIoU 是检测框与预测框关联所需的最小 IoU，默认 0.3
+	MatchIoU float64
+	// MaxLost 是轨迹连续未匹配多少帧后判定丢失，默认 30
+	MaxLost int
+	// CrossClass 为 true 时允许不同类别的检测延续同一轨迹
+	CrossClass bool
+}
+
+// WithDefaults 返回填充了默认值的配置
+func (c Config) WithDefaults() Config {
+	if c.Method == "" {
+		c.Method = MethodByteTrack
+	}
+	if c.HighThresh == 0 {
+		c.HighThresh = 0.5
+	}
+	if c.LowThresh == 0 {
+		c.LowThresh = 0.1
+	}
+	if c.MatchIoU == 0 {
+		c.MatchIoU = 0.3
+	}
+	if c.MaxLost == 0 {
+		c.MaxLost = 30
+	}
+	return c
+}
+
+// Validate 检查填充默认值后的配置
+func (c Config) Validate() error {
+	c = c.WithDefaults()
+	if c.Method != MethodByteTrack && c.Method != MethodSORT {
+		return fmt.Errorf("tracker method must be %s or %s, got %q", MethodByteTrack, MethodSORT, c.Method)
+	}
+	if c.LowThresh < 0 || c.HighThresh > 1 || c.LowThresh > c.HighThresh {
+		return fmt.Errorf("tracker thresholds must satisfy 0 <= low <= high <= 1, got low %g high %g", c.LowThresh, c.HighThresh)
+	}
+	if c.MatchIoU < 0 || c.MatchIoU > 1 {
+		return fmt.Errorf("tracker match IoU must be between 0 and 1, got %g", c.MatchIoU)
+	}
+	if c.MaxLost < 0 {
+		return fmt.Errorf("tracker max lost cannot be negative, got %d", c.MaxLost)
+	}
+	return nil
+}
+
+// Detection 是一帧中的一个检测
+type Detection struct {
+	Class string
+	Conf  float64
+	Box   Box
+}
+
+// Assignment 是检测所属的轨迹；TrackID 为 0 表示该检测没有关联到轨迹
+type Assignment struct {
+	TrackID int64
+	// Age 是轨迹从创建到当前帧经过的帧数，创建的那一帧为 1
+	Age int
+}
+
+// Event 是轨迹的创建或丢失
+type Event struct {
+	Type    string
+	TrackID int64
+	Class   string
+	Age     int
+}
+
+type track struct {
+	id    int64
+	class string
+	kf    kalman
+	start int
+	// lost 是连续未匹配的帧数
+	lost int
+}
+
+// Tracker 保存一路视频的全部轨迹，不是并发安全的，调用方需按帧顺序串行调用 Update
+type Tracker struct {
+	cfg    Config
+	tracks []*track
+	nextID int64
+	frame  int
+}
+
+// New 创建跟踪器，cfg 需先通过 Validate 检查
+func New(cfg Config) *Tracker {
+	return &Tracker{cfg: cfg.WithDefaults()}
+}
+
+// Len 返回当前保留的轨迹数（包括暂时未匹配的轨迹）
+func (t *Tracker) Len() int {
+	return len(t.tracks)
+}
+
+// Update 输入一帧的检测，返回与 dets 一一对应的轨迹指派，以及本帧新建和丢失的轨迹事件
+func (t *Tracker) Update(dets []Detection) ([]Assignment, []Event) {
+	t.frame++
+	for _, tr := range t.tracks {
+		tr.kf.predict()
+	}
+
+	var high, low []int
+	for i, d := range dets {
+		switch {
+		case d.Conf >= t.cfg.HighThresh || t.cfg.Method == MethodSORT && d.Conf >= t.cfg.LowThresh:
+			high = append(high, i)
+		case d.Conf >= t.cfg.LowThresh:
+			low = append(low, i)
+		}
+	}
+
+	matched := make([]*track, len(dets))
+	all := make([]int, len(t.tracks))
+	for i := range all {
+		all[i] = i
+	}
+	rest := t.associate(dets, high, all, matched)
+	if t.cfg.Method == MethodByteTrack {
+		// 第二轮只让上一帧仍被跟踪的轨迹匹配低置信度检测，避免低分误检接续已丢失的轨迹
+		var tracked []int
+		for _, ti := range rest {
+			if t.tracks[ti].lost == 0 {
+				tracked = append(tracked, ti)
+			}
+		}
+		t.associate(dets, low, tracked, matched)
+	}
+
+	var events []Event
+	kept := t.tracks[:0]
+	for _, tr := range t.tracks {
+		if !slices.Contains(matched, tr) {
+			tr.lost++
+			if tr.lost > t.cfg.MaxLost {
+				events = append(events, Event{Type: EventLost, TrackID: tr.id, Class: tr.class, Age: t.frame - tr.start + 1})
+				continue
+			}
+		}
+		kept = append(kept, tr)
+	}
+	t.tracks = kept
+
+	for _, i := range high {
+		if matched[i] != nil {
+			continue
+		}
+		t.nextID++
+		tr := &track{id: t.nextID, class: dets[i].Class, kf: newKalman(dets[i].Box), start: t.frame}
+		t.tracks = append(t.tracks, tr)
+		matched[i] = tr
+		events = append(events, Event{Type: EventNew, TrackID: tr.id, Class: tr.class, Age: 1})
+	}
+
+	assigned := make([]Assignment, len(dets))
+	for i, tr := range matched {
+		if tr != nil {
+			assigned[i] = Assignment{TrackID: tr.id, Age: t.frame - tr.start + 1}
+		}
+	}
+	return assigned, events
+}
+
+// associate 用匈牙利算法把 detIdx 中的检测指派给 trackIdx 中的轨迹，更新匹配轨迹的滤波器，
+// 返回未匹配的轨迹
+func (t *Tracker) associate(dets []Detection, detIdx, trackIdx []int, matched []*track) []int {
+	if len(detIdx) == 0 || len(trackIdx) == 0 {
+		return trackIdx
+	}
+	predicted := make([]Box, len(trackIdx))
+	for j, ti := range trackIdx {
+		predicted[j] = t.tracks[ti].kf.box()
+	}
+	cost := make([][]float64, len(detIdx))
+	for i, di := range detIdx {
+		cost[i] = make([]float64, len(trackIdx))
+		for j, ti := range trackIdx {
+			cost[i][j] = 1 - t.similarity(dets[di], t.tracks[ti], predicted[j])
+		}
+	}
+	used := make([]bool, len(trackIdx))
+	for i, j := range Hungarian(cost) {
+		if j < 0 {
+			continue
+		}
+		if iou := 1 - cost[i][j]; iou <= 0 || iou < t.cfg.MatchIoU {
+			continue
+		}
+		tr := t.tracks[trackIdx[j]]
+		tr.kf.update(dets[detIdx[i]].Box)
+		tr.lost = 0
+		used[j] = true
+		matched[detIdx[i]] = tr
+	}
+	var rest []int
+	for j, ti := range trackIdx {
+		if !used[j] {
+			rest = append(rest, ti)
+		}
+	}
+	return rest
+}
+
+// similarity 是检测与轨迹预测框的 IoU，类别不同且不允许跨类别时为 0
+func (t *Tracker) similarity(d Detection, tr *track, predicted Box) float64 {
+	if !t.cfg.CrossClass && d.Class != tr.class {
+		return 0
+	}
+	return d.Box.IoU(predicted)
+}
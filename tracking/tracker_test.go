@@ -0,0 +1,109 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func box(x, y, w, h float64) Box {
+	return Box{X1: x, Y1: y, X2: x + w, Y2: y + h}
+}
+
+func TestHungarian(t *testing.T) {
+	// 贪心会把第 0 行指派给第 0 列，总代价 1+10；最优为 2+2
+	assert.Equal(t, []int{1, 0}, Hungarian([][]float64{{1, 2}, {2, 10}}))
+	// 行多于列时多出的行不指派
+	assert.Equal(t, []int{-1, 0, 1}, Hungarian([][]float64{{5, 5}, {1, 9}, {9, 1}}))
+	// 列多于行
+	assert.Equal(t, []int{2}, Hungarian([][]float64{{3, 2, 1}}))
+	assert.Nil(t, Hungarian(nil))
+	assert.Equal(t, []int{-1}, Hungarian([][]float64{{}}))
+}
+
+func TestTrackerKeepsIDsForMovingObjects(t *testing.T) {
+	tr := New(Config{})
+	var ids [2]int64
+	for f := range 20 {
+		x := float64(f * 8)
+		// 两个同类目标相向运动，检测顺序每帧交换
+		dets := []Detection{
+			{Class: "person", Conf: 0.9, Box: box(x, 100, 40, 80)},
+			{Class: "person", Conf: 0.9, Box: box(400-x, 300, 40, 80)},
+		}
+		if f%2 == 1 {
+			dets[0], dets[1] = dets[1], dets[0]
+		}
+		got, events := tr.Update(dets)
+		if f%2 == 1 {
+			got[0], got[1] = got[1], got[0]
+		}
+		if f == 0 {
+			assert.Len(t, events, 2)
+			ids = [2]int64{got[0].TrackID, got[1].TrackID}
+			assert.NotEqual(t, ids[0], ids[1])
+			continue
+		}
+		assert.Empty(t, events, "frame %d", f)
+		assert.Equal(t, ids, [2]int64{got[0].TrackID, got[1].TrackID}, "frame %d", f)
+		assert.Equal(t, f+1, got[0].Age)
+	}
+}
+
+func TestTrackerLostAndNew(t *testing.T) {
+	tr := New(Config{MaxLost: 2})
+	got, events := tr.Update([]Detection{{Class: "car", Conf: 0.8, Box: box(0, 0, 50, 50)}})
+	assert.Equal(t, []Event{{Type: EventNew, TrackID: 1, Class: "car", Age: 1}}, events)
+	assert.Equal(t, Assignment{TrackID: 1, Age: 1}, got[0])
+
+	// 丢失的帧数不超过 MaxLost 时轨迹保留，再次出现时沿用原 ID
+	tr.Update(nil)
+	tr.Update(nil)
+	got, events = tr.Update([]Detection{{Class: "car", Conf: 0.8, Box: box(2, 0, 50, 50)}})
+	assert.Empty(t, events)
+	assert.Equal(t, Assignment{TrackID: 1, Age: 4}, got[0])
+
+	tr.Update(nil)
+	tr.Update(nil)
+	_, events = tr.Update(nil)
+	assert.Equal(t, []Event{{Type: EventLost, TrackID: 1, Class: "car", Age: 7}}, events)
+	assert.Zero(t, tr.Len())
+
+	// 不同类别不能延续同一轨迹
+	tr.Update([]Detection{{Class: "car", Conf: 0.8, Box: box(0, 0, 50, 50)}})
+	got, events = tr.Update([]Detection{{Class: "truck", Conf: 0.8, Box: box(0, 0, 50, 50)}})
+	assert.Equal(t, int64(3), got[0].TrackID)
+	assert.Equal(t, []Event{{Type: EventNew, TrackID: 3, Class: "truck", Age: 1}}, events)
+}
+
+func TestByteTrackLowConfidence(t *testing.T) {
+	dets := func(conf float64) []Detection {
+		return []Detection{{Class: "person", Conf: conf, Box: box(10, 10, 40, 80)}}
+	}
+	bt := New(Config{})
+	bt.Update(dets(0.9))
+	// 遮挡导致置信度下降，ByteTrack 用低分检测延续轨迹
+	got, _ := bt.Update(dets(0.2))
+	assert.Equal(t, int64(1), got[0].TrackID)
+	// 低分检测不创建新轨迹
+	got, events := New(Config{}).Update(dets(0.2))
+	assert.Zero(t, got[0].TrackID)
+	assert.Empty(t, events)
+	// 低于 LowThresh 的检测被忽略
+	got, _ = bt.Update(dets(0.05))
+	assert.Zero(t, got[0].TrackID)
+
+	// SORT 不区分高低分，LowThresh 以上的检测都能创建轨迹
+	got, events = New(Config{Method: MethodSORT}).Update(dets(0.2))
+	assert.Equal(t, int64(1), got[0].TrackID)
+	assert.Len(t, events, 1)
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{Method: MethodSORT, HighThresh: 0.6, LowThresh: 0.2}.Validate())
+	assert.ErrorContains(t, Config{Method: "deepsort"}.Validate(), "tracker method")
+	assert.ErrorContains(t, Config{HighThresh: 0.3, LowThresh: 0.4}.Validate(), "thresholds")
+	assert.ErrorContains(t, Config{MatchIoU: 1.5}.Validate(), "match IoU")
+	assert.ErrorContains(t, Config{MaxLost: -1}.Validate(), "max lost")
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// queue 是有界的磁盘队列，每个待投递的事件是目录中的一个 JSON 文件，文件名以纳秒时间戳开头，
+// 按文件名排序即为入队顺序。进程重启后未投递的事件从目录中恢复
+type queue struct {
+	dir  string
+	size int
+
+	mu     sync.Mutex
+	files  []string
+	notify chan struct{}
+}
+
+func openQueue(dir string, size int) (*queue, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create webhook queue %s: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook queue %s: %w", dir, err)
+	}
+	q := &queue{dir: dir, size: size, notify: make(chan struct{}, 1)}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			q.files = append(q.files, e.Name())
+		}
+	}
+	slices.Sort(q.files)
+	return q, nil
+}
+
+// push 写入一个事件，队列已满时丢弃最旧的事件，返回丢弃的个数
+func (q *queue) push(id string, body []byte) (int, error) {
+	name := fmt.Sprintf("%020d-%s.json", time.Now().UnixNano(), id)
+	tmp := filepath.Join(q.dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, filepath.Join(q.dir, name)); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	q.mu.Lock()
+	q.files = append(q.files, name)
+	var dropped []string
+	if over := len(q.files) - q.size; over > 0 {
+		dropped = slices.Clone(q.files[:over])
+		q.files = q.files[over:]
+	}
+	q.mu.Unlock()
+	for _, f := range dropped {
+		os.Remove(filepath.Join(q.dir, f))
+	}
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return len(dropped), nil
+}
+
+// peek 返回最旧的事件文件名
+func (q *queue) peek() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.files) == 0 {
+		return "", false
+	}
+	return q.files[0], true
+}
+
+// has 判断事件是否仍在队列中（可能已因队列满被丢弃）
+func (q *queue) has(name string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Contains(q.files, name)
+}
+
+func (q *queue) remove(name string) {
+	q.mu.Lock()
+	q.files = slices.DeleteFunc(q.files, func(f string) bool { return f == name })
+	q.mu.Unlock()
+	os.Remove(filepath.Join(q.dir, name))
+}
+
+func (q *queue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.files)
+}
+
+// eventID 从文件名中取出事件 ID
+func eventID(name string) string {
+	_, id, _ := strings.Cut(strings.TrimSuffix(name, ".json"), "-")
+	return id
+}
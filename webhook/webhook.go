@@ -0,0 +1,538 @@
+// Package webhook 把检测事件以 JSON POST 推送到业务系统。事件由类别出现、数量阈值或区域事件触发，
+// 先写入每个接收端的有界磁盘队列，再按入队顺序投递，失败时指数退避重试，请求带 HMAC-SHA256 签名头
+package webhook
+
+import (
+	"OnnxDetServer/logger"
+	"OnnxDetServer/monitor"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 触发器类型
+const (
+	TriggerPresence = "presence"
+	TriggerCount    = "count"
+	TriggerZone     = "zone"
+)
+
+// 请求头：接收端用 Secret 对 "时间戳.请求体" 计算 HMAC-SHA256 并与签名头比较
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Trigger 是一个触发条件
+type Trigger struct {
+	// Type 为 presence（指定类别出现）、count（数量达到阈值）或 zone（区域事件）
+	Type string `yaml:"type"`
+	// Classes 限定 presence / count 统计的类别，为空表示全部
+	Classes []string `yaml:"classes"`
+	// Min 是 count 触发器的阈值
+	Min int `yaml:"min"`
+	// Zone 非空时 count 触发器按该区域内的目标数计算
+	Zone string `yaml:"zone"`
+	// Zones 与 Events 过滤 zone 触发器的区域（或线）名称和事件类型，为空表示全部
+	Zones  []string `yaml:"zones"`
+	Events []string `yaml:"events"`
+}
+
+// Config 是 config.yaml 中的一个 webhook 接收端
+type Config struct {
+	Name   string `yaml:"name"`
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"`
+	// Engines 限定触发事件的引擎 ID 或别名，为空表示全部
+	Engines  []string  `yaml:"engines"`
+	Triggers []Trigger `yaml:"triggers"`
+	// QueueDir 为磁盘队列目录，默认 state/webhooks/<name>
+	QueueDir string `yaml:"queueDir"`
+	// QueueSize 为队列中最多保留的事件数，超出时丢弃最旧的事件，默认 1000
+	QueueSize int `yaml:"queueSize"`
+	// MaxRetries 为单个事件失败后的最大重试次数，默认 10
+	MaxRetries int `yaml:"maxRetries"`
+	// InitialBackoffMs 为首次重试的等待时间，之后每次翻倍直到 MaxBackoffMs，默认 500 与 60000
+	InitialBackoffMs int `yaml:"initialBackoffMs"`
+	MaxBackoffMs     int `yaml:"maxBackoffMs"`
+	// TimeoutSeconds 为单次请求超时，默认 5
+	TimeoutSeconds int `yaml:"timeoutSeconds"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.QueueDir == "" {
+		c.QueueDir = filepath.Join("state", "webhooks", c.Name)
+	}
+	if c.QueueSize == 0 {
+		c.QueueSize = 1000
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = 10
+	}
+	if c.InitialBackoffMs == 0 {
+		c.InitialBackoffMs = 500
+	}
+	if c.MaxBackoffMs == 0 {
+		c.MaxBackoffMs = 60000
+	}
+	if c.TimeoutSeconds == 0 {
+		c.TimeoutSeconds = 5
+	}
+	return c
+}
+
+// Validate 检查接收端配置
+func (c Config) Validate() error {
+	if c.Name == "" || strings.ContainsAny(c.Name, `/\:`) || c.Name == "." || c.Name == ".." {
+		return fmt.Errorf("invalid webhook name %q", c.Name)
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook %s: url must be an http or https URL, got %q", c.Name, c.URL)
+	}
+	if c.Secret == "" {
+		return fmt.Errorf("webhook %s: secret is required to sign requests", c.Name)
+	}
+	if len(c.Triggers) == 0 {
+		return fmt.Errorf("webhook %s: at least one trigger is required", c.Name)
+	}
+	for i, t := range c.Triggers {
+		switch t.Type {
+		case TriggerPresence, TriggerZone:
+		case TriggerCount:
+			if t.Min <= 0 {
+				return fmt.Errorf("webhook %s: trigger %d: count trigger needs min > 0", c.Name, i)
+			}
+		default:
+			return fmt.Errorf("webhook %s: trigger %d: unknown type %q", c.Name, i, t.Type)
+		}
+	}
+	if c.QueueSize < 0 || c.MaxRetries < 0 || c.InitialBackoffMs < 0 || c.MaxBackoffMs < 0 || c.TimeoutSeconds < 0 {
+		return fmt.Errorf("webhook %s: queue size, retries, backoff and timeout cannot be negative", c.Name)
+	}
+	return nil
+}
+
+// ZoneEvent 是一帧中的区域事件
+type ZoneEvent struct {
+	Type      string `json:"type"`
+	Zone      string `json:"zone"`
+	TrackID   int64  `json:"trackId,omitempty"`
+	Class     string `json:"class"`
+	Direction string `json:"direction,omitempty"`
+	DwellMs   int64  `json:"dwellMs,omitempty"`
+}
+
+// Frame 是一次推理的结果摘要
+type Frame struct {
+	EngineID  string
+	Alias     string
+	SessionID string
+	// StreamID 标识流式推理的一条流，由服务端生成
+	StreamID   string
+	Counts     map[string]int
+	ZoneCounts map[string]int
+	ZoneEvents []ZoneEvent
+}
+
+// Payload 是 POST 给接收端的 JSON
+type Payload struct {
+	ID         string         `json:"id"`
+	Sink       string         `json:"sink"`
+	Trigger    string         `json:"trigger"`
+	Time       time.Time      `json:"time"`
+	EngineID   string         `json:"engineId"`
+	Alias      string         `json:"alias,omitempty"`
+	SessionID  string         `json:"sessionId,omitempty"`
+	StreamID   string         `json:"streamId,omitempty"`
+	Counts     map[string]int `json:"counts"`
+	ZoneCounts map[string]int `json:"zoneCounts,omitempty"`
+	ZoneEvents []ZoneEvent    `json:"zoneEvents,omitempty"`
+}
+
+// Sign 返回 "sha256=" 加上 HMAC-SHA256(secret, timestamp + "." + body) 的十六进制
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sink 是一个 webhook 接收端
+type Sink struct {
+	cfg    Config
+	client *resty.Client
+	queue  *queue
+	inbox  chan message
+	done   <-chan struct{}
+
+	mu sync.Mutex
+	// active 记录 presence / count 触发器在每路引擎与会话上的当前状态，条件由不满足变为满足时才触发。
+	// 会话删除或过期、引擎销毁时通过 ForgetSession / ForgetEngine 清理
+	active map[activeKey]bool
+}
+
+// inboxSize 是每个接收端等待求值的帧缓冲，已满时丢弃新帧，不阻塞推理
+const inboxSize = 256
+
+// message 是交给接收端协程的一帧结果；forget 为 true 时表示清理 EngineID / SessionID 匹配的触发器状态
+type message struct {
+	frame  Frame
+	forget bool
+}
+
+type activeKey struct {
+	trigger   int
+	engineID  string
+	sessionID string
+	streamID  string
+}
+
+func newSink(cfg Config) (*Sink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg = cfg.withDefaults()
+	q, err := openQueue(cfg.QueueDir, cfg.QueueSize)
+	if err != nil {
+		return nil, err
+	}
+	monitor.WebhookQueueDepth.WithLabelValues(cfg.Name).Set(float64(q.len()))
+	return &Sink{
+		cfg:    cfg,
+		client: resty.New().SetTimeout(time.Duration(cfg.TimeoutSeconds) * time.Second),
+		queue:  q,
+		inbox:  make(chan message, inboxSize),
+		active: make(map[activeKey]bool),
+	}, nil
+}
+
+func selected(filter []string, v string) bool {
+	return len(filter) == 0 || slices.Contains(filter, v)
+}
+
+// fired 判断触发器在该帧是否触发，返回匹配的区域事件
+func (s *Sink) fired(i int, t Trigger, f Frame) (bool, []ZoneEvent) {
+	if t.Type == TriggerZone {
+		var matched []ZoneEvent
+		for _, e := range f.ZoneEvents {
+			if selected(t.Zones, e.Zone) && selected(t.Events, e.Type) {
+				matched = append(matched, e)
+			}
+		}
+		return len(matched) > 0, matched
+	}
+	// presence / count 按会话或流记录状态，没有会话的单次调用来自互不相关的调用方，不参与
+	if f.SessionID == "" && f.StreamID == "" {
+		return false, nil
+	}
+	n := 0
+	if t.Type == TriggerCount && t.Zone != "" {
+		n = f.ZoneCounts[t.Zone]
+	} else {
+		for class, c := range f.Counts {
+			if selected(t.Classes, class) {
+				n += c
+			}
+		}
+	}
+	on := n > 0
+	if t.Type == TriggerCount {
+		on = n >= t.Min
+	}
+	key := activeKey{trigger: i, engineID: f.EngineID, sessionID: f.SessionID, streamID: f.StreamID}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	was := s.active[key]
+	if on {
+		s.active[key] = true
+	} else {
+		delete(s.active, key)
+	}
+	return on && !was, nil
+}
+
+// send 把帧交给接收端协程，缓冲已满时丢弃帧；状态清理不能丢弃，缓冲已满时在后台等待
+func (s *Sink) send(m message) {
+	select {
+	case s.inbox <- m:
+		return
+	default:
+	}
+	if !m.forget {
+		monitor.WebhookDeliveries.WithLabelValues(s.cfg.Name, "overflow").Inc()
+		return
+	}
+	go func() {
+		select {
+		case s.inbox <- m:
+		case <-s.done:
+		}
+	}()
+}
+
+// evaluate 依次处理推理侧交来的帧与状态清理，触发器求值和写磁盘队列都在这里进行，不占用推理调用
+func (s *Sink) evaluate(ctx context.Context) {
+	for {
+		select {
+		case m := <-s.inbox:
+			if m.forget {
+				s.forget(m.frame)
+			} else {
+				s.publish(m.frame)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// forget 清理与 f 的引擎、会话、流匹配的触发器状态，空字段匹配任意值
+func (s *Sink) forget(f Frame) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.active {
+		if (f.EngineID == "" || key.engineID == f.EngineID) && (f.SessionID == "" || key.sessionID == f.SessionID) &&
+			(f.StreamID == "" || key.streamID == f.StreamID) {
+			delete(s.active, key)
+		}
+	}
+}
+
+// publish 对一帧求值所有触发器，把触发的事件写入队列
+func (s *Sink) publish(f Frame) {
+	if !selected(s.cfg.Engines, f.EngineID) && (f.Alias == "" || !selected(s.cfg.Engines, f.Alias)) {
+		return
+	}
+	for i, t := range s.cfg.Triggers {
+		ok, events := s.fired(i, t, f)
+		if !ok {
+			continue
+		}
+		p := Payload{
+			ID:         uuid.NewString(),
+			Sink:       s.cfg.Name,
+			Trigger:    t.Type,
+			Time:       time.Now(),
+			EngineID:   f.EngineID,
+			Alias:      f.Alias,
+			SessionID:  f.SessionID,
+			StreamID:   f.StreamID,
+			Counts:     f.Counts,
+			ZoneCounts: f.ZoneCounts,
+			ZoneEvents: events,
+		}
+		body, err := json.Marshal(p)
+		if err != nil {
+			logger.Log().Error("Failed to encode webhook payload", zap.String("sink", s.cfg.Name), zap.Error(err))
+			continue
+		}
+		dropped, err := s.queue.push(p.ID, body)
+		if err != nil {
+			logger.Log().Error("Failed to queue webhook event", zap.String("sink", s.cfg.Name), zap.Error(err))
+			monitor.WebhookDeliveries.WithLabelValues(s.cfg.Name, "dropped").Inc()
+			continue
+		}
+		if dropped > 0 {
+			logger.Log().Warn("Webhook queue full, dropped oldest events", zap.String("sink", s.cfg.Name), zap.Int("dropped", dropped))
+			monitor.WebhookDeliveries.WithLabelValues(s.cfg.Name, "dropped").Add(float64(dropped))
+		}
+		monitor.WebhookQueueDepth.WithLabelValues(s.cfg.Name).Set(float64(s.queue.len()))
+	}
+}
+
+// post 发送一次带签名的请求，非 2xx 响应视为失败
+func (s *Sink) post(ctx context.Context, id string, body []byte) error {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader(HeaderID, id).
+		SetHeader(HeaderTimestamp, ts).
+		SetHeader(HeaderSignature, Sign(s.cfg.Secret, ts, body)).
+		SetBody(body).
+		Post(s.cfg.URL)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("server returned %s", resp.Status())
+	}
+	return nil
+}
+
+// backoff 返回第 attempt 次重试前的等待时间
+func (s *Sink) backoff(attempt int) time.Duration {
+	d := time.Duration(s.cfg.InitialBackoffMs) * time.Millisecond
+	for range attempt {
+		d *= 2
+		if d >= time.Duration(s.cfg.MaxBackoffMs)*time.Millisecond {
+			return time.Duration(s.cfg.MaxBackoffMs) * time.Millisecond
+		}
+	}
+	return d
+}
+
+// run 按入队顺序投递事件直到 ctx 结束；正在重试的事件会阻塞后续事件以保持顺序，
+// ctx 结束时未投递的事件留在磁盘上，下次启动继续投递
+func (s *Sink) run(ctx context.Context) {
+	for {
+		name, ok := s.queue.peek()
+		if !ok {
+			select {
+			case <-s.queue.notify:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		if !s.deliver(ctx, name) {
+			return
+		}
+		s.queue.remove(name)
+		monitor.WebhookQueueDepth.WithLabelValues(s.cfg.Name).Set(float64(s.queue.len()))
+	}
+}
+
+// deliver 投递一个事件，重试次数用尽或事件已被丢弃时放弃；ctx 结束时返回 false
+func (s *Sink) deliver(ctx context.Context, name string) bool {
+	body, err := os.ReadFile(filepath.Join(s.queue.dir, name))
+	if err != nil {
+		// 队列满时事件可能已被丢弃
+		return true
+	}
+	id := eventID(name)
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err := s.post(ctx, id, body)
+		monitor.WebhookLatency.WithLabelValues(s.cfg.Name).Observe(time.Since(start).Seconds())
+		if err == nil {
+			monitor.WebhookDeliveries.WithLabelValues(s.cfg.Name, "delivered").Inc()
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		if attempt >= s.cfg.MaxRetries {
+			monitor.WebhookDeliveries.WithLabelValues(s.cfg.Name, "failed").Inc()
+			logger.Log().Error("Webhook delivery failed, giving up", zap.String("sink", s.cfg.Name), zap.String("id", id), zap.Int("attempts", attempt+1), zap.Error(err))
+			return true
+		}
+		monitor.WebhookDeliveries.WithLabelValues(s.cfg.Name, "retried").Inc()
+		logger.Log().Warn("Webhook delivery failed, retrying", zap.String("sink", s.cfg.Name), zap.String("id", id), zap.Int("attempt", attempt+1), zap.Error(err))
+		select {
+		case <-time.After(s.backoff(attempt)):
+		case <-ctx.Done():
+			return false
+		}
+		if !s.queue.has(name) {
+			return true
+		}
+	}
+}
+
+var (
+	sinks   []*Sink
+	sinksMu sync.RWMutex
+)
+
+// Start 创建 config.yaml 中配置的接收端并启动投递协程，ctx 结束时停止投递
+func Start(ctx context.Context, cfgs []Config) error {
+	names := make(map[string]bool)
+	for _, c := range cfgs {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if names[c.Name] {
+			return fmt.Errorf("duplicate webhook name %s", c.Name)
+		}
+		names[c.Name] = true
+	}
+	created := make([]*Sink, 0, len(cfgs))
+	for _, c := range cfgs {
+		s, err := newSink(c)
+		if err != nil {
+			return err
+		}
+		created = append(created, s)
+	}
+	for _, s := range created {
+		s.done = ctx.Done()
+		go s.evaluate(ctx)
+		go s.run(ctx)
+		logger.Log().Info("Webhook sink started", zap.String("sink", s.cfg.Name), zap.Int("queued", s.queue.len()))
+	}
+	sinksMu.Lock()
+	sinks = created
+	sinksMu.Unlock()
+	return nil
+}
+
+// Enabled 判断是否配置了接收端，调用方据此跳过构造 Frame
+func Enabled() bool {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	return len(sinks) > 0
+}
+
+// Publish 把一帧结果交给所有接收端，求值与写队列在接收端协程中异步进行，不阻塞调用方
+func Publish(f Frame) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.send(message{frame: f})
+	}
+}
+
+// ForgetSession 在跟踪会话删除或过期后清理该会话的触发器状态
+func ForgetSession(sessionID string) {
+	if sessionID == "" {
+		return
+	}
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.send(message{frame: Frame{SessionID: sessionID}, forget: true})
+	}
+}
+
+// ForgetStream 在流式推理结束后清理该流的触发器状态
+func ForgetStream(streamID string) {
+	if streamID == "" {
+		return
+	}
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.send(message{frame: Frame{StreamID: streamID}, forget: true})
+	}
+}
+
+// ForgetEngine 在引擎销毁后清理该引擎的触发器状态
+func ForgetEngine(engineID string) {
+	if engineID == "" {
+		return
+	}
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.send(message{frame: Frame{EngineID: engineID}, forget: true})
+	}
+}
@@ -0,0 +1,264 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig(t *testing.T, url string, triggers ...Trigger) Config {
+	return Config{Name: "ops", URL: url, Secret: "s3cret", Triggers: triggers, QueueDir: t.TempDir(), InitialBackoffMs: 5, MaxBackoffMs: 20}
+}
+
+func TestTriggers(t *testing.T) {
+	s, err := newSink(testConfig(t, "http://127.0.0.1:1",
+		Trigger{Type: TriggerPresence, Classes: []string{"person"}},
+		Trigger{Type: TriggerCount, Zone: "door", Min: 3},
+		Trigger{Type: TriggerZone, Zones: []string{"gate"}, Events: []string{"cross"}},
+	))
+	if !assert.NoError(t, err) {
+		return
+	}
+	person := Frame{EngineID: "e1", SessionID: "cam1", Counts: map[string]int{"person": 1}}
+	// presence 只在类别从无到有时触发一次，每路引擎与会话分别计算
+	s.publish(person)
+	s.publish(person)
+	assert.Equal(t, 1, s.queue.len())
+	s.publish(Frame{EngineID: "e1", SessionID: "cam1", Counts: map[string]int{"car": 2}})
+	s.publish(person)
+	s.publish(Frame{EngineID: "e2", SessionID: "cam1", Counts: map[string]int{"person": 1}})
+	assert.Equal(t, 3, s.queue.len())
+
+	// 没有会话的单次调用不参与边沿触发，不同的流各自计算
+	s.publish(Frame{EngineID: "e1", Counts: map[string]int{"person": 1}})
+	assert.Equal(t, 3, s.queue.len())
+	s.publish(Frame{EngineID: "e1", StreamID: "st1", Counts: map[string]int{"person": 1}})
+	s.publish(Frame{EngineID: "e1", StreamID: "st2", Counts: map[string]int{"person": 1}})
+	s.publish(Frame{EngineID: "e1", StreamID: "st1", Counts: map[string]int{"person": 1}})
+	assert.Equal(t, 5, s.queue.len())
+
+	s.publish(Frame{EngineID: "e3", SessionID: "cam1", ZoneCounts: map[string]int{"door": 2}})
+	assert.Equal(t, 5, s.queue.len())
+	s.publish(Frame{EngineID: "e3", SessionID: "cam1", ZoneCounts: map[string]int{"door": 3}})
+	assert.Equal(t, 6, s.queue.len())
+
+	s.publish(Frame{EngineID: "e4", ZoneEvents: []ZoneEvent{{Type: "enter", Zone: "gate"}, {Type: "cross", Zone: "door"}}})
+	assert.Equal(t, 6, s.queue.len())
+	s.publish(Frame{EngineID: "e4", ZoneEvents: []ZoneEvent{{Type: "cross", Zone: "gate", TrackID: 9, Class: "car", Direction: "forward"}}})
+	if assert.Equal(t, 7, s.queue.len()) {
+		name := s.queue.files[6]
+		var p Payload
+		b, _ := readQueued(s, name)
+		assert.NoError(t, json.Unmarshal(b, &p))
+		assert.Equal(t, eventID(name), p.ID)
+		assert.Equal(t, TriggerZone, p.Trigger)
+		assert.Equal(t, []ZoneEvent{{Type: "cross", Zone: "gate", TrackID: 9, Class: "car", Direction: "forward"}}, p.ZoneEvents)
+	}
+
+	// 引擎过滤同时匹配 UUID 与别名
+	s.cfg.Engines = []string{"cam-alias"}
+	s.publish(Frame{EngineID: "e5", SessionID: "cam1", Counts: map[string]int{"person": 1}})
+	assert.Equal(t, 7, s.queue.len())
+	s.publish(Frame{EngineID: "e5", Alias: "cam-alias", SessionID: "cam1", Counts: map[string]int{"person": 1}})
+	assert.Equal(t, 8, s.queue.len())
+}
+
+func TestForgetState(t *testing.T) {
+	s, err := newSink(testConfig(t, "http://127.0.0.1:1", Trigger{Type: TriggerPresence}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	person := map[string]int{"person": 1}
+	s.publish(Frame{EngineID: "e1", SessionID: "cam1", Counts: person})
+	s.publish(Frame{EngineID: "e1", SessionID: "cam2", Counts: person})
+	s.publish(Frame{EngineID: "e2", StreamID: "st1", Counts: person})
+	assert.Len(t, s.active, 3)
+
+	// 会话删除后其状态被清理，同一会话再次出现时重新触发
+	s.forget(Frame{SessionID: "cam1"})
+	assert.Len(t, s.active, 2)
+	s.publish(Frame{EngineID: "e1", SessionID: "cam1", Counts: person})
+	assert.Equal(t, 4, s.queue.len())
+
+	s.forget(Frame{EngineID: "e1"})
+	assert.Len(t, s.active, 1)
+	s.forget(Frame{StreamID: "st1"})
+	assert.Empty(t, s.active)
+}
+
+func TestPublishAsync(t *testing.T) {
+	s, err := newSink(testConfig(t, "http://127.0.0.1:1", Trigger{Type: TriggerPresence}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	sinksMu.Lock()
+	sinks = []*Sink{s}
+	sinksMu.Unlock()
+	defer func() {
+		sinksMu.Lock()
+		sinks = nil
+		sinksMu.Unlock()
+	}()
+
+	// 接收端协程未运行时缓冲写满后直接丢弃，Publish 不阻塞也不写磁盘
+	for range inboxSize + 10 {
+		Publish(Frame{EngineID: "e1", SessionID: "cam1", Counts: map[string]int{"person": 1}})
+	}
+	assert.Equal(t, 0, s.queue.len())
+	assert.Len(t, s.inbox, inboxSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.done = ctx.Done()
+	go s.evaluate(ctx)
+	assert.Eventually(t, func() bool { return len(s.inbox) == 0 && s.queue.len() == 1 }, time.Second, 5*time.Millisecond)
+	ForgetSession("cam1")
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.active) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func readQueued(s *Sink, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.queue.dir, name))
+}
+
+func TestQueueBoundAndRecovery(t *testing.T) {
+	cfg := testConfig(t, "http://127.0.0.1:1", Trigger{Type: TriggerZone})
+	cfg.QueueSize = 2
+	s, err := newSink(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, zone := range []string{"a", "b", "c"} {
+		s.publish(Frame{ZoneEvents: []ZoneEvent{{Type: "enter", Zone: zone}}})
+	}
+	// 队列满时丢弃最旧的事件
+	assert.Equal(t, 2, s.queue.len())
+	var p Payload
+	b, _ := readQueued(s, s.queue.files[0])
+	json.Unmarshal(b, &p)
+	assert.Equal(t, "b", p.ZoneEvents[0].Zone)
+
+	// 重新打开时从磁盘恢复未投递的事件
+	reopened, err := newSink(cfg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, s.queue.files, reopened.queue.files)
+	}
+}
+
+func TestDeliveryRetriesAndSignature(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	var received []Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if Sign("s3cret", r.Header.Get(HeaderTimestamp), body) != r.Header.Get(HeaderSignature) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var p Payload
+		json.Unmarshal(body, &p)
+		if p.ID != r.Header.Get(HeaderID) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, p)
+	}))
+	defer srv.Close()
+
+	s, err := newSink(testConfig(t, srv.URL, Trigger{Type: TriggerZone}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+	s.publish(Frame{EngineID: "e1", ZoneEvents: []ZoneEvent{{Type: "enter", Zone: "a"}}})
+	s.publish(Frame{EngineID: "e1", ZoneEvents: []ZoneEvent{{Type: "exit", Zone: "a", DwellMs: 1500}}})
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	// 前两次失败后重试，事件按入队顺序投递
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, "enter", received[0].ZoneEvents[0].Type)
+	assert.Equal(t, "exit", received[1].ZoneEvents[0].Type)
+	mu.Unlock()
+	assert.Eventually(t, func() bool { return s.queue.len() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestDeliveryGivesUp(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	cfg := testConfig(t, srv.URL, Trigger{Type: TriggerZone})
+	cfg.MaxRetries = 2
+	s, err := newSink(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+	s.publish(Frame{ZoneEvents: []ZoneEvent{{Type: "enter", Zone: "a"}}})
+	assert.Eventually(t, func() bool { return s.queue.len() == 0 }, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 3, calls)
+	mu.Unlock()
+}
+
+func TestBackoff(t *testing.T) {
+	s := &Sink{cfg: Config{InitialBackoffMs: 500, MaxBackoffMs: 3000}}
+	assert.Equal(t, 500*time.Millisecond, s.backoff(0))
+	assert.Equal(t, 2*time.Second, s.backoff(2))
+	assert.Equal(t, 3*time.Second, s.backoff(3))
+	assert.Equal(t, 3*time.Second, s.backoff(30))
+}
+
+func TestValidate(t *testing.T) {
+	ok := Config{Name: "ops", URL: "https://hooks.example.com/x", Secret: "s3cret", Triggers: []Trigger{{Type: TriggerPresence}}}
+	assert.NoError(t, ok.Validate())
+	bad := ok
+	bad.Name = "../x"
+	assert.ErrorContains(t, bad.Validate(), "invalid webhook name")
+	bad = ok
+	bad.URL = "ftp://x"
+	assert.ErrorContains(t, bad.Validate(), "http or https")
+	bad = ok
+	bad.Secret = ""
+	assert.ErrorContains(t, bad.Validate(), "secret is required")
+	bad = ok
+	bad.Triggers = []Trigger{{Type: TriggerCount}}
+	assert.ErrorContains(t, bad.Validate(), "min > 0")
+	bad.Triggers = []Trigger{{Type: "motion"}}
+	assert.ErrorContains(t, bad.Validate(), "unknown type")
+	bad.Triggers = nil
+	assert.ErrorContains(t, bad.Validate(), "at least one trigger")
+	assert.ErrorContains(t, Start(context.Background(), []Config{ok, ok}), "duplicate")
+}
@@ -0,0 +1,234 @@
+// Package zones 在检测结果上统计区域规则：多边形区域内的目标计数、有向线的穿越，
+// 以及轨迹在区域内的停留时长。穿越与停留需要轨迹 ID，由 Analyzer 按帧保存状态
+package zones
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	EventEnter = "enter"
+	EventExit  = "exit"
+	EventDwell = "dwell"
+	EventCross = "cross"
+)
+
+// 有向线 A→B 的穿越方向：图像坐标系（y 轴向下）中从 A→B 的左侧穿到右侧为 forward，反之为 backward
+const (
+	Forward  = "forward"
+	Backward = "backward"
+)
+
+type Point struct {
+	X, Y float64
+}
+
+// Zone 是多边形区域，Classes 为空时统计所有类别
+type Zone struct {
+	Name    string
+	Polygon []Point
+	Classes []string
+	// DwellAlert 大于 0 时，轨迹在区域内停留超过该时长时产生一次 dwell 事件
+	DwellAlert time.Duration
+}
+
+// Line 是从 A 指向 B 的有向线段，Classes 为空时统计所有类别
+type Line struct {
+	Name    string
+	A, B    Point
+	Classes []string
+}
+
+// Config 是一组区域与线规则，区域与线的名称不能重复
+type Config struct {
+	Zones []Zone
+	Lines []Line
+}
+
+func (c Config) Validate() error {
+	seen := make(map[string]bool)
+	checkName := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("%s name cannot be empty", kind)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate zone or line name %s", name)
+		}
+		seen[name] = true
+		return nil
+	}
+	for _, z := range c.Zones {
+		if err := checkName("zone", z.Name); err != nil {
+			return err
+		}
+		if len(z.Polygon) < 3 {
+			return fmt.Errorf("zone %s needs at least 3 points, got %d", z.Name, len(z.Polygon))
+		}
+		if z.DwellAlert < 0 {
+			return fmt.Errorf("zone %s dwell alert cannot be negative", z.Name)
+		}
+	}
+	for _, l := range c.Lines {
+		if err := checkName("line", l.Name); err != nil {
+			return err
+		}
+		if l.A == l.B {
+			return fmt.Errorf("line %s needs two distinct points", l.Name)
+		}
+	}
+	return nil
+}
+
+// Contains 用射线法判断点是否在多边形内
+func (z Zone) Contains(p Point) bool {
+	inside := false
+	for i, j := 0, len(z.Polygon)-1; i < len(z.Polygon); j, i = i, i+1 {
+		a, b := z.Polygon[i], z.Polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func accepts(classes []string, class string) bool {
+	return len(classes) == 0 || slices.Contains(classes, class)
+}
+
+// side 返回点在有向线 A→B 的哪一侧，图像坐标系中右侧为正
+func (l Line) side(p Point) float64 {
+	return (l.B.X-l.A.X)*(p.Y-l.A.Y) - (l.B.Y-l.A.Y)*(p.X-l.A.X)
+}
+
+// crossing 返回从 from 移动到 to 时穿越线段的方向，没有穿越时返回空串
+func (l Line) crossing(from, to Point) string {
+	s1, s2 := l.side(from) >= 0, l.side(to) >= 0
+	if s1 == s2 {
+		return ""
+	}
+	// 移动轨迹所在直线必须把线段两端分开，否则是从线段延长线外绕过
+	move := Line{A: from, B: to}
+	if sa, sb := move.side(l.A), move.side(l.B); sa > 0 && sb > 0 || sa < 0 && sb < 0 {
+		return ""
+	}
+	if s2 {
+		return Forward
+	}
+	return Backward
+}
+
+// Object 是一帧中的一个目标，Point 为参与判断的参考点；TrackID 为 0 的目标只参与计数
+type Object struct {
+	TrackID int64
+	Class   string
+	Point   Point
+}
+
+// Count 是一个区域内当前的目标数，Classes 为按类别的细分
+type Count struct {
+	Zone    string
+	Total   int
+	Classes map[string]int
+}
+
+// Count 统计每个区域内的目标数，顺序与 Zones 一致
+func (c Config) Count(objs []Object) []Count {
+	counts := make([]Count, len(c.Zones))
+	for i, z := range c.Zones {
+		counts[i] = Count{Zone: z.Name, Classes: make(map[string]int)}
+		for _, o := range objs {
+			if accepts(z.Classes, o.Class) && z.Contains(o.Point) {
+				counts[i].Total++
+				counts[i].Classes[o.Class]++
+			}
+		}
+	}
+	return counts
+}
+
+// Event 是区域进出、停留超时或穿越线的事件，Name 为区域或线的名称
+type Event struct {
+	Type      string
+	Name      string
+	TrackID   int64
+	Class     string
+	Direction string
+	// Dwell 是 exit 与 dwell 事件中轨迹在区域内已停留的时长
+	Dwell time.Duration
+}
+
+type presence struct {
+	zone  int
+	track int64
+}
+
+type visit struct {
+	since   time.Time
+	class   string
+	alerted bool
+}
+
+// Analyzer 按帧跟踪每条轨迹相对区域与线的状态，不是并发安全的
+type Analyzer struct {
+	cfg    Config
+	last   map[int64]Point
+	inside map[presence]*visit
+}
+
+func NewAnalyzer(cfg Config) *Analyzer {
+	return &Analyzer{cfg: cfg, last: make(map[int64]Point), inside: make(map[presence]*visit)}
+}
+
+// Update 输入时间为 now 的一帧目标以及本帧丢失的轨迹，返回产生的事件。
+// 暂时未出现在本帧中的轨迹保留状态，直到出现在 lost 中才按离开区域处理
+func (a *Analyzer) Update(now time.Time, objs []Object, lost []int64) []Event {
+	var events []Event
+	for _, o := range objs {
+		if o.TrackID == 0 {
+			continue
+		}
+		if prev, ok := a.last[o.TrackID]; ok {
+			for _, l := range a.cfg.Lines {
+				if !accepts(l.Classes, o.Class) {
+					continue
+				}
+				if dir := l.crossing(prev, o.Point); dir != "" {
+					events = append(events, Event{Type: EventCross, Name: l.Name, TrackID: o.TrackID, Class: o.Class, Direction: dir})
+				}
+			}
+		}
+		a.last[o.TrackID] = o.Point
+
+		for i, z := range a.cfg.Zones {
+			if !accepts(z.Classes, o.Class) {
+				continue
+			}
+			key := presence{zone: i, track: o.TrackID}
+			v, was := a.inside[key]
+			switch is := z.Contains(o.Point); {
+			case is && !was:
+				a.inside[key] = &visit{since: now, class: o.Class}
+				events = append(events, Event{Type: EventEnter, Name: z.Name, TrackID: o.TrackID, Class: o.Class})
+			case !is && was:
+				delete(a.inside, key)
+				events = append(events, Event{Type: EventExit, Name: z.Name, TrackID: o.TrackID, Class: o.Class, Dwell: now.Sub(v.since)})
+			case is && z.DwellAlert > 0 && !v.alerted && now.Sub(v.since) >= z.DwellAlert:
+				v.alerted = true
+				events = append(events, Event{Type: EventDwell, Name: z.Name, TrackID: o.TrackID, Class: o.Class, Dwell: now.Sub(v.since)})
+			}
+		}
+	}
+	for _, id := range lost {
+		delete(a.last, id)
+		for i, z := range a.cfg.Zones {
+			key := presence{zone: i, track: id}
+			if v, ok := a.inside[key]; ok {
+				delete(a.inside, key)
+				events = append(events, Event{Type: EventExit, Name: z.Name, TrackID: id, Class: v.class, Dwell: now.Sub(v.since)})
+			}
+		}
+	}
+	return events
+}
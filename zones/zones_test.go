@@ -0,0 +1,81 @@
+package zones
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var square = Zone{Name: "door", Polygon: []Point{{0, 0}, {100, 0}, {100, 100}, {0, 100}}}
+
+func TestContains(t *testing.T) {
+	assert.True(t, square.Contains(Point{50, 50}))
+	assert.False(t, square.Contains(Point{150, 50}))
+	// 凹多边形的缺口不算在区域内
+	u := Zone{Polygon: []Point{{0, 0}, {30, 0}, {30, 70}, {70, 70}, {70, 0}, {100, 0}, {100, 100}, {0, 100}}}
+	assert.True(t, u.Contains(Point{10, 10}))
+	assert.False(t, u.Contains(Point{50, 10}))
+	assert.True(t, u.Contains(Point{50, 90}))
+}
+
+func TestCount(t *testing.T) {
+	cfg := Config{Zones: []Zone{square, {Name: "cars", Polygon: square.Polygon, Classes: []string{"car"}}}}
+	counts := cfg.Count([]Object{
+		{Class: "person", Point: Point{10, 10}},
+		{Class: "car", Point: Point{20, 20}},
+		{Class: "car", Point: Point{200, 20}},
+	})
+	assert.Equal(t, []Count{
+		{Zone: "door", Total: 2, Classes: map[string]int{"person": 1, "car": 1}},
+		{Zone: "cars", Total: 1, Classes: map[string]int{"car": 1}},
+	}, counts)
+}
+
+func TestLineCrossing(t *testing.T) {
+	// 从左到右的水平线，图像坐标系中下方为右侧
+	l := Line{Name: "gate", A: Point{0, 50}, B: Point{100, 50}}
+	assert.Equal(t, Forward, l.crossing(Point{50, 40}, Point{50, 60}))
+	assert.Equal(t, Backward, l.crossing(Point{50, 60}, Point{50, 40}))
+	assert.Empty(t, l.crossing(Point{50, 40}, Point{60, 45}))
+	// 从线段端点外侧绕过不算穿越
+	assert.Empty(t, l.crossing(Point{150, 40}, Point{150, 60}))
+
+	a := NewAnalyzer(Config{Lines: []Line{l, {Name: "cars", A: l.A, B: l.B, Classes: []string{"car"}}}})
+	now := time.Now()
+	assert.Empty(t, a.Update(now, []Object{{TrackID: 1, Class: "person", Point: Point{50, 40}}}, nil))
+	assert.Equal(t, []Event{{Type: EventCross, Name: "gate", TrackID: 1, Class: "person", Direction: Forward}},
+		a.Update(now, []Object{{TrackID: 1, Class: "person", Point: Point{50, 60}}}, nil))
+	// 没有轨迹 ID 的目标不参与穿越判断
+	assert.Empty(t, a.Update(now, []Object{{Class: "person", Point: Point{50, 40}}}, nil))
+}
+
+func TestDwell(t *testing.T) {
+	z := square
+	z.DwellAlert = 5 * time.Second
+	a := NewAnalyzer(Config{Zones: []Zone{z}})
+	t0 := time.Unix(1000, 0)
+	in := []Object{{TrackID: 7, Class: "person", Point: Point{50, 50}}}
+	out := []Object{{TrackID: 7, Class: "person", Point: Point{150, 50}}}
+
+	assert.Equal(t, []Event{{Type: EventEnter, Name: "door", TrackID: 7, Class: "person"}}, a.Update(t0, in, nil))
+	assert.Empty(t, a.Update(t0.Add(3*time.Second), in, nil))
+	assert.Equal(t, []Event{{Type: EventDwell, Name: "door", TrackID: 7, Class: "person", Dwell: 6 * time.Second}}, a.Update(t0.Add(6*time.Second), in, nil))
+	// dwell 事件每次停留只产生一次
+	assert.Empty(t, a.Update(t0.Add(7*time.Second), in, nil))
+	assert.Equal(t, []Event{{Type: EventExit, Name: "door", TrackID: 7, Class: "person", Dwell: 8 * time.Second}}, a.Update(t0.Add(8*time.Second), out, nil))
+
+	// 轨迹在区域内丢失时按离开处理
+	a.Update(t0.Add(10*time.Second), in, nil)
+	assert.Equal(t, []Event{{Type: EventExit, Name: "door", TrackID: 7, Class: "person", Dwell: 2 * time.Second}}, a.Update(t0.Add(12*time.Second), nil, []int64{7}))
+	assert.Empty(t, a.inside)
+	assert.Empty(t, a.last)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{Zones: []Zone{square}, Lines: []Line{{Name: "gate", A: Point{0, 0}, B: Point{1, 1}}}}.Validate())
+	assert.ErrorContains(t, Config{Zones: []Zone{{Name: "a", Polygon: square.Polygon[:2]}}}.Validate(), "at least 3 points")
+	assert.ErrorContains(t, Config{Zones: []Zone{square}, Lines: []Line{{Name: "door", A: Point{0, 0}, B: Point{1, 1}}}}.Validate(), "duplicate")
+	assert.ErrorContains(t, Config{Lines: []Line{{Name: "gate"}}}.Validate(), "distinct points")
+	assert.ErrorContains(t, Config{Lines: []Line{{A: Point{0, 0}, B: Point{1, 1}}}}.Validate(), "name cannot be empty")
+}